@@ -42,13 +42,14 @@ const (
 // BaseApp reflects the ABCI application implementation.
 type BaseApp struct {
 	// initialized on creation
-	Logger     log.Logger
-	name       string               // application name from abci.Info
-	cdc        *wire.Codec          // Amino codec
-	db         dbm.DB               // common DB backend
-	cms        sdk.CommitMultiStore // Main (uncached) state
-	router     Router               // handle any kind of message
-	codespacer *sdk.Codespacer      // handle module codespacing
+	Logger      log.Logger
+	name        string               // application name from abci.Info
+	cdc         *wire.Codec          // Amino codec
+	db          dbm.DB               // common DB backend
+	cms         sdk.CommitMultiStore // Main (uncached) state
+	router      Router               // handle any kind of message
+	queryRouter QueryRouter          // router for redirecting query calls
+	codespacer  *sdk.Codespacer      // handle module codespacing
 
 	// must be set
 	txDecoder   sdk.TxDecoder   // unmarshal []byte into sdk.Tx
@@ -60,6 +61,7 @@ type BaseApp struct {
 	endBlocker       sdk.EndBlocker   // logic to run after all txs, and to determine valset changes
 	addrPeerFilter   sdk.PeerFilter   // filter peers by address and port
 	pubkeyPeerFilter sdk.PeerFilter   // filter peers by public key
+	haltHook         sdk.HaltHook     // stop the node before blocks it must not process
 
 	//--------------------
 	// Volatile
@@ -69,6 +71,8 @@ type BaseApp struct {
 	checkState       *state                  // for CheckTx
 	deliverState     *state                  // for DeliverTx
 	signedValidators []abci.SigningValidator // absent validators from begin block
+	haltReason       string                  // reason of the halt hook to stop the node, if any
+	halted           chan string             // receives the halt reason, for the server to stop the node
 }
 
 var _ abci.Application = (*BaseApp)(nil)
@@ -83,14 +87,16 @@ var _ abci.Application = (*BaseApp)(nil)
 // Accepts variable number of option functions, which act on the BaseApp to set configuration choices
 func NewBaseApp(name string, cdc *wire.Codec, logger log.Logger, db dbm.DB, options ...func(*BaseApp)) *BaseApp {
 	app := &BaseApp{
-		Logger:     logger,
-		name:       name,
-		cdc:        cdc,
-		db:         db,
-		cms:        store.NewCommitMultiStore(db),
-		router:     NewRouter(),
-		codespacer: sdk.NewCodespacer(),
-		txDecoder:  defaultTxDecoder(cdc),
+		Logger:      logger,
+		name:        name,
+		cdc:         cdc,
+		db:          db,
+		cms:         store.NewCommitMultiStore(db),
+		router:      NewRouter(),
+		queryRouter: NewQueryRouter(),
+		codespacer:  sdk.NewCodespacer(),
+		txDecoder:   defaultTxDecoder(cdc),
+		halted:      make(chan string, 1),
 	}
 
 	// Register the undefined & root codespaces, which should not be used by
@@ -142,10 +148,10 @@ func (app *BaseApp) SetTxDecoder(txDecoder sdk.TxDecoder) {
 
 // default custom logic for transaction decoding
 // TODO: remove auth and wire dependencies from baseapp
-//	- move this to auth.DefaultTxDecoder
-//	- set the default here to JSON decode like docs/examples/app1 (it will fail
-//		for multiple messages ;))
-//	- pass a TxDecoder into NewBaseApp, instead of a codec.
+//   - move this to auth.DefaultTxDecoder
+//   - set the default here to JSON decode like docs/examples/app1 (it will fail
+//     for multiple messages ;))
+//   - pass a TxDecoder into NewBaseApp, instead of a codec.
 func defaultTxDecoder(cdc *wire.Codec) sdk.TxDecoder {
 	return func(txBytes []byte) (sdk.Tx, sdk.Error) {
 		var tx = auth.StdTx{}
@@ -183,7 +189,11 @@ func (app *BaseApp) SetAddrPeerFilter(pf sdk.PeerFilter) {
 func (app *BaseApp) SetPubKeyPeerFilter(pf sdk.PeerFilter) {
 	app.pubkeyPeerFilter = pf
 }
-func (app *BaseApp) Router() Router { return app.router }
+func (app *BaseApp) SetHaltHook(hh sdk.HaltHook) {
+	app.haltHook = hh
+}
+func (app *BaseApp) Router() Router           { return app.router }
+func (app *BaseApp) QueryRouter() QueryRouter { return app.queryRouter }
 
 // load latest application version
 func (app *BaseApp) LoadLatestVersion(mainKey sdk.StoreKey) error {
@@ -223,9 +233,37 @@ func (app *BaseApp) initFromStore(mainKey sdk.StoreKey) error {
 		return errors.New("baseapp expects MultiStore with 'main' KVStore")
 	}
 
+	// a restarted node which must not process its next block is stopped by
+	// the server as soon as it is started
+	app.checkHalt(app.LastBlockHeight() + 1)
 	return nil
 }
 
+// Halted returns the channel receiving the reason of the halt hook to stop the
+// node, once. The server stops the node when it is received.
+func (app *BaseApp) Halted() <-chan string {
+	return app.halted
+}
+
+// run the halt hook on the committed state before the block at height,
+// notifying Halted if the node must stop
+func (app *BaseApp) checkHalt(height int64) bool {
+	if app.haltHook == nil {
+		return false
+	}
+	ctx := sdk.NewContext(app.cms.CacheMultiStore(), abci.Header{Height: height - 1}, true, app.Logger)
+	reason := app.haltHook(ctx, height)
+	if reason == "" {
+		return false
+	}
+	app.Logger.Error(fmt.Sprintf("halting the node before height %d: %s", height, reason))
+	if app.haltReason == "" {
+		app.halted <- reason
+	}
+	app.haltReason = reason
+	return true
+}
+
 // NewContext returns a new Context with the correct store, the given header, and nil txBytes.
 func (app *BaseApp) NewContext(isCheckTx bool, header abci.Header) sdk.Context {
 	if isCheckTx {
@@ -338,6 +376,8 @@ func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 		return handleQueryStore(app, path, req)
 	case "p2p":
 		return handleQueryP2P(app, path, req)
+	case "custom":
+		return handleQueryCustom(app, path, req)
 	}
 
 	msg := "unknown query path"
@@ -407,8 +447,42 @@ func handleQueryP2P(app *BaseApp, path []string, req abci.RequestQuery) (res abc
 	return sdk.ErrUnknownRequest(msg).QueryResult()
 }
 
+func handleQueryCustom(app *BaseApp, path []string, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// path[0] should be "custom" because "/custom" prefix is required for keeper queries.
+	// the queryRouter routes using path[1]. For example, in the path "custom/gov/proposal", queryRouter routes using "gov"
+	if len(path) < 2 || path[1] == "" {
+		return sdk.ErrUnknownRequest("No route for custom query specified").QueryResult()
+	}
+	querier := app.queryRouter.Route(path[1])
+	if querier == nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("no custom querier found for route %s", path[1])).QueryResult()
+	}
+
+	// run the querier against a cache of the latest committed state, so that
+	// it can never write to it
+	ctx := sdk.NewContext(app.cms.CacheMultiStore(), app.checkState.ctx.BlockHeader(), true, app.Logger)
+
+	// Passes the rest of the path as an argument to the querier.
+	// For example, in the path "custom/gov/proposal/test", the gov querier gets []string{"proposal", "test"} as the path
+	resBytes, err := querier(ctx, path[2:], req)
+	if err != nil {
+		return err.QueryResult()
+	}
+	return abci.ResponseQuery{
+		Code:  uint32(sdk.ABCICodeOK),
+		Value: resBytes,
+	}
+}
+
 // BeginBlock implements the ABCI application interface.
 func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
+	if app.haltReason != "" {
+		// never process a block the halt hook stopped the node before, no
+		// state has been written for it yet. Tendermint stops its consensus
+		// on the panic if the server has not stopped the node yet.
+		panic(fmt.Sprintf("node halted before height %d: %s", req.Header.Height, app.haltReason))
+	}
+
 	if app.cms.TracingEnabled() {
 		app.cms.ResetTraceContext()
 		app.cms.WithTracingContext(sdk.TraceContext(
@@ -688,6 +762,9 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	// Empty the Deliver state
 	app.deliverState = nil
 
+	// have the server stop the node if it must not process the next block
+	app.checkHalt(header.Height + 1)
+
 	return abci.ResponseCommit{
 		Data: commitID.Hash,
 	}
@@ -139,6 +139,50 @@ func testLoadVersionHelper(t *testing.T, app *BaseApp, expectedHeight int64, exp
 	require.Equal(t, expectedID, lastID)
 }
 
+// Test that the halt hook runs on the committed state before each block.
+func TestHaltHook(t *testing.T) {
+	logger := defaultLogger()
+	db := dbm.NewMemDB()
+	name := t.Name()
+	capKey := sdk.NewKVStoreKey("main")
+
+	var heights []int64
+	hook := func(ctx sdk.Context, height int64) string {
+		require.Equal(t, height-1, ctx.BlockHeight())
+		heights = append(heights, height)
+		if height > 3 {
+			return "stop"
+		}
+		return ""
+	}
+
+	app := NewBaseApp(name, nil, logger, db)
+	app.SetHaltHook(hook)
+	app.MountStoresIAVL(capKey)
+	err := app.LoadLatestVersion(capKey)
+	require.Nil(t, err)
+
+	for height := int64(1); height <= 2; height++ {
+		app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: height}})
+		app.Commit()
+	}
+	require.Equal(t, []int64{1, 2, 3}, heights)
+	require.Equal(t, "", app.haltReason)
+
+	// a restarted node checks its next block when loading the store
+	app = NewBaseApp(name, nil, logger, db)
+	app.SetHaltHook(hook)
+	app.MountStoresIAVL(capKey)
+	err = app.LoadLatestVersion(capKey)
+	require.Nil(t, err)
+	require.Equal(t, []int64{1, 2, 3, 3}, heights)
+
+	// the server is notified of the halt, and the next block is never processed
+	require.True(t, app.checkHalt(4))
+	require.Equal(t, "stop", <-app.Halted())
+	require.Panics(t, func() { app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 3}}) })
+}
+
 func TestOptionFunction(t *testing.T) {
 	logger := defaultLogger()
 	db := dbm.NewMemDB()
@@ -833,6 +877,55 @@ func TestQuery(t *testing.T) {
 	require.Equal(t, value, res.Value)
 }
 
+// Test custom queries routed to a module querier
+func TestCustomQuery(t *testing.T) {
+	app, capKey, _ := setupBaseApp(t)
+
+	key, value := []byte("hello"), []byte("goodbye")
+	app.Router().AddRoute(typeMsgCounter, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		store := ctx.KVStore(capKey)
+		store.Set(key, value)
+		return sdk.Result{}
+	})
+	app.QueryRouter().AddRoute("test", func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) != 1 || path[0] != "key" {
+			return nil, sdk.ErrUnknownRequest("unknown test query endpoint")
+		}
+		return ctx.KVStore(capKey).Get(req.Data), nil
+	})
+	app.InitChain(abci.RequestInitChain{})
+
+	query := abci.RequestQuery{
+		Path: "/custom/test/key",
+		Data: key,
+	}
+
+	// query is empty before we do anything
+	res := app.Query(query)
+	require.True(t, res.IsOK())
+	require.Equal(t, 0, len(res.Value))
+
+	// query is still empty after a DeliverTx before we commit
+	app.BeginBlock(abci.RequestBeginBlock{})
+	resTx := app.Deliver(newTxCounter(0, 0))
+	require.True(t, resTx.IsOK(), fmt.Sprintf("%v", resTx))
+	res = app.Query(query)
+	require.Equal(t, 0, len(res.Value))
+
+	// query returns correct value after Commit
+	app.Commit()
+	res = app.Query(query)
+	require.Equal(t, value, res.Value)
+
+	// unknown routes and endpoints are rejected
+	res = app.Query(abci.RequestQuery{Path: "/custom/unknown/key"})
+	require.False(t, res.IsOK())
+	res = app.Query(abci.RequestQuery{Path: "/custom/test/unknown"})
+	require.False(t, res.IsOK())
+	res = app.Query(abci.RequestQuery{Path: "/custom"})
+	require.False(t, res.IsOK())
+}
+
 // Test p2p filter queries
 func TestP2PQuery(t *testing.T) {
 	app, _, _ := setupBaseApp(t)
@@ -0,0 +1,41 @@
+package baseapp
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// QueryRouter provides queryables for each query path.
+type QueryRouter interface {
+	AddRoute(r string, h sdk.Querier) (rtr QueryRouter)
+	Route(path string) (h sdk.Querier)
+}
+
+type queryrouter struct {
+	routes map[string]sdk.Querier
+}
+
+// nolint
+// NewQueryRouter - create new queryrouter
+// TODO either make Function unexported or make return type (queryrouter) Exported
+func NewQueryRouter() *queryrouter {
+	return &queryrouter{
+		routes: map[string]sdk.Querier{},
+	}
+}
+
+// AddRoute - Adds an sdk.Querier to the route provided. Panics on duplicate
+func (rtr *queryrouter) AddRoute(r string, q sdk.Querier) QueryRouter {
+	if !isAlpha(r) {
+		panic("route expressions can only contain alphabet characters")
+	}
+	if rtr.routes[r] != nil {
+		panic("route has already been initialized")
+	}
+	rtr.routes[r] = q
+	return rtr
+}
+
+// Route - returns the sdk.Querier for a given query route path
+func (rtr *queryrouter) Route(path string) (h sdk.Querier) {
+	return rtr.routes[path]
+}
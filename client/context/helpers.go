@@ -65,6 +65,12 @@ func (ctx CoreContext) Query(path string) (res []byte, err error) {
 	return ctx.query(path, nil)
 }
 
+// QueryWithData queries the given path, passing data to the application,
+// e.g. a custom "custom/<module>/<endpoint>" query with JSON encoded params
+func (ctx CoreContext) QueryWithData(path string, data []byte) (res []byte, err error) {
+	return ctx.query(path, data)
+}
+
 // QueryStore from Tendermint with the provided key and storename
 func (ctx CoreContext) QueryStore(key cmn.HexBytes, storeName string) (res []byte, err error) {
 	return ctx.queryStore(key, storeName, "key")
@@ -129,35 +135,45 @@ func (ctx CoreContext) GetFromAddress() (from sdk.AccAddress, err error) {
 	return sdk.AccAddress(info.GetPubKey().Address()), nil
 }
 
-// sign and build the transaction from the msg
-func (ctx CoreContext) SignAndBuild(name, passphrase string, msgs []sdk.Msg, cdc *wire.Codec) ([]byte, error) {
-
-	// build the Sign Messsage from the Standard Message
+// build the Sign Message of the msgs from the chain ID, account number,
+// sequence, memo, fee and gas of the context, to be signed offline or by
+// SignAndBuild
+func (ctx CoreContext) BuildSignMsg(msgs []sdk.Msg) (auth.StdSignMsg, error) {
 	chainID := ctx.ChainID
 	if chainID == "" {
-		return nil, errors.Errorf("chain ID required but not specified")
+		return auth.StdSignMsg{}, errors.Errorf("chain ID required but not specified")
 	}
-	accnum := ctx.AccountNumber
-	sequence := ctx.Sequence
-	memo := ctx.Memo
 
 	fee := sdk.Coin{}
 	if ctx.Fee != "" {
 		parsedFee, err := sdk.ParseCoin(ctx.Fee)
 		if err != nil {
-			return nil, err
+			return auth.StdSignMsg{}, err
 		}
 		fee = parsedFee
 	}
 
-	signMsg := auth.StdSignMsg{
+	return auth.StdSignMsg{
 		ChainID:       chainID,
-		AccountNumber: accnum,
-		Sequence:      sequence,
+		AccountNumber: ctx.AccountNumber,
+		Sequence:      ctx.Sequence,
 		Msgs:          msgs,
-		Memo:          memo,
+		Memo:          ctx.Memo,
 		Fee:           auth.NewStdFee(ctx.Gas, fee), // TODO run simulate to estimate gas?
+	}, nil
+}
+
+// sign and build the transaction from the msg
+func (ctx CoreContext) SignAndBuild(name, passphrase string, msgs []sdk.Msg, cdc *wire.Codec) ([]byte, error) {
+
+	// build the Sign Messsage from the Standard Message
+	signMsg, err := ctx.BuildSignMsg(msgs)
+	if err != nil {
+		return nil, err
 	}
+	accnum := signMsg.AccountNumber
+	sequence := signMsg.Sequence
+	memo := signMsg.Memo
 
 	keybase, err := keys.GetKeyBase()
 	if err != nil {
@@ -17,14 +17,21 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
 	"github.com/cosmos/cosmos-sdk/x/gov"
 	"github.com/cosmos/cosmos-sdk/x/ibc"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/slashing"
 	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/cosmos/cosmos-sdk/x/upgrade"
 )
 
 const (
 	appName = "GaiaApp"
+
+	// ParamsUpgradeName - the name of the upgrade plan moving the module
+	// params to the params store
+	ParamsUpgradeName = "params-store-v1"
 )
 
 // default home directories for expected binaries
@@ -47,13 +54,19 @@ type GaiaApp struct {
 	keyGov           *sdk.KVStoreKey
 	keyFeeCollection *sdk.KVStoreKey
 	keySentinel      *sdk.KVStoreKey
+	keyParams        *sdk.KVStoreKey
+	keyUpgrade       *sdk.KVStoreKey
+	keyDistr         *sdk.KVStoreKey
 
 	// Manage getting and setting accounts
 	accountMapper       auth.AccountMapper
 	feeCollectionKeeper auth.FeeCollectionKeeper
 	coinKeeper          bank.Keeper
 	ibcMapper           ibc.Mapper
+	paramsKeeper        params.Keeper
+	upgradeKeeper       upgrade.Keeper
 	stakeKeeper         stake.Keeper
+	distrKeeper         distr.Keeper
 	slashingKeeper      slashing.Keeper
 	govKeeper           gov.Keeper
 	sentinelKeeper      sent.Keeper
@@ -77,6 +90,9 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, baseAppOptio
 		keyGov:           sdk.NewKVStoreKey("gov"),
 		keyFeeCollection: sdk.NewKVStoreKey("fee"),
 		keySentinel:      sdk.NewKVStoreKey("sentinel"),
+		keyParams:        sdk.NewKVStoreKey("params"),
+		keyUpgrade:       sdk.NewKVStoreKey("upgrade"),
+		keyDistr:         sdk.NewKVStoreKey("distr"),
 	}
 
 	// define the accountMapper
@@ -88,27 +104,58 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, baseAppOptio
 
 	// add handlers
 	app.coinKeeper = bank.NewKeeper(app.accountMapper)
-	app.ibcMapper = ibc.NewMapper(app.cdc, app.keyIBC, app.RegisterCodespace(ibc.DefaultCodespace))
-	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.RegisterCodespace(stake.DefaultCodespace))
-	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.keySlashing, app.stakeKeeper, app.RegisterCodespace(slashing.DefaultCodespace))
-	app.govKeeper = gov.NewKeeper(app.cdc, app.keyGov, app.coinKeeper, app.stakeKeeper, app.RegisterCodespace(gov.DefaultCodespace))
 	app.feeCollectionKeeper = auth.NewFeeCollectionKeeper(app.cdc, app.keyFeeCollection)
-	app.sentinelKeeper = sent.NewKeeper(app.cdc, app.keySentinel, app.coinKeeper, app.accountMapper, app.RegisterCodespace(stake.DefaultCodespace))
+	app.ibcMapper = ibc.NewMapper(app.cdc, app.keyIBC, app.RegisterCodespace(ibc.DefaultCodespace))
+	app.paramsKeeper = params.NewKeeper(app.cdc, app.keyParams)
+	app.upgradeKeeper = upgrade.NewKeeper(app.cdc, app.keyUpgrade, app.RegisterCodespace(upgrade.DefaultCodespace))
+	// register the upgrade handlers, running the store migrations, of the upgrades this binary implements here
+	app.upgradeKeeper.SetUpgradeHandler(ParamsUpgradeName, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.migrateParams(ctx)
+	})
+	app.upgradeKeeper.SetUpgradeHandler(sent.StoreUpgradeName, func(ctx sdk.Context, plan upgrade.Plan) {
+		// the sentinel migrations read the params
+		app.migrateParams(ctx)
+		sent.MigrateStore(ctx, app.sentinelKeeper)
+	})
+	app.stakeKeeper = stake.NewKeeper(app.cdc, app.keyStake, app.coinKeeper, app.paramsKeeper.Subspace(stake.DefaultParamspace, stake.ParamTypeTable()), app.RegisterCodespace(stake.DefaultCodespace))
+	app.distrKeeper = distr.NewKeeper(app.cdc, app.keyDistr, app.paramsKeeper.Subspace(distr.DefaultParamspace, distr.ParamTypeTable()), app.coinKeeper, app.stakeKeeper, app.feeCollectionKeeper, app.RegisterCodespace(distr.DefaultCodespace))
+
+	// settle the rewards of a delegation before its shares change, the
+	// keepers below must be built with the hooked stake keeper
+	app.stakeKeeper = app.stakeKeeper.SetHooks(app.distrKeeper.Hooks())
+	app.slashingKeeper = slashing.NewKeeper(app.cdc, app.keySlashing, app.stakeKeeper, app.paramsKeeper.Subspace(slashing.DefaultParamspace, slashing.ParamTypeTable()), app.RegisterCodespace(slashing.DefaultCodespace))
+	app.sentinelKeeper = sent.NewKeeper(app.cdc, app.keySentinel, app.coinKeeper, app.accountMapper, app.paramsKeeper.Subspace(sent.DefaultParamspace, sent.ParamTypeTable()), app.RegisterCodespace(stake.DefaultCodespace))
+
+	// register the handlers of the module proposal contents
+	govRouter := gov.NewProposalRouter().
+		AddRoute(upgrade.GovRoute, upgrade.NewProposalHandler(app.upgradeKeeper)).
+		AddRoute(sent.GovRoute, sent.NewGovProposalHandler(app.sentinelKeeper))
+	app.govKeeper = gov.NewKeeper(app.cdc, app.keyGov, app.paramsKeeper, app.paramsKeeper.Subspace(gov.DefaultParamspace, gov.ParamTypeTable()), app.coinKeeper, app.stakeKeeper, govRouter, app.RegisterCodespace(gov.DefaultCodespace))
+
 	// register message routes
 	app.Router().
 		AddRoute("bank", bank.NewHandler(app.coinKeeper)).
 		AddRoute("ibc", ibc.NewHandler(app.ibcMapper, app.coinKeeper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper)).
 		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper)).
+		AddRoute("distr", distr.NewHandler(app.distrKeeper)).
 		AddRoute("gov", gov.NewHandler(app.govKeeper)).
 		AddRoute("sentinel", sent.NewHandler(app.sentinelKeeper))
 
+	// register custom query routes
+	app.QueryRouter().
+		AddRoute("gov", gov.NewQuerier(app.govKeeper)).
+		AddRoute("stake", stake.NewQuerier(app.stakeKeeper, app.cdc)).
+		AddRoute("distr", distr.NewQuerier(app.distrKeeper)).
+		AddRoute("sentinel", sent.NewQuerier(app.sentinelKeeper))
+
 	// initialize BaseApp
 	app.SetInitChainer(app.initChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
 	app.SetEndBlocker(app.EndBlocker)
+	app.SetHaltHook(upgrade.NewHaltHook(app.upgradeKeeper))
 	app.SetAnteHandler(auth.NewAnteHandler(app.accountMapper, app.feeCollectionKeeper))
-	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake, app.keySlashing, app.keyGov, app.keyFeeCollection, app.keySentinel)
+	app.MountStoresIAVL(app.keyMain, app.keyAccount, app.keyIBC, app.keyStake, app.keySlashing, app.keyGov, app.keyFeeCollection, app.keySentinel, app.keyParams, app.keyUpgrade, app.keyDistr)
 	err := app.LoadLatestVersion(app.keyMain)
 	if err != nil {
 		cmn.Exit(err.Error())
@@ -124,7 +171,9 @@ func MakeCodec() *wire.Codec {
 	bank.RegisterWire(cdc)
 	stake.RegisterWire(cdc)
 	slashing.RegisterWire(cdc)
+	distr.RegisterWire(cdc)
 	gov.RegisterWire(cdc)
+	upgrade.RegisterWire(cdc)
 	auth.RegisterWire(cdc)
 	sdk.RegisterWire(cdc)
 	wire.RegisterCrypto(cdc)
@@ -132,9 +181,25 @@ func MakeCodec() *wire.Codec {
 	return cdc
 }
 
+// seed the params store with the module params kept elsewhere or hard coded
+// before they became governable, keeping the params already set
+func (app *GaiaApp) migrateParams(ctx sdk.Context) {
+	app.stakeKeeper.MigrateParams(ctx)
+	app.slashingKeeper.MigrateParams(ctx)
+	app.distrKeeper.MigrateParams(ctx)
+	app.govKeeper.MigrateParams(ctx)
+	app.sentinelKeeper.MigrateParams(ctx)
+}
+
 // application updates every end block
 func (app *GaiaApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
-	tags := slashing.BeginBlocker(ctx, req, app.slashingKeeper)
+	// apply the upgrade scheduled at this height before anything else
+	tags := upgrade.BeginBlocker(ctx, app.upgradeKeeper)
+
+	// pay the fees of the last block to the validators which signed it
+	distr.BeginBlocker(ctx, req, app.distrKeeper)
+
+	tags = tags.AppendTags(slashing.BeginBlocker(ctx, req, app.slashingKeeper))
 
 	return abci.ResponseBeginBlock{
 		Tags: tags.ToKVPairs(),
@@ -148,6 +213,9 @@ func (app *GaiaApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.R
 
 	tags, _ := gov.EndBlocker(ctx, app.govKeeper)
 
+	// settle the sentinel sessions past the session timeout
+	tags = tags.AppendTags(sent.EndBlocker(ctx, app.sentinelKeeper))
+
 	return abci.ResponseEndBlock{
 		ValidatorUpdates: validatorUpdates,
 		Tags:             tags,
@@ -166,6 +234,13 @@ func (app *GaiaApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 		// return sdk.ErrGenesisParse("").TraceCause(err, "")
 	}
 
+	// default the empty module sections, then check the whole state
+	genesisState.setDefaults()
+	err = GaiaValidateGenesisState(genesisState)
+	if err != nil {
+		panic(err) // TODO https://github.com/cosmos/cosmos-sdk/issues/468
+	}
+
 	// load the accounts
 	for _, gacc := range genesisState.Accounts {
 		acc := gacc.ToAccount()
@@ -180,7 +255,23 @@ func (app *GaiaApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 		// return sdk.ErrGenesisParse("").TraceCause(err, "")
 	}
 
-	gov.InitGenesis(ctx, app.govKeeper, gov.DefaultGenesisState())
+	distr.InitGenesis(ctx, app.distrKeeper, genesisState.DistrData)
+
+	slashing.InitGenesis(ctx, app.slashingKeeper, genesisState.SlashingData)
+
+	gov.InitGenesis(ctx, app.govKeeper, genesisState.GovData)
+
+	// load the registered dVPN nodes, master nodes and open sessions
+	err = sent.InitGenesis(ctx, app.sentinelKeeper, genesisState.SentinelData)
+	if err != nil {
+		panic(err) // TODO https://github.com/cosmos/cosmos-sdk/issues/468
+	}
+
+	// schedule the upgrade plan carried over by an exported genesis
+	err = upgrade.InitGenesis(ctx, app.upgradeKeeper, genesisState.UpgradeData)
+	if err != nil {
+		panic(err) // TODO https://github.com/cosmos/cosmos-sdk/issues/468
+	}
 
 	return abci.ResponseInitChain{}
 }
@@ -199,8 +290,13 @@ func (app *GaiaApp) ExportAppStateAndValidators() (appState json.RawMessage, val
 	app.accountMapper.IterateAccounts(ctx, appendAccount)
 
 	genState := GenesisState{
-		Accounts:  accounts,
-		StakeData: stake.WriteGenesis(ctx, app.stakeKeeper),
+		Accounts:     accounts,
+		StakeData:    stake.WriteGenesis(ctx, app.stakeKeeper),
+		SlashingData: slashing.WriteGenesis(ctx, app.slashingKeeper),
+		GovData:      gov.WriteGenesis(ctx, app.govKeeper),
+		DistrData:    distr.WriteGenesis(ctx, app.distrKeeper),
+		SentinelData: sent.WriteGenesis(ctx, app.sentinelKeeper),
+		UpgradeData:  upgrade.WriteGenesis(ctx, app.upgradeKeeper),
 	}
 	appState, err = wire.MarshalJSONIndent(app.cdc, genState)
 	if err != nil {
@@ -3,6 +3,8 @@ package app
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 
 	"github.com/spf13/pflag"
 	"github.com/tendermint/tendermint/crypto"
@@ -13,7 +15,12 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/sentinel"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
 	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/cosmos/cosmos-sdk/x/upgrade"
 )
 
 var (
@@ -24,8 +31,61 @@ var (
 
 // State to Unmarshal
 type GenesisState struct {
-	Accounts  []GenesisAccount   `json:"accounts"`
-	StakeData stake.GenesisState `json:"stake"`
+	Accounts     []GenesisAccount      `json:"accounts"`
+	StakeData    stake.GenesisState    `json:"stake"`
+	SlashingData slashing.GenesisState `json:"slashing"`
+	GovData      gov.GenesisState      `json:"gov"`
+	DistrData    distr.GenesisState    `json:"distr"`
+	SentinelData sentinel.GenesisState `json:"sentinel"`
+	UpgradeData  upgrade.GenesisState  `json:"upgrade"`
+}
+
+// set the module sections left empty in the genesis file to the default
+// genesis state of their module
+func (genesisState *GenesisState) setDefaults() {
+	if reflect.DeepEqual(genesisState.StakeData, stake.GenesisState{}) {
+		genesisState.StakeData = stake.DefaultGenesisState()
+	}
+	if reflect.DeepEqual(genesisState.SlashingData, slashing.GenesisState{}) {
+		genesisState.SlashingData = slashing.DefaultGenesisState()
+	}
+	if reflect.DeepEqual(genesisState.GovData, gov.GenesisState{}) {
+		genesisState.GovData = gov.DefaultGenesisState()
+	}
+	if reflect.DeepEqual(genesisState.DistrData, distr.GenesisState{}) {
+		genesisState.DistrData = distr.DefaultGenesisState()
+	}
+	if reflect.DeepEqual(genesisState.SentinelData, sentinel.GenesisState{}) {
+		genesisState.SentinelData = sentinel.DefaultGenesisState()
+	}
+}
+
+// GaiaValidateGenesisState ensures that the genesis state obeys the expected
+// invariants: no account is given twice and the state of every module is
+// valid
+func GaiaValidateGenesisState(genesisState GenesisState) error {
+	addresses := make(map[string]bool, len(genesisState.Accounts))
+	for _, acc := range genesisState.Accounts {
+		if addresses[acc.Address.String()] {
+			return fmt.Errorf("duplicate genesis account %s", acc.Address)
+		}
+		addresses[acc.Address.String()] = true
+	}
+	validators := []func() error{
+		func() error { return stake.ValidateGenesis(genesisState.StakeData) },
+		func() error { return slashing.ValidateGenesis(genesisState.SlashingData) },
+		func() error { return gov.ValidateGenesis(genesisState.GovData) },
+		func() error { return distr.ValidateGenesis(genesisState.DistrData) },
+		func() error { return sentinel.ValidateGenesis(genesisState.SentinelData) },
+		func() error { return upgrade.ValidateGenesis(genesisState.UpgradeData) },
+	}
+	for _, validate := range validators {
+		err := validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GenesisAccount doesn't need pubkey or sequence
@@ -189,9 +249,15 @@ func GaiaAppGenState(cdc *wire.Codec, appGenTxs []json.RawMessage) (genesisState
 
 	// create the final app state
 	genesisState = GenesisState{
-		Accounts:  genaccs,
-		StakeData: stakeData,
+		Accounts:     genaccs,
+		StakeData:    stakeData,
+		SlashingData: slashing.DefaultGenesisState(),
+		GovData:      gov.DefaultGenesisState(),
+		DistrData:    distr.DefaultGenesisState(),
+		SentinelData: sentinel.DefaultGenesisState(),
+		UpgradeData:  upgrade.DefaultGenesisState(),
 	}
+	err = GaiaValidateGenesisState(genesisState)
 	return
 }
 
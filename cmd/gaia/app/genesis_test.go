@@ -5,6 +5,9 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/cosmos/cosmos-sdk/x/upgrade"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 )
@@ -34,3 +37,28 @@ func TestGaiaAppGenState(t *testing.T) {
 	// TODO test with both one and two genesis transactions:
 	// TODO        correct: genesis account created, canididates created, pool token variance
 }
+
+func TestGaiaValidateGenesisState(t *testing.T) {
+	addr := sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())
+	authAcc := auth.NewBaseAccountWithAddress(addr)
+	genesisState := GenesisState{Accounts: []GenesisAccount{NewGenesisAccount(&authAcc)}}
+
+	// empty module sections get the default genesis of their module
+	genesisState.setDefaults()
+	require.Equal(t, gov.DefaultGenesisState(), genesisState.GovData)
+	require.Nil(t, GaiaValidateGenesisState(genesisState))
+
+	// accounts are only given once
+	duplicated := genesisState
+	duplicated.Accounts = append(duplicated.Accounts, NewGenesisAccount(&authAcc))
+	require.NotNil(t, GaiaValidateGenesisState(duplicated))
+
+	// module sections are validated
+	invalid := genesisState
+	invalid.SlashingData = slashing.DefaultGenesisState()
+	invalid.SlashingData.Params.SignedBlocksWindow = 0
+	require.NotNil(t, GaiaValidateGenesisState(invalid))
+	invalid = genesisState
+	invalid.UpgradeData = upgrade.NewGenesisState(upgrade.NewPlan("", 10, ""))
+	require.NotNil(t, GaiaValidateGenesisState(invalid))
+}
@@ -13,8 +13,10 @@ import (
 	"github.com/cosmos/cosmos-sdk/version"
 	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
 	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
+	distrcmd "github.com/cosmos/cosmos-sdk/x/fee_distribution/client/cli"
 	govcmd "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
 	ibccmd "github.com/cosmos/cosmos-sdk/x/ibc/client/cli"
+	sentinelcmd "github.com/cosmos/cosmos-sdk/x/sentinel/client/cli"
 	slashingcmd "github.com/cosmos/cosmos-sdk/x/slashing/client/cli"
 	stakecmd "github.com/cosmos/cosmos-sdk/x/stake/client/cli"
 
@@ -103,6 +105,26 @@ func main() {
 		stakeCmd,
 	)
 
+	//Add distribution commands
+	distrCmd := &cobra.Command{
+		Use:   "distr",
+		Short: "Fee distribution subcommands",
+	}
+	distrCmd.AddCommand(
+		client.GetCommands(
+			distrcmd.GetCmdQueryRewards("distr", cdc),
+			distrcmd.GetCmdQueryCommission("distr", cdc),
+			distrcmd.GetCmdQueryPool("distr", cdc),
+		)...)
+	distrCmd.AddCommand(
+		client.PostCommands(
+			distrcmd.GetCmdWithdrawRewards(cdc),
+			distrcmd.GetCmdWithdrawCommission(cdc),
+		)...)
+	rootCmd.AddCommand(
+		distrCmd,
+	)
+
 	//Add stake commands
 	govCmd := &cobra.Command{
 		Use:   "gov",
@@ -124,6 +146,44 @@ func main() {
 		govCmd,
 	)
 
+	//Add sentinel commands
+	sentinelCmd := &cobra.Command{
+		Use:   "sentinel",
+		Short: "Sentinel dVPN marketplace subcommands",
+	}
+	sentinelCmd.AddCommand(
+		client.GetCommands(
+			sentinelcmd.GetCmdQueryNode("sentinel", cdc),
+			sentinelcmd.GetCmdQueryNodes("sentinel", cdc),
+			sentinelcmd.GetCmdQueryNodeHistory("sentinel", cdc),
+			sentinelcmd.GetCmdQuerySessions("sentinel", cdc),
+			sentinelcmd.GetCmdQueryMasterNode("sentinel", cdc),
+			sentinelcmd.GetCmdQueryDispute("sentinel", cdc),
+			sentinelcmd.GetCmdQueryPlans("sentinel", cdc),
+			sentinelcmd.GetCmdQuerySubscription("sentinel", cdc),
+			sentinelcmd.GetCmdQueryProposal("sentinel", cdc),
+			sentinelcmd.GetCmdQueryProposals("sentinel", cdc),
+		)...)
+	sentinelCmd.AddCommand(
+		client.PostCommands(
+			sentinelcmd.GetCmdRegisterVpnService(cdc),
+			sentinelcmd.GetCmdUpdateVpnService(cdc),
+			sentinelcmd.GetCmdRegisterMasterNode(cdc),
+			sentinelcmd.GetCmdDeleteVpnService(cdc),
+			sentinelcmd.GetCmdDeleteMasterNode(cdc),
+			sentinelcmd.GetCmdRateNode(cdc),
+			sentinelcmd.GetCmdNodeHeartbeat(cdc),
+			sentinelcmd.GetCmdOpenDispute(cdc),
+			sentinelcmd.GetCmdDisputeVerdict(cdc),
+			sentinelcmd.GetCmdAddPlan(cdc),
+			sentinelcmd.GetCmdSubscribe(cdc),
+			sentinelcmd.GetCmdSubmitProposal(cdc),
+			sentinelcmd.GetCmdVote(cdc),
+		)...)
+	rootCmd.AddCommand(
+		sentinelCmd,
+	)
+
 	//Add auth and bank commands
 	rootCmd.AddCommand(
 		client.GetCommands(
@@ -53,6 +53,11 @@ func (v Validator) GetMoniker() string {
 	return ""
 }
 
+// Implements sdk.Validator
+func (v Validator) GetCommission() sdk.Rat {
+	return sdk.ZeroRat()
+}
+
 // Implements sdk.Validator
 type ValidatorSet struct {
 	Validators []Validator
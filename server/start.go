@@ -1,11 +1,16 @@
 package server
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/tendermint/tendermint/abci/server"
+	abci "github.com/tendermint/tendermint/abci/types"
 
 	tcmd "github.com/tendermint/tendermint/cmd/tendermint/commands"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -73,15 +78,8 @@ func startStandAlone(ctx *Context, appCreator AppCreator) error {
 		cmn.Exit(err.Error())
 	}
 
-	// wait forever
-	cmn.TrapSignal(func() {
-		// cleanup
-		err = svr.Stop()
-		if err != nil {
-			cmn.Exit(err.Error())
-		}
-	})
-	return nil
+	// run until the app halts or the process is interrupted
+	return waitForHalt(ctx, app, svr.Stop)
 }
 
 func startInProcess(ctx *Context, appCreator AppCreator) (*node.Node, error) {
@@ -113,7 +111,35 @@ func startInProcess(ctx *Context, appCreator AppCreator) (*node.Node, error) {
 		return nil, err
 	}
 
-	// trap signal (run forever)
-	tmNode.RunForever()
-	return tmNode, nil
+	// run until the app halts or the process is interrupted
+	return tmNode, waitForHalt(ctx, app, tmNode.Stop)
+}
+
+// Halter is implemented by the apps which may ask the server to stop the
+// node, such as the apps built on BaseApp with a halt hook
+type Halter interface {
+	Halted() <-chan string
+}
+
+// block until the app halts or the process is interrupted, then stop the
+// service. An error is returned if the app halted.
+func waitForHalt(ctx *Context, app abci.Application, stop func() error) error {
+	var halted <-chan string
+	if h, ok := app.(Halter); ok {
+		halted = h.Halted()
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case reason := <-halted:
+		ctx.Logger.Error("halting the node", "reason", reason)
+		err := stop()
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("node halted: %s", reason)
+	case <-interrupted:
+		return stop()
+	}
 }
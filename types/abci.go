@@ -13,3 +13,7 @@ type EndBlocker func(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBloc
 
 // respond to p2p filtering queries from Tendermint
 type PeerFilter func(info string) abci.ResponseQuery
+
+// decide, on the committed state, whether the node must stop before processing
+// the block at height, returning the reason to stop or an empty string
+type HaltHook func(ctx Context, height int64) (reason string)
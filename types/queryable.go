@@ -0,0 +1,9 @@
+package types
+
+import (
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Querier defines a function type that a module keeper can expose to answer
+// custom queries. The path excludes the "custom/<route>" prefix.
+type Querier = func(ctx Context, path []string, req abci.RequestQuery) (res []byte, err Error)
@@ -45,6 +45,7 @@ type Validator interface {
 	GetPower() Rat            // validation power
 	GetDelegatorShares() Rat  // Total out standing delegator shares
 	GetBondHeight() int64     // height in which the validator became active
+	GetCommission() Rat       // fraction of the fees earned which is kept by the validator
 }
 
 // validator which fulfills abci validator interface for use in Tendermint
@@ -92,4 +93,16 @@ type DelegationSet interface {
 	//   execute func for each validator
 	IterateDelegations(ctx Context, delegator AccAddress,
 		fn func(index int64, delegation Delegation) (stop bool))
+
+	Delegation(Context, AccAddress, AccAddress) Delegation // get a particular delegation by delegator and validator owner AccAddress
+}
+
+//_______________________________________________________________________________
+
+// event hooks for the staking module, letting other modules act on changes
+// of the staking state
+type StakingHooks interface {
+	// called before a delegation is created or its shares are modified,
+	// including when the delegation is about to be removed
+	BeforeDelegationSharesModified(ctx Context, delegator AccAddress, validator AccAddress)
 }
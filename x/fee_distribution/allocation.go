@@ -0,0 +1,79 @@
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// distribution begin block functionality
+func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k Keeper) {
+	k.AllocateFees(ctx, req.Validators)
+}
+
+// allocate the fees collected in the previous block to the validators which
+// signed it, in proportion of their power. A fraction of the fees is set
+// aside in the reserve pool, and each validator keeps its commission from
+// the remainder before the rest is credited to its delegators.
+func (k Keeper) AllocateFees(ctx sdk.Context, signingValidators []abci.SigningValidator) {
+	type signer struct {
+		validator sdk.Validator
+		power     int64
+	}
+
+	var signers []signer
+	totalPower := int64(0)
+	for _, signingValidator := range signingValidators {
+		if !signingValidator.SignedLastBlock {
+			continue
+		}
+		pubkey, err := tmtypes.PB2TM.PubKey(signingValidator.Validator.PubKey)
+		if err != nil {
+			panic(err)
+		}
+		validator := k.vs.ValidatorByPubKey(ctx, pubkey)
+		if validator == nil || signingValidator.Validator.Power <= 0 {
+			continue
+		}
+		signers = append(signers, signer{validator, signingValidator.Validator.Power})
+		totalPower += signingValidator.Validator.Power
+	}
+
+	// keep the fees for the next block if nobody can receive them
+	if totalPower == 0 {
+		return
+	}
+
+	collected := NewRatCoins(k.fck.GetCollectedFees(ctx))
+	if collected.IsZero() {
+		return
+	}
+	k.fck.ClearCollectedFees(ctx)
+
+	params := k.GetParams(ctx)
+	reserve := collected.MulRat(params.ReservePoolFee)
+	toValidators := collected.Minus(reserve)
+
+	allocated := RatCoins{}
+	for _, s := range signers {
+		rewards := toValidators.MulRat(sdk.NewRat(s.power, totalPower))
+		allocated = allocated.Plus(rewards)
+
+		vdi := k.GetValidatorDistInfo(ctx, s.validator.GetOwner())
+		shares := s.validator.GetDelegatorShares()
+		if shares.IsZero() {
+			vdi.Commission = vdi.Commission.Plus(rewards)
+		} else {
+			commission := rewards.MulRat(s.validator.GetCommission())
+			vdi.Commission = vdi.Commission.Plus(commission)
+			delegatorRewards := rewards.Minus(commission)
+			vdi.RewardsPerShare = vdi.RewardsPerShare.Plus(delegatorRewards.QuoRat(shares))
+		}
+		k.SetValidatorDistInfo(ctx, vdi)
+	}
+
+	// the rounding remainder goes to the reserve pool as well
+	pool := k.GetPool(ctx)
+	pool.FeeReservePool = pool.FeeReservePool.Plus(collected.Minus(allocated))
+	k.SetPool(ctx, pool)
+}
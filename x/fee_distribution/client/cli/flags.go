@@ -0,0 +1,7 @@
+package cli
+
+// nolint
+const (
+	FlagAddressValidator = "address-validator"
+	FlagAddressDelegator = "address-delegator"
+)
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
+)
+
+// get the command to query the rewards of a delegator
+func GetCmdQueryRewards(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rewards",
+		Short: "Query the rewards of a delegator not yet withdrawn, from a single validator or from all of them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			delegatorAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddressDelegator))
+			if err != nil {
+				return err
+			}
+			params := distr.QueryDelegationRewardsParams{
+				DelegatorAddr: delegatorAddr,
+			}
+			if viper.GetString(FlagAddressValidator) != "" {
+				params.ValidatorAddr, err = sdk.AccAddressFromBech32(viper.GetString(FlagAddressValidator))
+				if err != nil {
+					return err
+				}
+			}
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryDelegationRewards), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(FlagAddressDelegator, "", "bech address of the delegator")
+	cmd.Flags().String(FlagAddressValidator, "", "bech address of the validator, all validators if empty")
+	return cmd
+}
+
+// get the command to query the commission of a validator
+func GetCmdQueryCommission(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commission [validator-address]",
+		Short: "Query the commission of a validator not yet withdrawn",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			validatorAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(distr.QueryValidatorCommissionParams{ValidatorAddr: validatorAddr})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryValidatorCommission), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// get the command to query the distribution pool
+func GetCmdQueryPool(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pool",
+		Short: "Query the fee distribution pool",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryPool), nil)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+	return cmd
+}
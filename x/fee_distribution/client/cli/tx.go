@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
+)
+
+// create withdraw rewards command
+func GetCmdWithdrawRewards(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-rewards",
+		Short: "withdraw the delegation rewards, from a single validator or from all of them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			delegatorAddr, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			var msg sdk.Msg
+			if viper.GetString(FlagAddressValidator) == "" {
+				msg = distr.NewMsgWithdrawDelegatorRewardsAll(delegatorAddr)
+			} else {
+				validatorAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddressValidator))
+				if err != nil {
+					return err
+				}
+				msg = distr.NewMsgWithdrawDelegatorReward(delegatorAddr, validatorAddr)
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			err = ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+			if err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(FlagAddressValidator, "", "bech address of the validator to withdraw from, all validators if empty")
+	return cmd
+}
+
+// create withdraw commission command
+func GetCmdWithdrawCommission(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-commission",
+		Short: "withdraw the commission earned by the validator of the sender",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			validatorAddr, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			msg := distr.NewMsgWithdrawValidatorCommission(validatorAddr)
+
+			// build and sign the transaction, then broadcast to Tendermint
+			err = ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+			if err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
+)
+
+func registerQueryRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
+	r.HandleFunc(
+		"/distr/delegators/{delegator}/rewards",
+		delegatorRewardsHandlerFn(ctx, "distr", cdc),
+	).Methods("GET")
+	r.HandleFunc(
+		"/distr/validators/{validator}/commission",
+		validatorCommissionHandlerFn(ctx, "distr", cdc),
+	).Methods("GET")
+	r.HandleFunc(
+		"/distr/pool",
+		poolHandlerFn(ctx, "distr", cdc),
+	).Methods("GET")
+}
+
+// http request handler to query the rewards of a delegator, optionally from
+// a single validator given by the "validator" query parameter
+func delegatorRewardsHandlerFn(ctx context.CoreContext, storeName string, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// read parameters
+		vars := mux.Vars(r)
+		delegatorAddr, err := sdk.AccAddressFromBech32(vars["delegator"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		params := distr.QueryDelegationRewardsParams{
+			DelegatorAddr: delegatorAddr,
+		}
+		if bech32validator := r.URL.Query().Get("validator"); bech32validator != "" {
+			params.ValidatorAddr, err = sdk.AccAddressFromBech32(bech32validator)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+		}
+
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryDelegationRewards), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query rewards. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+// http request handler to query the commission of a validator
+func validatorCommissionHandlerFn(ctx context.CoreContext, storeName string, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// read parameters
+		vars := mux.Vars(r)
+		validatorAddr, err := sdk.AccAddressFromBech32(vars["validator"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		bz, err := cdc.MarshalJSON(distr.QueryValidatorCommissionParams{ValidatorAddr: validatorAddr})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryValidatorCommission), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query commission. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+// http request handler to query the distribution pool
+func poolHandlerFn(ctx context.CoreContext, storeName string, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, distr.QueryPool), nil)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query pool. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	distr "github.com/cosmos/cosmos-sdk/x/fee_distribution"
+)
+
+func registerTxRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, kb keys.Keybase) {
+	r.HandleFunc(
+		"/distr/withdraw_rewards",
+		withdrawRewardsRequestHandlerFn(cdc, kb, ctx),
+	).Methods("POST")
+	r.HandleFunc(
+		"/distr/withdraw_commission",
+		withdrawCommissionRequestHandlerFn(cdc, kb, ctx),
+	).Methods("POST")
+}
+
+// Withdraw TX body, the account of the key is the delegator or the validator
+// owner. Without a validator address, the rewards of all the delegations of
+// the account are withdrawn.
+type WithdrawBody struct {
+	LocalAccountName string `json:"name"`
+	Password         string `json:"password"`
+	ChainID          string `json:"chain_id"`
+	AccountNumber    int64  `json:"account_number"`
+	Sequence         int64  `json:"sequence"`
+	Gas              int64  `json:"gas"`
+	ValidatorAddr    string `json:"validator_addr"`
+}
+
+func withdrawRewardsRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, addr, ok := readWithdrawBody(w, r, kb)
+		if !ok {
+			return
+		}
+
+		var msg sdk.Msg
+		if m.ValidatorAddr == "" {
+			msg = distr.NewMsgWithdrawDelegatorRewardsAll(addr)
+		} else {
+			validatorAddr, err := sdk.AccAddressFromBech32(m.ValidatorAddr)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("Couldn't decode validator. Error: %s", err.Error())))
+				return
+			}
+			msg = distr.NewMsgWithdrawDelegatorReward(addr, validatorAddr)
+		}
+
+		signAndBroadcast(w, ctx, cdc, m, msg)
+	}
+}
+
+func withdrawCommissionRequestHandlerFn(cdc *wire.Codec, kb keys.Keybase, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, addr, ok := readWithdrawBody(w, r, kb)
+		if !ok {
+			return
+		}
+
+		msg := distr.NewMsgWithdrawValidatorCommission(addr)
+		signAndBroadcast(w, ctx, cdc, m, msg)
+	}
+}
+
+// read the withdraw body and the address of its key, writing the error
+// response on failure
+func readWithdrawBody(w http.ResponseWriter, r *http.Request, kb keys.Keybase) (m WithdrawBody, addr sdk.AccAddress, ok bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return m, nil, false
+	}
+	err = json.Unmarshal(body, &m)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return m, nil, false
+	}
+
+	info, err := kb.Get(m.LocalAccountName)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return m, nil, false
+	}
+	return m, sdk.AccAddress(info.GetPubKey().Address()), true
+}
+
+func signAndBroadcast(w http.ResponseWriter, ctx context.CoreContext, cdc *wire.Codec, m WithdrawBody, msg sdk.Msg) {
+	ctx = ctx.WithGas(m.Gas)
+	ctx = ctx.WithChainID(m.ChainID)
+	ctx = ctx.WithAccountNumber(m.AccountNumber)
+	ctx = ctx.WithSequence(m.Sequence)
+
+	txBytes, err := ctx.SignAndBuild(m.LocalAccountName, m.Password, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	res, err := ctx.BroadcastTx(txBytes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	output, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Write(output)
+}
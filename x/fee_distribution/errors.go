@@ -0,0 +1,31 @@
+// nolint
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Local code type
+type CodeType = sdk.CodeType
+
+const (
+	// Default distribution codespace
+	DefaultCodespace sdk.CodespaceType = 12
+
+	CodeInvalidInput CodeType = 101
+	CodeNoValidator  CodeType = 102
+	CodeNoDelegation CodeType = 103
+)
+
+func ErrNilDelegatorAddr(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput, "delegator address is nil")
+}
+func ErrNilValidatorAddr(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput, "validator address is nil")
+}
+func ErrNoValidator(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeNoValidator, "validator does not exist for that address")
+}
+func ErrNoDelegation(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeNoDelegation, "no delegation for this (address, validator) pair")
+}
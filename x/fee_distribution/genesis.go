@@ -0,0 +1,62 @@
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState - all distribution state that must be provided at genesis
+type GenesisState struct {
+	Params              Params               `json:"params"`
+	Pool                Pool                 `json:"pool"`
+	ValidatorDistInfos  []ValidatorDistInfo  `json:"validator_dist_infos"`
+	DelegationDistInfos []DelegationDistInfo `json:"delegation_dist_infos"`
+}
+
+func NewGenesisState(params Params, pool Pool, vdis []ValidatorDistInfo, ddis []DelegationDistInfo) GenesisState {
+	return GenesisState{
+		Params:              params,
+		Pool:                pool,
+		ValidatorDistInfos:  vdis,
+		DelegationDistInfos: ddis,
+	}
+}
+
+// get raw genesis raw message for testing
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Params: DefaultParams(),
+		Pool:   InitialPool(),
+	}
+}
+
+// ValidateGenesis checks the genesis parameters
+func ValidateGenesis(data GenesisState) error {
+	return validateParams(data.Params)
+}
+
+// InitGenesis - store genesis parameters and distribution state
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	keeper.SetParams(ctx, data.Params)
+	keeper.SetPool(ctx, data.Pool)
+	for _, vdi := range data.ValidatorDistInfos {
+		keeper.SetValidatorDistInfo(ctx, vdi)
+	}
+	for _, ddi := range data.DelegationDistInfos {
+		keeper.SetDelegationDistInfo(ctx, ddi)
+	}
+}
+
+// WriteGenesis - output genesis parameters and distribution state
+func WriteGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	var vdis []ValidatorDistInfo
+	keeper.IterateValidatorDistInfos(ctx, func(vdi ValidatorDistInfo) (stop bool) {
+		vdis = append(vdis, vdi)
+		return false
+	})
+	var ddis []DelegationDistInfo
+	keeper.IterateDelegationDistInfos(ctx, func(ddi DelegationDistInfo) (stop bool) {
+		ddis = append(ddis, ddi)
+		return false
+	})
+	return NewGenesisState(keeper.GetParams(ctx), keeper.GetPool(ctx), vdis, ddis)
+}
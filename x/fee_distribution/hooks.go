@@ -0,0 +1,32 @@
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Hooks - the staking hooks of the distribution keeper
+type Hooks struct {
+	k Keeper
+}
+
+var _ sdk.StakingHooks = Hooks{}
+
+// Hooks returns the staking hooks to register with the stake keeper
+func (k Keeper) Hooks() Hooks {
+	return Hooks{k}
+}
+
+// withdraw the rewards of a delegation before its shares change, as the
+// rewards accumulated so far are owed for the previous shares. A new
+// delegation starts accumulating from the current rewards per share.
+func (h Hooks) BeforeDelegationSharesModified(ctx sdk.Context, delegatorAddr, validatorAddr sdk.AccAddress) {
+	if h.k.ds.Delegation(ctx, delegatorAddr, validatorAddr) == nil {
+		vdi := h.k.GetValidatorDistInfo(ctx, validatorAddr)
+		h.k.SetDelegationDistInfo(ctx, NewDelegationDistInfo(delegatorAddr, validatorAddr, vdi.RewardsPerShare))
+		return
+	}
+	_, err := h.k.WithdrawDelegationRewards(ctx, delegatorAddr, validatorAddr)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -1,91 +1,220 @@
-package stake
-
-//// keeper of the staking store
-//type Keeper struct {
-//storeKey   sdk.StoreKey
-//cdc        *wire.Codec
-//coinKeeper bank.Keeper
-
-//// codespace
-//codespace sdk.CodespaceType
-//}
-
-//func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
-//keeper := Keeper{
-//storeKey:   key,
-//cdc:        cdc,
-//coinKeeper: ck,
-//codespace:  codespace,
-//}
-//return keeper
-//}
-
-////_________________________________________________________________________
-
-//// cummulative power of the non-absent prevotes
-//func (k Keeper) GetTotalPrecommitVotingPower(ctx sdk.Context) sdk.Rat {
-//store := ctx.KVStore(k.storeKey)
-
-//// get absent prevote indexes
-//absents := ctx.AbsentValidators()
-
-//TotalPower := sdk.ZeroRat()
-//i := int32(0)
-//iterator := store.SubspaceIterator(ValidatorsBondedKey)
-//for ; iterator.Valid(); iterator.Next() {
-
-//skip := false
-//for j, absentIndex := range absents {
-//if absentIndex > i {
-//break
-//}
-
-//// if non-voting validator found, skip adding its power
-//if absentIndex == i {
-//absents = append(absents[:j], absents[j+1:]...) // won't need again
-//skip = true
-//break
-//}
-//}
-//if skip {
-//continue
-//}
-
-//bz := iterator.Value()
-//var validator Validator
-//k.cdc.MustUnmarshalBinary(bz, &validator)
-//TotalPower = TotalPower.Add(validator.Power)
-//i++
-//}
-//iterator.Close()
-//return TotalPower
-//}
-
-////_______________________________________________________________________
-
-//// XXX TODO trim functionality
-
-//// retrieve all the power changes which occur after a height
-//func (k Keeper) GetPowerChangesAfterHeight(ctx sdk.Context, earliestHeight int64) (pcs []PowerChange) {
-//store := ctx.KVStore(k.storeKey)
-
-//iterator := store.SubspaceIterator(PowerChangeKey) //smallest to largest
-//for ; iterator.Valid(); iterator.Next() {
-//pcBytes := iterator.Value()
-//var pc PowerChange
-//k.cdc.MustUnmarshalBinary(pcBytes, &pc)
-//if pc.Height < earliestHeight {
-//break
-//}
-//pcs = append(pcs, pc)
-//}
-//iterator.Close()
-//return
-//}
-
-//// set a power change
-//func (k Keeper) setPowerChange(ctx sdk.Context, pc PowerChange) {
-//store := ctx.KVStore(k.storeKey)
-//b := k.cdc.MustMarshalBinary(pc)
-//store.Set(GetPowerChangeKey(pc.Height), b)
-//}
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// Keeper of the fee distribution store
+type Keeper struct {
+	storeKey   sdk.StoreKey
+	cdc        *wire.Codec
+	paramSpace params.Subspace
+	ck         bank.Keeper
+	ds         sdk.DelegationSet
+	vs         sdk.ValidatorSet
+	fck        auth.FeeCollectionKeeper
+
+	// codespace
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper creates a fee distribution keeper
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, paramSpace params.Subspace, ck bank.Keeper,
+	ds sdk.DelegationSet, fck auth.FeeCollectionKeeper, codespace sdk.CodespaceType) Keeper {
+
+	keeper := Keeper{
+		storeKey:   key,
+		cdc:        cdc,
+		paramSpace: paramSpace,
+		ck:         ck,
+		ds:         ds,
+		vs:         ds.GetValidatorSet(),
+		fck:        fck,
+		codespace:  codespace,
+	}
+	return keeper
+}
+
+//_______________________________________________________________________
+
+// load the global distribution pool
+func (k Keeper) GetPool(ctx sdk.Context) (pool Pool) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(PoolKey)
+	if b == nil {
+		return InitialPool()
+	}
+	k.cdc.MustUnmarshalBinary(b, &pool)
+	return
+}
+
+// set the global distribution pool
+func (k Keeper) SetPool(ctx sdk.Context, pool Pool) {
+	store := ctx.KVStore(k.storeKey)
+	b := k.cdc.MustMarshalBinary(pool)
+	store.Set(PoolKey, b)
+}
+
+// load the distribution info of a validator, empty if none has been
+// accumulated yet
+func (k Keeper) GetValidatorDistInfo(ctx sdk.Context, validatorAddr sdk.AccAddress) (vdi ValidatorDistInfo) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(GetValidatorDistInfoKey(validatorAddr))
+	if b == nil {
+		return NewValidatorDistInfo(validatorAddr)
+	}
+	k.cdc.MustUnmarshalBinary(b, &vdi)
+	return
+}
+
+// set the distribution info of a validator
+func (k Keeper) SetValidatorDistInfo(ctx sdk.Context, vdi ValidatorDistInfo) {
+	store := ctx.KVStore(k.storeKey)
+	b := k.cdc.MustMarshalBinary(vdi)
+	store.Set(GetValidatorDistInfoKey(vdi.ValidatorAddr), b)
+}
+
+// iterate over the distribution infos of all the validators
+func (k Keeper) IterateValidatorDistInfos(ctx sdk.Context, fn func(vdi ValidatorDistInfo) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorDistInfoKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var vdi ValidatorDistInfo
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &vdi)
+		if fn(vdi) {
+			break
+		}
+	}
+}
+
+// load the distribution info of a delegation
+func (k Keeper) GetDelegationDistInfo(ctx sdk.Context, delegatorAddr, validatorAddr sdk.AccAddress) (ddi DelegationDistInfo, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(GetDelegationDistInfoKey(delegatorAddr, validatorAddr))
+	if b == nil {
+		return ddi, false
+	}
+	k.cdc.MustUnmarshalBinary(b, &ddi)
+	return ddi, true
+}
+
+// set the distribution info of a delegation
+func (k Keeper) SetDelegationDistInfo(ctx sdk.Context, ddi DelegationDistInfo) {
+	store := ctx.KVStore(k.storeKey)
+	b := k.cdc.MustMarshalBinary(ddi)
+	store.Set(GetDelegationDistInfoKey(ddi.DelegatorAddr, ddi.ValidatorAddr), b)
+}
+
+// iterate over the distribution infos of all the delegations
+func (k Keeper) IterateDelegationDistInfos(ctx sdk.Context, fn func(ddi DelegationDistInfo) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, DelegationDistInfoKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var ddi DelegationDistInfo
+		k.cdc.MustUnmarshalBinary(iterator.Value(), &ddi)
+		if fn(ddi) {
+			break
+		}
+	}
+}
+
+//_______________________________________________________________________
+
+// get the rewards of a delegation which have not been withdrawn yet
+func (k Keeper) GetDelegationRewards(ctx sdk.Context, delegatorAddr, validatorAddr sdk.AccAddress) (RatCoins, sdk.Error) {
+	delegation := k.ds.Delegation(ctx, delegatorAddr, validatorAddr)
+	if delegation == nil {
+		return nil, ErrNoDelegation(k.codespace)
+	}
+	vdi := k.GetValidatorDistInfo(ctx, validatorAddr)
+
+	// delegations without a distribution info have been set at genesis, as the
+	// staking hooks create one for every other delegation, and are owed all
+	// the rewards accumulated since
+	adjustment := RatCoins{}
+	ddi, found := k.GetDelegationDistInfo(ctx, delegatorAddr, validatorAddr)
+	if found {
+		adjustment = ddi.Adjustment
+	}
+	return vdi.RewardsPerShare.Minus(adjustment).MulRat(delegation.GetBondShares()), nil
+}
+
+// withdraw the rewards of a delegation, paying them to the delegator
+func (k Keeper) WithdrawDelegationRewards(ctx sdk.Context, delegatorAddr, validatorAddr sdk.AccAddress) (sdk.Coins, sdk.Error) {
+	rewards, err := k.GetDelegationRewards(ctx, delegatorAddr, validatorAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// all rewards accumulated so far are accounted for
+	vdi := k.GetValidatorDistInfo(ctx, validatorAddr)
+	k.SetDelegationDistInfo(ctx, NewDelegationDistInfo(delegatorAddr, validatorAddr, vdi.RewardsPerShare))
+
+	return k.payOut(ctx, delegatorAddr, rewards)
+}
+
+// withdraw the rewards of all the delegations of a delegator
+func (k Keeper) WithdrawDelegationRewardsAll(ctx sdk.Context, delegatorAddr sdk.AccAddress) (withdrawn sdk.Coins, err sdk.Error) {
+	var validatorAddrs []sdk.AccAddress
+	k.ds.IterateDelegations(ctx, delegatorAddr, func(_ int64, delegation sdk.Delegation) (stop bool) {
+		validatorAddrs = append(validatorAddrs, delegation.GetValidator())
+		return false
+	})
+
+	withdrawn = sdk.Coins{}
+	for _, validatorAddr := range validatorAddrs {
+		coins, err := k.WithdrawDelegationRewards(ctx, delegatorAddr, validatorAddr)
+		if err != nil {
+			return nil, err
+		}
+		withdrawn = withdrawn.Plus(coins)
+	}
+	return withdrawn, nil
+}
+
+// withdraw the commission of a validator, paying it to the validator owner.
+// The fractional change is kept as commission until the next withdrawal.
+func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, validatorAddr sdk.AccAddress) (sdk.Coins, sdk.Error) {
+	if k.vs.Validator(ctx, validatorAddr) == nil {
+		return nil, ErrNoValidator(k.codespace)
+	}
+	vdi := k.GetValidatorDistInfo(ctx, validatorAddr)
+	coins, change := vdi.Commission.TruncateCoins()
+	vdi.Commission = change
+	k.SetValidatorDistInfo(ctx, vdi)
+
+	if coins.IsZero() {
+		return coins, nil
+	}
+	_, _, err := k.ck.AddCoins(ctx, validatorAddr, coins)
+	if err != nil {
+		return nil, err
+	}
+	return coins, nil
+}
+
+// pay the whole coins of the amount to the address, sending the fractional
+// change to the reserve pool
+func (k Keeper) payOut(ctx sdk.Context, addr sdk.AccAddress, amount RatCoins) (sdk.Coins, sdk.Error) {
+	coins, change := amount.TruncateCoins()
+	if !change.IsZero() {
+		pool := k.GetPool(ctx)
+		pool.FeeReservePool = pool.FeeReservePool.Plus(change)
+		k.SetPool(ctx, pool)
+	}
+
+	if coins.IsZero() {
+		return coins, nil
+	}
+	_, _, err := k.ck.AddCoins(ctx, addr, coins)
+	if err != nil {
+		return nil, err
+	}
+	return coins, nil
+}
@@ -1,31 +1,146 @@
-package stake
-
-//// test if is a gotValidator from the last update
-//func TestGetTotalPrecommitVotingPower(t *testing.T) {
-//ctx, _, keeper := createTestInput(t, false, 0)
-
-//amts := []int64{10000, 1000, 100, 10, 1}
-//var candidatesIn [5]Candidate
-//for i, amt := range amts {
-//candidatesIn[i] = NewCandidate(addrVals[i], pks[i], Description{})
-//candidatesIn[i].BondedShares = sdk.NewRat(amt)
-//candidatesIn[i].DelegatorShares = sdk.NewRat(amt)
-//keeper.setCandidate(ctx, candidatesIn[i])
-//}
-
-//// test that an empty gotValidator set doesn't have any gotValidators
-//gotValidators := keeper.GetValidators(ctx)
-//require.Equal(t, 5, len(gotValidators))
-
-//totPow := keeper.GetTotalPrecommitVotingPower(ctx)
-//exp := sdk.NewRat(11111)
-//require.True(t, exp.Equal(totPow), "exp %v, got %v", exp, totPow)
-
-//// set absent gotValidators to be the 1st and 3rd record sorted by pubKey address
-//ctx = ctx.WithAbsentValidators([]int32{1, 3})
-//totPow = keeper.GetTotalPrecommitVotingPower(ctx)
-
-//// XXX verify that this order should infact exclude these two records
-//exp = sdk.NewRat(11100)
-//require.True(t, exp.Equal(totPow), "exp %v, got %v", exp, totPow)
-//}
+package distribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+)
+
+func TestAllocateAndWithdraw(t *testing.T) {
+	ctx, ck, sk, keyFee, keeper := createTestInput(t)
+	sh := stake.NewHandler(sk)
+	dh := NewHandler(keeper)
+
+	got := sh(ctx, newTestMsgCreateValidator(addrs[0], pks[0], sdk.NewInt(100), sdk.NewRat(1, 10)))
+	require.True(t, got.IsOK(), "%v", got)
+	stake.EndBlocker(ctx, sk)
+
+	// 5% to the reserve pool, 10% of the rest as commission
+	setCollectedFees(ctx, keyFee, sdk.Coins{sdk.NewCoin("fee", 1000)})
+	keeper.AllocateFees(ctx, []abci.SigningValidator{newSigningValidator(pks[0], 100, true)})
+	require.True(t, keeper.fck.GetCollectedFees(ctx).IsZero())
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(50))}.String(), keeper.GetPool(ctx).FeeReservePool.String())
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(95))}.String(), keeper.GetValidatorDistInfo(ctx, addrs[0]).Commission.String())
+
+	rewards, err := keeper.GetDelegationRewards(ctx, addrs[0], addrs[0])
+	require.Nil(t, err)
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(855))}.String(), rewards.String())
+
+	got = dh(ctx, NewMsgWithdrawDelegatorReward(addrs[0], addrs[0]))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, int64(855), ck.GetCoins(ctx, addrs[0]).AmountOf("fee").Int64())
+	rewards, err = keeper.GetDelegationRewards(ctx, addrs[0], addrs[0])
+	require.Nil(t, err)
+	require.True(t, rewards.IsZero())
+
+	got = dh(ctx, NewMsgWithdrawValidatorCommission(addrs[0]))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, int64(950), ck.GetCoins(ctx, addrs[0]).AmountOf("fee").Int64())
+	require.True(t, keeper.GetValidatorDistInfo(ctx, addrs[0]).Commission.IsZero())
+}
+
+func TestNewDelegationEarnsFromNextAllocation(t *testing.T) {
+	ctx, ck, sk, keyFee, keeper := createTestInput(t)
+	sh := stake.NewHandler(sk)
+	dh := NewHandler(keeper)
+
+	got := sh(ctx, newTestMsgCreateValidator(addrs[0], pks[0], sdk.NewInt(100), sdk.NewRat(1, 10)))
+	require.True(t, got.IsOK(), "%v", got)
+	stake.EndBlocker(ctx, sk)
+
+	setCollectedFees(ctx, keyFee, sdk.Coins{sdk.NewCoin("fee", 1000)})
+	keeper.AllocateFees(ctx, []abci.SigningValidator{newSigningValidator(pks[0], 100, true)})
+
+	// the new delegation is not owed any of the fees allocated before it
+	got = sh(ctx, stake.NewMsgDelegate(addrs[1], addrs[0], sdk.NewCoin("steak", 100)))
+	require.True(t, got.IsOK(), "%v", got)
+	stake.EndBlocker(ctx, sk)
+	rewards, err := keeper.GetDelegationRewards(ctx, addrs[1], addrs[0])
+	require.Nil(t, err)
+	require.True(t, rewards.IsZero())
+
+	// 855 for the delegators, split evenly between the 200 shares
+	setCollectedFees(ctx, keyFee, sdk.Coins{sdk.NewCoin("fee", 1000)})
+	keeper.AllocateFees(ctx, []abci.SigningValidator{newSigningValidator(pks[0], 200, true)})
+	rewards, err = keeper.GetDelegationRewards(ctx, addrs[1], addrs[0])
+	require.Nil(t, err)
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(855, 2))}.String(), rewards.String())
+	rewards, err = keeper.GetDelegationRewards(ctx, addrs[0], addrs[0])
+	require.Nil(t, err)
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(855+855, 2))}.String(), rewards.String())
+
+	// the fractional change of the withdrawal goes to the reserve pool
+	got = dh(ctx, NewMsgWithdrawDelegatorRewardsAll(addrs[1]))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, int64(427), ck.GetCoins(ctx, addrs[1]).AmountOf("fee").Int64())
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(201, 2))}.String(), keeper.GetPool(ctx).FeeReservePool.String())
+
+	// unbonding withdraws the rewards owed for the unbonded shares
+	got = sh(ctx, stake.NewMsgBeginUnbonding(addrs[0], addrs[0], sdk.NewRat(50)))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, int64(1282), ck.GetCoins(ctx, addrs[0]).AmountOf("fee").Int64())
+}
+
+func TestWithdrawGenesisDelegation(t *testing.T) {
+	ctx, _, sk, _, _ := createTestInput(t)
+	sh := stake.NewHandler(sk)
+
+	got := sh(ctx, newTestMsgCreateValidator(addrs[0], pks[0], sdk.NewInt(100), sdk.NewRat(1, 10)))
+	require.True(t, got.IsOK(), "%v", got)
+	got = sh(ctx, stake.NewMsgDelegate(addrs[1], addrs[0], sdk.NewCoin("steak", 100)))
+	require.True(t, got.IsOK(), "%v", got)
+	stake.EndBlocker(ctx, sk)
+	genesis := stake.WriteGenesis(ctx, sk)
+
+	// the genesis delegations are set without running the staking hooks
+	ctx, ck, sk, keyFee, keeper := createTestInput(t)
+	err := stake.InitGenesis(ctx, sk, genesis)
+	require.Nil(t, err)
+	_, found := keeper.GetDelegationDistInfo(ctx, addrs[1], addrs[0])
+	require.False(t, found)
+
+	setCollectedFees(ctx, keyFee, sdk.Coins{sdk.NewCoin("fee", 1000)})
+	keeper.AllocateFees(ctx, []abci.SigningValidator{newSigningValidator(pks[0], 200, true)})
+	rewards, err := keeper.GetDelegationRewards(ctx, addrs[1], addrs[0])
+	require.Nil(t, err)
+	require.Equal(t, RatCoins{NewRatCoin("fee", sdk.NewRat(855, 2))}.String(), rewards.String())
+
+	got = NewHandler(keeper)(ctx, NewMsgWithdrawDelegatorReward(addrs[1], addrs[0]))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, int64(427), ck.GetCoins(ctx, addrs[1]).AmountOf("fee").Int64())
+	rewards, err = keeper.GetDelegationRewards(ctx, addrs[1], addrs[0])
+	require.Nil(t, err)
+	require.True(t, rewards.IsZero())
+}
+
+func TestAllocateWithoutSigners(t *testing.T) {
+	ctx, _, sk, keyFee, keeper := createTestInput(t)
+	sh := stake.NewHandler(sk)
+
+	got := sh(ctx, newTestMsgCreateValidator(addrs[0], pks[0], sdk.NewInt(100), sdk.ZeroRat()))
+	require.True(t, got.IsOK(), "%v", got)
+	stake.EndBlocker(ctx, sk)
+
+	// the fees are kept for the next block
+	fees := sdk.Coins{sdk.NewCoin("fee", 1000)}
+	setCollectedFees(ctx, keyFee, fees)
+	keeper.AllocateFees(ctx, []abci.SigningValidator{newSigningValidator(pks[0], 100, false)})
+	require.True(t, fees.IsEqual(keeper.fck.GetCollectedFees(ctx)))
+	require.True(t, keeper.GetPool(ctx).FeeReservePool.IsZero())
+}
+
+func TestWithdrawErrors(t *testing.T) {
+	ctx, _, _, _, keeper := createTestInput(t)
+	dh := NewHandler(keeper)
+
+	got := dh(ctx, NewMsgWithdrawDelegatorReward(addrs[0], addrs[1]))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNoDelegation), got.Code)
+
+	got = dh(ctx, NewMsgWithdrawValidatorCommission(addrs[0]))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNoValidator), got.Code)
+}
@@ -0,0 +1,29 @@
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+//nolint
+var (
+	PoolKey               = []byte{0x00} // key for the global distribution pool
+	ValidatorDistInfoKey  = []byte{0x01} // prefix for each key to the distribution info of a validator
+	DelegationDistInfoKey = []byte{0x02} // prefix for each key to the distribution info of a delegation
+)
+
+// get the key for the distribution info of a validator
+// VALUE: distribution.ValidatorDistInfo
+func GetValidatorDistInfoKey(validatorAddr sdk.AccAddress) []byte {
+	return append(ValidatorDistInfoKey, validatorAddr.Bytes()...)
+}
+
+// get the key for the distribution info of a delegation
+// VALUE: distribution.DelegationDistInfo
+func GetDelegationDistInfoKey(delegatorAddr, validatorAddr sdk.AccAddress) []byte {
+	return append(GetDelegationDistInfosKey(delegatorAddr), validatorAddr.Bytes()...)
+}
+
+// get the prefix for the distribution infos of all the delegations of a delegator
+func GetDelegationDistInfosKey(delegatorAddr sdk.AccAddress) []byte {
+	return append(DelegationDistInfoKey, delegatorAddr.Bytes()...)
+}
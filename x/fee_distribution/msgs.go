@@ -0,0 +1,122 @@
+package distribution
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// name to identify transaction types
+const MsgType = "distr"
+
+// verify interface at compile time
+var _, _, _ sdk.Msg = &MsgWithdrawDelegatorReward{}, &MsgWithdrawDelegatorRewardsAll{}, &MsgWithdrawValidatorCommission{}
+
+// MsgWithdrawDelegatorReward - withdraw the rewards of a single delegation
+type MsgWithdrawDelegatorReward struct {
+	DelegatorAddr sdk.AccAddress `json:"delegator_addr"`
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+func NewMsgWithdrawDelegatorReward(delegatorAddr, validatorAddr sdk.AccAddress) MsgWithdrawDelegatorReward {
+	return MsgWithdrawDelegatorReward{
+		DelegatorAddr: delegatorAddr,
+		ValidatorAddr: validatorAddr,
+	}
+}
+
+//nolint
+func (msg MsgWithdrawDelegatorReward) Type() string { return MsgType }
+func (msg MsgWithdrawDelegatorReward) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddr}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgWithdrawDelegatorReward) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// quick validity check
+func (msg MsgWithdrawDelegatorReward) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddr == nil {
+		return ErrNilDelegatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorAddr == nil {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	return nil
+}
+
+//______________________________________________________________________
+
+// MsgWithdrawDelegatorRewardsAll - withdraw the rewards of all the delegations of a delegator
+type MsgWithdrawDelegatorRewardsAll struct {
+	DelegatorAddr sdk.AccAddress `json:"delegator_addr"`
+}
+
+func NewMsgWithdrawDelegatorRewardsAll(delegatorAddr sdk.AccAddress) MsgWithdrawDelegatorRewardsAll {
+	return MsgWithdrawDelegatorRewardsAll{
+		DelegatorAddr: delegatorAddr,
+	}
+}
+
+//nolint
+func (msg MsgWithdrawDelegatorRewardsAll) Type() string { return MsgType }
+func (msg MsgWithdrawDelegatorRewardsAll) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddr}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgWithdrawDelegatorRewardsAll) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// quick validity check
+func (msg MsgWithdrawDelegatorRewardsAll) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddr == nil {
+		return ErrNilDelegatorAddr(DefaultCodespace)
+	}
+	return nil
+}
+
+//______________________________________________________________________
+
+// MsgWithdrawValidatorCommission - withdraw the commission of a validator
+type MsgWithdrawValidatorCommission struct {
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+func NewMsgWithdrawValidatorCommission(validatorAddr sdk.AccAddress) MsgWithdrawValidatorCommission {
+	return MsgWithdrawValidatorCommission{
+		ValidatorAddr: validatorAddr,
+	}
+}
+
+//nolint
+func (msg MsgWithdrawValidatorCommission) Type() string { return MsgType }
+func (msg MsgWithdrawValidatorCommission) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.ValidatorAddr}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgWithdrawValidatorCommission) GetSignBytes() []byte {
+	b, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// quick validity check
+func (msg MsgWithdrawValidatorCommission) ValidateBasic() sdk.Error {
+	if msg.ValidatorAddr == nil {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	return nil
+}
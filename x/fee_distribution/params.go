@@ -0,0 +1,61 @@
+package distribution
+
+import (
+	"errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// name of the distribution subspace of the params store, and the key of the
+// distribution params within it
+const (
+	DefaultParamspace   = "distr"
+	ParamStoreKeyParams = "params"
+)
+
+// ParamTypeTable - the parameters of the distribution subspace
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterType(ParamStoreKeyParams, Params{}, validateParams)
+}
+
+// Params - the governable parameters of fee distribution
+type Params struct {
+	ReservePoolFee sdk.Rat `json:"reserve_pool_fee"` // fraction of the collected fees set aside in the reserve pool
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		ReservePoolFee: sdk.NewRat(5, 100),
+	}
+}
+
+// validate the distribution params set by a params change
+func validateParams(value interface{}) error {
+	fee := value.(Params).ReservePoolFee
+	if fee.Rat == nil || fee.LT(sdk.ZeroRat()) || fee.GT(sdk.OneRat()) {
+		return errors.New("reserve pool fee must be between 0 and 1")
+	}
+	return nil
+}
+
+// MigrateParams sets the default distribution params unless they have been
+// set, for chains started before fee distribution. It is run by an upgrade
+// handler registered by the app.
+func (k Keeper) MigrateParams(ctx sdk.Context) {
+	if !k.paramSpace.Has(ctx, ParamStoreKeyParams) {
+		k.SetParams(ctx, DefaultParams())
+	}
+}
+
+// load/save the distribution params, kept in the distribution subspace of the params store
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	k.paramSpace.Get(ctx, ParamStoreKeyParams, &params)
+	return
+}
+
+// set the params
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	k.paramSpace.Set(ctx, ParamStoreKeyParams, params)
+}
@@ -0,0 +1,95 @@
+package distribution
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// query endpoints supported by the distribution Querier
+const (
+	QueryPool                = "pool"
+	QueryDelegationRewards   = "delegation_rewards"
+	QueryValidatorCommission = "validator_commission"
+)
+
+// NewQuerier returns the sdk.Querier answering "custom/distr/<endpoint>" queries
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("no distr query endpoint specified")
+		}
+		switch path[0] {
+		case QueryPool:
+			return marshalQueryResult(keeper.cdc, keeper.GetPool(ctx))
+		case QueryDelegationRewards:
+			return queryDelegationRewards(ctx, req, keeper)
+		case QueryValidatorCommission:
+			return queryValidatorCommission(ctx, req, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown distr query endpoint %s", path[0]))
+		}
+	}
+}
+
+// Params for query 'custom/distr/delegation_rewards'. Without a validator
+// the rewards of all the delegations of the delegator are summed.
+type QueryDelegationRewardsParams struct {
+	DelegatorAddr sdk.AccAddress `json:"delegator_addr"`
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+// Params for query 'custom/distr/validator_commission'
+type QueryValidatorCommissionParams struct {
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+func queryDelegationRewards(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryDelegationRewardsParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	if params.ValidatorAddr != nil {
+		rewards, err := keeper.GetDelegationRewards(ctx, params.DelegatorAddr, params.ValidatorAddr)
+		if err != nil {
+			return nil, err
+		}
+		return marshalQueryResult(keeper.cdc, rewards)
+	}
+
+	rewards := RatCoins{}
+	keeper.ds.IterateDelegations(ctx, params.DelegatorAddr, func(_ int64, delegation sdk.Delegation) (stop bool) {
+		delRewards, err := keeper.GetDelegationRewards(ctx, params.DelegatorAddr, delegation.GetValidator())
+		if err == nil {
+			rewards = rewards.Plus(delRewards)
+		}
+		return false
+	})
+	return marshalQueryResult(keeper.cdc, rewards)
+}
+
+func queryValidatorCommission(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryValidatorCommissionParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	if keeper.vs.Validator(ctx, params.ValidatorAddr) == nil {
+		return nil, ErrNoValidator(keeper.codespace)
+	}
+	return marshalQueryResult(keeper.cdc, keeper.GetValidatorDistInfo(ctx, params.ValidatorAddr).Commission)
+}
+
+func marshalQueryResult(cdc *wire.Codec, result interface{}) (res []byte, err sdk.Error) {
+	bz, errRes := wire.MarshalJSONIndent(cdc, result)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("could not marshal result to JSON - %s", errRes.Error()))
+	}
+	return bz, nil
+}
@@ -0,0 +1,164 @@
+package distribution
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// precision to which the fractional amounts are truncated, which bounds the
+// size of the rationals accumulated by the lazy fee accounting
+var ratPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// RatCoin - a coin whose amount may be fractional, as the fees owed to the
+// validators and delegators are until they are withdrawn
+type RatCoin struct {
+	Denom  string  `json:"denom"`
+	Amount sdk.Rat `json:"amount"`
+}
+
+// NewRatCoin creates a RatCoin
+func NewRatCoin(denom string, amount sdk.Rat) RatCoin {
+	return RatCoin{
+		Denom:  denom,
+		Amount: amount,
+	}
+}
+
+func (rc RatCoin) String() string {
+	return fmt.Sprintf("%v%v", rc.Amount.FloatString(), rc.Denom)
+}
+
+//_______________________________________________________________________
+
+// RatCoins - a set of RatCoin sorted by denom, without zero amounts
+type RatCoins []RatCoin
+
+// NewRatCoins converts coins to RatCoins
+func NewRatCoins(coins sdk.Coins) RatCoins {
+	rcs := RatCoins{}
+	for _, coin := range coins {
+		if coin.Amount.IsZero() {
+			continue
+		}
+		rcs = append(rcs, NewRatCoin(coin.Denom, sdk.NewRatFromInt(coin.Amount)))
+	}
+	return rcs
+}
+
+func (rcs RatCoins) String() string {
+	if len(rcs) == 0 {
+		return ""
+	}
+	out := make([]string, len(rcs))
+	for i, rc := range rcs {
+		out[i] = rc.String()
+	}
+	return strings.Join(out, ",")
+}
+
+// IsZero returns true if there are no coins
+func (rcs RatCoins) IsZero() bool {
+	return len(rcs) == 0
+}
+
+// AmountOf returns the amount of the denom
+func (rcs RatCoins) AmountOf(denom string) sdk.Rat {
+	for _, rc := range rcs {
+		if rc.Denom == denom {
+			return rc.Amount
+		}
+	}
+	return sdk.ZeroRat()
+}
+
+// Plus adds two sets of coins
+func (rcs RatCoins) Plus(rcs2 RatCoins) RatCoins {
+	sum := RatCoins{}
+	i, j := 0, 0
+	for i < len(rcs) || j < len(rcs2) {
+		switch {
+		case j == len(rcs2) || (i < len(rcs) && rcs[i].Denom < rcs2[j].Denom):
+			sum = append(sum, rcs[i])
+			i++
+		case i == len(rcs) || rcs2[j].Denom < rcs[i].Denom:
+			sum = append(sum, rcs2[j])
+			j++
+		default:
+			amount := rcs[i].Amount.Add(rcs2[j].Amount)
+			if !amount.IsZero() {
+				sum = append(sum, NewRatCoin(rcs[i].Denom, amount))
+			}
+			i++
+			j++
+		}
+	}
+	return sum
+}
+
+// Negative returns the set of coins with all amounts negated
+func (rcs RatCoins) Negative() RatCoins {
+	neg := make(RatCoins, len(rcs))
+	for i, rc := range rcs {
+		neg[i] = NewRatCoin(rc.Denom, sdk.ZeroRat().Sub(rc.Amount))
+	}
+	return neg
+}
+
+// Minus subtracts a set of coins
+func (rcs RatCoins) Minus(rcs2 RatCoins) RatCoins {
+	return rcs.Plus(rcs2.Negative())
+}
+
+// MulRat multiplies all amounts by the rational, truncating them to the
+// accounting precision
+func (rcs RatCoins) MulRat(r sdk.Rat) RatCoins {
+	res := RatCoins{}
+	for _, rc := range rcs {
+		amount := truncateRat(rc.Amount.Mul(r))
+		if !amount.IsZero() {
+			res = append(res, NewRatCoin(rc.Denom, amount))
+		}
+	}
+	return res
+}
+
+// QuoRat divides all amounts by the rational, truncating them to the
+// accounting precision
+func (rcs RatCoins) QuoRat(r sdk.Rat) RatCoins {
+	res := RatCoins{}
+	for _, rc := range rcs {
+		amount := truncateRat(rc.Amount.Quo(r))
+		if !amount.IsZero() {
+			res = append(res, NewRatCoin(rc.Denom, amount))
+		}
+	}
+	return res
+}
+
+// TruncateCoins returns the whole coins of the set, along with the remaining
+// fractional change
+func (rcs RatCoins) TruncateCoins() (coins sdk.Coins, change RatCoins) {
+	coins = sdk.Coins{}
+	change = RatCoins{}
+	for _, rc := range rcs {
+		whole := new(big.Int).Quo(rc.Amount.Rat.Num(), rc.Amount.Rat.Denom())
+		if whole.Sign() != 0 {
+			coins = append(coins, sdk.Coin{Denom: rc.Denom, Amount: sdk.NewIntFromBigInt(whole)})
+		}
+		remainder := rc.Amount.Sub(sdk.NewRatFromBigInt(whole))
+		if !remainder.IsZero() {
+			change = append(change, NewRatCoin(rc.Denom, remainder))
+		}
+	}
+	return coins, change
+}
+
+// truncate the rational towards zero to the accounting precision
+func truncateRat(r sdk.Rat) sdk.Rat {
+	num := new(big.Int).Mul(r.Rat.Num(), ratPrecision)
+	num.Quo(num, r.Rat.Denom())
+	return sdk.NewRatFromBigInt(num, ratPrecision)
+}
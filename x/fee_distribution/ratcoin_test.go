@@ -0,0 +1,44 @@
+package distribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestRatCoinsArithmetic(t *testing.T) {
+	a := RatCoins{NewRatCoin("atom", sdk.NewRat(3, 2)), NewRatCoin("fee", sdk.NewRat(10))}
+	b := RatCoins{NewRatCoin("fee", sdk.NewRat(10)), NewRatCoin("steak", sdk.NewRat(1, 3))}
+
+	require.Equal(t, RatCoins{
+		NewRatCoin("atom", sdk.NewRat(3, 2)),
+		NewRatCoin("fee", sdk.NewRat(20)),
+		NewRatCoin("steak", sdk.NewRat(1, 3)),
+	}.String(), a.Plus(b).String())
+
+	// zero amounts are dropped
+	require.Equal(t, RatCoins{
+		NewRatCoin("atom", sdk.NewRat(3, 2)),
+		NewRatCoin("steak", sdk.NewRat(-1, 3)),
+	}.String(), a.Minus(b).String())
+	require.True(t, a.Minus(a).IsZero())
+
+	require.Equal(t, RatCoins{
+		NewRatCoin("atom", sdk.NewRat(3, 4)),
+		NewRatCoin("fee", sdk.NewRat(5)),
+	}.String(), a.MulRat(sdk.NewRat(1, 2)).String())
+	require.Equal(t, a.MulRat(sdk.NewRat(1, 2)).String(), a.QuoRat(sdk.NewRat(2)).String())
+}
+
+func TestRatCoinsTruncation(t *testing.T) {
+	// products are truncated to the accounting precision
+	third := RatCoins{NewRatCoin("fee", sdk.OneRat())}.QuoRat(sdk.NewRat(3))
+	require.Equal(t, "333333333333333333/1000000000000000000", third[0].Amount.Rat.String())
+
+	rcs := RatCoins{NewRatCoin("atom", sdk.NewRat(1, 2)), NewRatCoin("fee", sdk.NewRat(7, 2))}
+	coins, change := rcs.TruncateCoins()
+	require.True(t, sdk.Coins{sdk.NewCoin("fee", 3)}.IsEqual(coins))
+	require.Equal(t, RatCoins{NewRatCoin("atom", sdk.NewRat(1, 2)), NewRatCoin("fee", sdk.NewRat(1, 2))}.String(), change.String())
+}
@@ -0,0 +1,130 @@
+package distribution
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+)
+
+var (
+	pks = []crypto.PubKey{
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB50"),
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB51"),
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB52"),
+	}
+	addrs = []sdk.AccAddress{
+		sdk.AccAddress(pks[0].Address()),
+		sdk.AccAddress(pks[1].Address()),
+		sdk.AccAddress(pks[2].Address()),
+	}
+	initCoins sdk.Int = sdk.NewInt(200)
+)
+
+func createTestCodec() *wire.Codec {
+	cdc := wire.NewCodec()
+	sdk.RegisterWire(cdc)
+	auth.RegisterWire(cdc)
+	bank.RegisterWire(cdc)
+	stake.RegisterWire(cdc)
+	RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+	return cdc
+}
+
+// test input with the stake keeper calling the distribution hooks, along with
+// the store key of the fee collection keeper to fake the collected fees
+func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, stake.Keeper, sdk.StoreKey, Keeper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyStake := sdk.NewKVStoreKey("stake")
+	keyFee := sdk.NewKVStoreKey("fee")
+	keyDistr := sdk.NewKVStoreKey("distr")
+	keyParams := sdk.NewKVStoreKey("params")
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyStake, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyFee, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyDistr, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewTMLogger(os.Stdout))
+	cdc := createTestCodec()
+	accountMapper := auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount)
+	ck := bank.NewKeeper(accountMapper)
+	fck := auth.NewFeeCollectionKeeper(cdc, keyFee)
+	pk := params.NewKeeper(cdc, keyParams)
+	sk := stake.NewKeeper(cdc, keyStake, ck, pk.Subspace(stake.DefaultParamspace, stake.ParamTypeTable()), stake.DefaultCodespace)
+	genesis := stake.DefaultGenesisState()
+
+	genesis.Pool.LooseTokens = sdk.NewRat(initCoins.MulRaw(int64(len(addrs))).Int64())
+
+	err = stake.InitGenesis(ctx, sk, genesis)
+	require.Nil(t, err)
+
+	for _, addr := range addrs {
+		_, _, err = ck.AddCoins(ctx, addr, sdk.Coins{
+			{sk.GetParams(ctx).BondDenom, initCoins},
+		})
+	}
+	require.Nil(t, err)
+	keeper := NewKeeper(cdc, keyDistr, pk.Subspace(DefaultParamspace, ParamTypeTable()), ck, sk, fck, DefaultCodespace)
+	sk = sk.SetHooks(keeper.Hooks())
+	InitGenesis(ctx, keeper, DefaultGenesisState())
+	return ctx, ck, sk, keyFee, keeper
+}
+
+// set the fees collected by the ante handler, as the fee collection keeper
+// does not expose a setter
+func setCollectedFees(ctx sdk.Context, keyFee sdk.StoreKey, coins sdk.Coins) {
+	ctx.KVStore(keyFee).Set([]byte("collectedFees"), createTestCodec().MustMarshalBinary(coins))
+}
+
+// the abci signing validator of a pubkey
+func newSigningValidator(pk crypto.PubKey, power int64, signed bool) abci.SigningValidator {
+	return abci.SigningValidator{
+		Validator: abci.Validator{
+			PubKey: tmtypes.TM2PB.PubKey(pk),
+			Power:  power,
+		},
+		SignedLastBlock: signed,
+	}
+}
+
+func newPubKey(pk string) (res crypto.PubKey) {
+	pkBytes, err := hex.DecodeString(pk)
+	if err != nil {
+		panic(err)
+	}
+	var pkEd ed25519.PubKeyEd25519
+	copy(pkEd[:], pkBytes[:])
+	return pkEd
+}
+
+func newTestMsgCreateValidator(address sdk.AccAddress, pubKey crypto.PubKey, amt sdk.Int, commission sdk.Rat) stake.MsgCreateValidator {
+	return stake.MsgCreateValidator{
+		Description:   stake.Description{},
+		Commission:    commission,
+		DelegatorAddr: address,
+		ValidatorAddr: address,
+		PubKey:        pubKey,
+		Delegation:    sdk.Coin{"steak", amt},
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"io/ioutil"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -11,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
 	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/pkg/errors"
 )
 
@@ -24,6 +27,8 @@ const (
 	flagDepositer    = "depositer"
 	flagVoter        = "voter"
 	flagOption       = "option"
+	flagParamChange  = "param-change"
+	flagContent      = "content"
 )
 
 // submit a proposal tx
@@ -55,6 +60,29 @@ func GetCmdSubmitProposal(cdc *wire.Codec) *cobra.Command {
 
 			// create the message
 			msg := gov.NewMsgSubmitProposal(title, description, proposalType, from, amount)
+			if proposalType == gov.ProposalTypeParameterChange {
+				strChanges, err := cmd.Flags().GetStringArray(flagParamChange)
+				if err != nil {
+					return err
+				}
+				changes, err := parseParamChanges(strChanges)
+				if err != nil {
+					return err
+				}
+				msg = gov.NewMsgSubmitParameterChangeProposal(title, description, from, amount, changes)
+			}
+			if proposalType == gov.ProposalTypeSoftwareUpgrade || proposalType == gov.ProposalTypeModule {
+				bz, err := ioutil.ReadFile(viper.GetString(flagContent))
+				if err != nil {
+					return err
+				}
+				var content gov.ProposalContent
+				err = cdc.UnmarshalJSON(bz, &content)
+				if err != nil {
+					return err
+				}
+				msg = gov.NewMsgSubmitModuleProposal(title, description, from, amount, content)
+			}
 
 			err = msg.ValidateBasic()
 			if err != nil {
@@ -79,10 +107,29 @@ func GetCmdSubmitProposal(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().String(flagProposalType, "", "proposalType of proposal")
 	cmd.Flags().String(flagDeposit, "", "deposit of proposal")
 	cmd.Flags().String(flagProposer, "", "proposer of proposal")
+	cmd.Flags().StringArray(flagParamChange, nil, "parameter change of a ParameterChange proposal, as subspace/key=value (JSON value), may be repeated")
+	cmd.Flags().String(flagContent, "", `JSON file of the content of a SoftwareUpgrade or Module proposal, e.g. {"type":"upgrade/SoftwareUpgradeProposal","value":{"plan":{"name":"v2","height":"1000","info":""}}}`)
 
 	return cmd
 }
 
+// parse parameter changes given as subspace/key=value
+func parseParamChanges(strChanges []string) ([]params.ParamChange, error) {
+	changes := make([]params.ParamChange, 0, len(strChanges))
+	for _, strChange := range strChanges {
+		kv := strings.SplitN(strChange, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid parameter change %s, expected subspace/key=value", strChange)
+		}
+		path := strings.SplitN(kv[0], "/", 2)
+		if len(path) != 2 {
+			return nil, errors.Errorf("invalid parameter change %s, expected subspace/key=value", strChange)
+		}
+		changes = append(changes, params.NewParamChange(path[0], path[1], kv[1]))
+	}
+	return changes, nil
+}
+
 // set a new Deposit transaction
 func GetCmdDeposit(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
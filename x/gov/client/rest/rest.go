@@ -9,6 +9,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 )
@@ -38,12 +39,14 @@ func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
 }
 
 type postProposalReq struct {
-	BaseReq        baseReq          `json:"base_req"`
-	Title          string           `json:"title"`           //  Title of the proposal
-	Description    string           `json:"description"`     //  Description of the proposal
-	ProposalType   gov.ProposalKind `json:"proposal_type"`   //  Type of proposal. Initial set {PlainTextProposal, SoftwareUpgradeProposal}
-	Proposer       sdk.AccAddress   `json:"proposer"`        //  Address of the proposer
-	InitialDeposit sdk.Coins        `json:"initial_deposit"` // Coins to add to the proposal's deposit
+	BaseReq        baseReq              `json:"base_req"`
+	Title          string               `json:"title"`           //  Title of the proposal
+	Description    string               `json:"description"`     //  Description of the proposal
+	ProposalType   gov.ProposalKind     `json:"proposal_type"`   //  Type of proposal {Text, ParameterChange, SoftwareUpgrade, Module}
+	Proposer       sdk.AccAddress       `json:"proposer"`        //  Address of the proposer
+	InitialDeposit sdk.Coins            `json:"initial_deposit"` // Coins to add to the proposal's deposit
+	Changes        []params.ParamChange `json:"changes"`         // Parameter changes of a ParameterChange proposal
+	Content        gov.ProposalContent  `json:"content"`         // Content of a SoftwareUpgrade or Module proposal
 }
 
 type depositReq struct {
@@ -72,6 +75,12 @@ func postProposalHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.Handle
 
 		// create the message
 		msg := gov.NewMsgSubmitProposal(req.Title, req.Description, req.ProposalType, req.Proposer, req.InitialDeposit)
+		if req.ProposalType == gov.ProposalTypeParameterChange {
+			msg = gov.NewMsgSubmitParameterChangeProposal(req.Title, req.Description, req.Proposer, req.InitialDeposit, req.Changes)
+		}
+		if req.ProposalType == gov.ProposalTypeSoftwareUpgrade || req.ProposalType == gov.ProposalTypeModule {
+			msg = gov.NewMsgSubmitModuleProposal(req.Title, req.Description, req.Proposer, req.InitialDeposit, req.Content)
+		}
 		err = msg.ValidateBasic()
 		if err != nil {
 			writeErr(&w, http.StatusBadRequest, err.Error())
@@ -7,6 +7,10 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/stake"
 )
 
 func TestTickExpiredDepositPeriod(t *testing.T) {
@@ -166,3 +170,96 @@ func TestTickPassedVotingPeriod(t *testing.T) {
 	depositsIterator.Close()
 	require.Equal(t, StatusRejected, keeper.GetProposal(ctx, proposalID).GetStatus())
 }
+
+func TestTickPassedParameterChangeProposal(t *testing.T) {
+	mapp, keeper, sk, addrs, _, _ := getMockApp(t, 10)
+	mapp.BeginBlock(abci.RequestBeginBlock{})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{})
+	govHandler := NewHandler(keeper)
+
+	dummyDescription := stake.NewDescription("T", "E", "S", "T")
+	valCreateMsg := stake.NewMsgCreateValidator(addrs[0], ed25519.GenPrivKey().PubKey(), sdk.NewCoin("steak", 5), dummyDescription)
+	res := stake.NewHandler(sk)(ctx, valCreateMsg)
+	require.True(t, res.IsOK())
+
+	// changes to unknown parameters are rejected on submission
+	badChange := params.NewParamChange(DefaultParamspace, "unknown", `{"voting_period":"100"}`)
+	res = govHandler(ctx, NewMsgSubmitParameterChangeProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, []params.ParamChange{badChange}))
+	require.False(t, res.IsOK())
+	badChange = params.NewParamChange(DefaultParamspace, ParamStoreKeyVotingProcedure, `{"voting_period":true}`)
+	res = govHandler(ctx, NewMsgSubmitParameterChangeProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, []params.ParamChange{badChange}))
+	require.False(t, res.IsOK())
+	badChange = params.NewParamChange(DefaultParamspace, ParamStoreKeyTallyingProcedure, `{"threshold":"3/2","veto":"1/3","governance_penalty":"1/100"}`)
+	res = govHandler(ctx, NewMsgSubmitParameterChangeProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, []params.ParamChange{badChange}))
+	require.False(t, res.IsOK())
+
+	changes := []params.ParamChange{
+		params.NewParamChange(DefaultParamspace, ParamStoreKeyVotingProcedure, `{"voting_period":"100"}`),
+		params.NewParamChange(DefaultParamspace, ParamStoreKeyDepositProcedure, `{"min_deposit":[{"denom":"steak","amount":"20"}],"max_deposit_period":"300"}`),
+	}
+	res = govHandler(ctx, NewMsgSubmitParameterChangeProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, changes))
+	require.True(t, res.IsOK())
+	var proposalID int64
+	keeper.cdc.UnmarshalBinaryBare(res.Data, &proposalID)
+	require.Equal(t, StatusVotingPeriod, keeper.GetProposal(ctx, proposalID).GetStatus())
+
+	res = govHandler(ctx, NewMsgVote(addrs[0], proposalID, OptionYes))
+	require.True(t, res.IsOK())
+
+	// the parameters only change once the proposal passes
+	ctx = ctx.WithBlockHeight(10)
+	EndBlocker(ctx, keeper)
+	require.Equal(t, int64(200), keeper.GetVotingProcedure(ctx).VotingPeriod)
+
+	ctx = ctx.WithBlockHeight(215)
+	EndBlocker(ctx, keeper)
+	require.Equal(t, StatusPassed, keeper.GetProposal(ctx, proposalID).GetStatus())
+	require.Equal(t, int64(100), keeper.GetVotingProcedure(ctx).VotingPeriod)
+	require.Equal(t, int64(300), keeper.GetDepositProcedure(ctx).MaxDepositPeriod)
+	require.True(t, sdk.Coins{sdk.NewCoin("steak", 20)}.IsEqual(keeper.GetDepositProcedure(ctx).MinDeposit))
+}
+
+func TestTickPassedModuleProposal(t *testing.T) {
+	mapp, keeper, sk, addrs, _, _ := getMockApp(t, 10)
+	mapp.Cdc.RegisterConcrete(testProposalContent{}, "gov/testProposalContent", nil)
+	mapp.BeginBlock(abci.RequestBeginBlock{})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{})
+	govHandler := NewHandler(keeper)
+
+	dummyDescription := stake.NewDescription("T", "E", "S", "T")
+	valCreateMsg := stake.NewMsgCreateValidator(addrs[0], ed25519.GenPrivKey().PubKey(), sdk.NewCoin("steak", 5), dummyDescription)
+	res := stake.NewHandler(sk)(ctx, valCreateMsg)
+	require.True(t, res.IsOK())
+
+	// contents without a registered route or rejected by their handler are
+	// rejected on submission
+	res = govHandler(ctx, NewMsgSubmitModuleProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, testProposalContent{Key: "key"}))
+	require.Equal(t, CodeUnknownProposalRoute, res.Code)
+	keeper.router.AddRoute("test", testProposalHandler{keeper})
+	res = govHandler(ctx, NewMsgSubmitModuleProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, testProposalContent{Key: "rejected"}))
+	require.False(t, res.IsOK())
+
+	var proposalIDs []int64
+	for _, content := range []testProposalContent{{Key: "key"}, {Key: "failed", Fail: true}} {
+		res = govHandler(ctx, NewMsgSubmitModuleProposal("Test", "test", addrs[0], sdk.Coins{sdk.NewCoin("steak", 10)}, content))
+		require.True(t, res.IsOK())
+		var proposalID int64
+		keeper.cdc.UnmarshalBinaryBare(res.Data, &proposalID)
+		res = govHandler(ctx, NewMsgVote(addrs[0], proposalID, OptionYes))
+		require.True(t, res.IsOK())
+		proposalIDs = append(proposalIDs, proposalID)
+	}
+
+	// the contents are only executed once the proposals pass, and nothing
+	// written by a failed execution is kept
+	store := ctx.KVStore(keeper.storeKey)
+	require.False(t, store.Has([]byte("key")))
+
+	ctx = ctx.WithBlockHeight(215)
+	EndBlocker(ctx, keeper)
+	for _, proposalID := range proposalIDs {
+		require.Equal(t, StatusPassed, keeper.GetProposal(ctx, proposalID).GetStatus())
+	}
+	require.True(t, store.Has([]byte("key")))
+	require.False(t, store.Has([]byte("failed")))
+}
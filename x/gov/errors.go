@@ -21,6 +21,9 @@ const (
 	CodeInvalidVote             sdk.CodeType = 9
 	CodeInvalidGenesis          sdk.CodeType = 10
 	CodeInvalidProposalStatus   sdk.CodeType = 11
+	CodeInvalidParamChange      sdk.CodeType = 12
+	CodeInvalidProposalContent  sdk.CodeType = 13
+	CodeUnknownProposalRoute    sdk.CodeType = 14
 )
 
 //----------------------------------------
@@ -65,3 +68,15 @@ func ErrInvalidVote(codespace sdk.CodespaceType, voteOption VoteOption) sdk.Erro
 func ErrInvalidGenesis(codespace sdk.CodespaceType, msg string) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidVote, msg)
 }
+
+func ErrInvalidParamChange(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidParamChange, fmt.Sprintf("Invalid parameter change: %s", msg))
+}
+
+func ErrInvalidProposalContent(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidProposalContent, fmt.Sprintf("Invalid proposal content: %s", msg))
+}
+
+func ErrUnknownProposalRoute(codespace sdk.CodespaceType, route string) sdk.Error {
+	return sdk.NewError(codespace, CodeUnknownProposalRoute, fmt.Sprintf("No proposal handler registered for route '%s'", route))
+}
@@ -2,16 +2,23 @@ package gov
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
 )
 
 // GenesisState - all staking state that must be provided at genesis
 type GenesisState struct {
-	StartingProposalID int64 `json:"starting_proposalID"`
+	StartingProposalID int64             `json:"starting_proposalID"`
+	DepositProcedure   DepositProcedure  `json:"deposit_procedure"`
+	VotingProcedure    VotingProcedure   `json:"voting_procedure"`
+	TallyingProcedure  TallyingProcedure `json:"tallying_procedure"`
 }
 
-func NewGenesisState(startingProposalID int64) GenesisState {
+func NewGenesisState(startingProposalID int64, dp DepositProcedure, vp VotingProcedure, tp TallyingProcedure) GenesisState {
 	return GenesisState{
 		StartingProposalID: startingProposalID,
+		DepositProcedure:   dp,
+		VotingProcedure:    vp,
+		TallyingProcedure:  tp,
 	}
 }
 
@@ -19,9 +26,37 @@ func NewGenesisState(startingProposalID int64) GenesisState {
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
 		StartingProposalID: 1,
+		DepositProcedure: DepositProcedure{
+			MinDeposit:       sdk.Coins{sdk.NewCoin("steak", 10)},
+			MaxDepositPeriod: 10000,
+		},
+		VotingProcedure: VotingProcedure{
+			VotingPeriod: 10000,
+		},
+		TallyingProcedure: TallyingProcedure{
+			Threshold:         sdk.NewRat(1, 2),
+			Veto:              sdk.NewRat(1, 3),
+			GovernancePenalty: sdk.NewRat(1, 100),
+		},
 	}
 }
 
+// ValidateGenesis checks the genesis parameters
+func ValidateGenesis(data GenesisState) error {
+	if data.StartingProposalID < 0 {
+		return errors.Errorf("starting proposal id must not be negative, is %d", data.StartingProposalID)
+	}
+	err := validateDepositProcedure(data.DepositProcedure)
+	if err != nil {
+		return err
+	}
+	err = validateVotingProcedure(data.VotingProcedure)
+	if err != nil {
+		return err
+	}
+	return validateTallyingProcedure(data.TallyingProcedure)
+}
+
 // InitGenesis - store genesis parameters
 func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
 	err := k.setInitialProposalID(ctx, data.StartingProposalID)
@@ -29,6 +64,9 @@ func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
 		// TODO: Handle this with #870
 		panic(err)
 	}
+	k.setDepositProcedure(ctx, data.DepositProcedure)
+	k.setVotingProcedure(ctx, data.VotingProcedure)
+	k.setTallyingProcedure(ctx, data.TallyingProcedure)
 }
 
 // WriteGenesis - output genesis parameters
@@ -36,6 +74,9 @@ func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
 	initalProposalID, _ := k.getNewProposalID(ctx)
 
 	return GenesisState{
-		initalProposalID,
+		StartingProposalID: initalProposalID,
+		DepositProcedure:   k.GetDepositProcedure(ctx),
+		VotingProcedure:    k.GetVotingProcedure(ctx),
+		TallyingProcedure:  k.GetTallyingProcedure(ctx),
 	}
 }
@@ -1,6 +1,8 @@
 package gov
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -23,7 +25,30 @@ func NewHandler(keeper Keeper) sdk.Handler {
 
 func handleMsgSubmitProposal(ctx sdk.Context, keeper Keeper, msg MsgSubmitProposal) sdk.Result {
 
-	proposal := keeper.NewTextProposal(ctx, msg.Title, msg.Description, msg.ProposalType)
+	var proposal Proposal
+	if msg.ProposalType == ProposalTypeParameterChange {
+		// reject changes to unknown parameters or of the wrong type up front
+		for _, change := range msg.Changes {
+			err := keeper.paramsKeeper.ValidateChange(change)
+			if err != nil {
+				return ErrInvalidParamChange(keeper.codespace, err.Error()).Result()
+			}
+		}
+		proposal = keeper.NewParameterChangeProposal(ctx, msg.Title, msg.Description, msg.Changes)
+	} else if msg.ProposalType.hasContent() {
+		// reject content the module could not execute up front
+		handler := keeper.router.Route(msg.Content.ProposalRoute())
+		if handler == nil {
+			return ErrUnknownProposalRoute(keeper.codespace, msg.Content.ProposalRoute()).Result()
+		}
+		err := handler.CheckProposal(ctx, msg.Content)
+		if err != nil {
+			return err.Result()
+		}
+		proposal = keeper.NewModuleProposal(ctx, msg.Title, msg.Description, msg.Content)
+	} else {
+		proposal = keeper.NewTextProposal(ctx, msg.Title, msg.Description, msg.ProposalType)
+	}
 
 	err, votingStarted := keeper.AddDeposit(ctx, proposal.GetProposalID(), msg.Proposer, msg.InitialDeposit)
 	if err != nil {
@@ -114,7 +139,7 @@ func EndBlocker(ctx sdk.Context, keeper Keeper) (tags sdk.Tags, nonVotingVals []
 	for shouldPopActiveProposalQueue(ctx, keeper) {
 		activeProposal := keeper.ActiveProposalQueuePop(ctx)
 
-		if ctx.BlockHeight() >= activeProposal.GetVotingStartBlock()+keeper.GetVotingProcedure().VotingPeriod {
+		if ctx.BlockHeight() >= activeProposal.GetVotingStartBlock()+keeper.GetVotingProcedure(ctx).VotingPeriod {
 			passes, nonVotingVals = tally(ctx, keeper, activeProposal)
 			proposalIDBytes := keeper.cdc.MustMarshalBinaryBare(activeProposal.GetProposalID())
 			if passes {
@@ -122,6 +147,20 @@ func EndBlocker(ctx sdk.Context, keeper Keeper) (tags sdk.Tags, nonVotingVals []
 				activeProposal.SetStatus(StatusPassed)
 				tags.AppendTag("action", []byte("proposalPassed"))
 				tags.AppendTag("proposalId", proposalIDBytes)
+
+				err := keeper.applyParameterChanges(ctx, activeProposal)
+				if err != nil {
+					ctx.Logger().With("module", "x/gov").Error(fmt.Sprintf("could not apply parameter changes of proposal %d: %s", activeProposal.GetProposalID(), err.Error()))
+					tags.AppendTag("action", []byte("parameterChangeFailed"))
+					tags.AppendTag("proposalId", proposalIDBytes)
+				}
+
+				err = keeper.executeModuleProposal(ctx, activeProposal)
+				if err != nil {
+					ctx.Logger().With("module", "x/gov").Error(fmt.Sprintf("could not execute the content of proposal %d: %s", activeProposal.GetProposalID(), err.Error()))
+					tags.AppendTag("action", []byte("proposalExecutionFailed"))
+					tags.AppendTag("proposalId", proposalIDBytes)
+				}
 			} else {
 				keeper.DeleteDeposits(ctx, activeProposal.GetProposalID())
 				activeProposal.SetStatus(StatusRejected)
@@ -136,7 +175,7 @@ func EndBlocker(ctx sdk.Context, keeper Keeper) (tags sdk.Tags, nonVotingVals []
 	return tags, nonVotingVals
 }
 func shouldPopInactiveProposalQueue(ctx sdk.Context, keeper Keeper) bool {
-	depositProcedure := keeper.GetDepositProcedure()
+	depositProcedure := keeper.GetDepositProcedure(ctx)
 	peekProposal := keeper.InactiveProposalQueuePeek(ctx)
 
 	if peekProposal == nil {
@@ -150,7 +189,7 @@ func shouldPopInactiveProposalQueue(ctx sdk.Context, keeper Keeper) bool {
 }
 
 func shouldPopActiveProposalQueue(ctx sdk.Context, keeper Keeper) bool {
-	votingProcedure := keeper.GetVotingProcedure()
+	votingProcedure := keeper.GetVotingProcedure(ctx)
 	peekProposal := keeper.ActiveProposalQueuePeek(ctx)
 
 	if peekProposal == nil {
@@ -4,10 +4,17 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	wire "github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // Governance Keeper
 type Keeper struct {
+	// The reference to the params store, to apply parameter change proposals
+	paramsKeeper params.Keeper
+
+	// The governance subspace of the params store
+	paramSpace params.Subspace
+
 	// The reference to the CoinKeeper to modify balances
 	ck bank.Keeper
 
@@ -17,6 +24,9 @@ type Keeper struct {
 	// The reference to the DelegationSet to get information about delegators
 	ds sdk.DelegationSet
 
+	// The router to the proposal handlers of the modules, to check and execute module proposals
+	router ProposalRouter
+
 	// The (unexposed) keys used to access the stores from the Context.
 	storeKey sdk.StoreKey
 
@@ -28,14 +38,17 @@ type Keeper struct {
 }
 
 // NewGovernanceMapper returns a mapper that uses go-wire to (binary) encode and decode gov types.
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, ds sdk.DelegationSet, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, paramsKeeper params.Keeper, paramSpace params.Subspace, ck bank.Keeper, ds sdk.DelegationSet, router ProposalRouter, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
-		storeKey:  key,
-		ck:        ck,
-		ds:        ds,
-		vs:        ds.GetValidatorSet(),
-		cdc:       cdc,
-		codespace: codespace,
+		storeKey:     key,
+		paramsKeeper: paramsKeeper,
+		paramSpace:   paramSpace,
+		ck:           ck,
+		ds:           ds,
+		vs:           ds.GetValidatorSet(),
+		router:       router,
+		cdc:          cdc,
+		codespace:    codespace,
 	}
 }
 
@@ -68,6 +81,54 @@ func (keeper Keeper) NewTextProposal(ctx sdk.Context, title string, description
 	return proposal
 }
 
+// Creates a new ParameterChangeProposal
+func (keeper Keeper) NewParameterChangeProposal(ctx sdk.Context, title string, description string, changes []params.ParamChange) Proposal {
+	proposalID, err := keeper.getNewProposalID(ctx)
+	if err != nil {
+		return nil
+	}
+	var proposal Proposal = &ParameterChangeProposal{
+		TextProposal: TextProposal{
+			ProposalID:       proposalID,
+			Title:            title,
+			Description:      description,
+			ProposalType:     ProposalTypeParameterChange,
+			Status:           StatusDepositPeriod,
+			TotalDeposit:     sdk.Coins{},
+			SubmitBlock:      ctx.BlockHeight(),
+			VotingStartBlock: -1, // TODO: Make Time
+		},
+		Changes: changes,
+	}
+	keeper.SetProposal(ctx, proposal)
+	keeper.InactiveProposalQueuePush(ctx, proposal)
+	return proposal
+}
+
+// Creates a new ModuleProposal
+func (keeper Keeper) NewModuleProposal(ctx sdk.Context, title string, description string, content ProposalContent) Proposal {
+	proposalID, err := keeper.getNewProposalID(ctx)
+	if err != nil {
+		return nil
+	}
+	var proposal Proposal = &ModuleProposal{
+		TextProposal: TextProposal{
+			ProposalID:       proposalID,
+			Title:            title,
+			Description:      description,
+			ProposalType:     contentProposalType(content),
+			Status:           StatusDepositPeriod,
+			TotalDeposit:     sdk.Coins{},
+			SubmitBlock:      ctx.BlockHeight(),
+			VotingStartBlock: -1, // TODO: Make Time
+		},
+		Content: content,
+	}
+	keeper.SetProposal(ctx, proposal)
+	keeper.InactiveProposalQueuePush(ctx, proposal)
+	return proposal
+}
+
 // Get Proposal from store by ProposalID
 func (keeper Keeper) GetProposal(ctx sdk.Context, proposalID int64) Proposal {
 	store := ctx.KVStore(keeper.storeKey)
@@ -118,6 +179,46 @@ func (keeper Keeper) getNewProposalID(ctx sdk.Context) (proposalID int64, err sd
 	return proposalID, nil
 }
 
+// Writes the parameter changes of a passed ParameterChangeProposal to the
+// params store. Either every change is applied or none of them are.
+func (keeper Keeper) applyParameterChanges(ctx sdk.Context, proposal Proposal) error {
+	paramProposal, ok := proposal.(*ParameterChangeProposal)
+	if !ok {
+		return nil
+	}
+
+	cacheCtx, writeCache := ctx.CacheContext()
+	for _, change := range paramProposal.Changes {
+		err := keeper.paramsKeeper.SetRaw(cacheCtx, change)
+		if err != nil {
+			return err
+		}
+	}
+	writeCache()
+	return nil
+}
+
+// Executes the content of a passed ModuleProposal with the proposal handler
+// of its route. Either the whole content is executed or none of it is.
+func (keeper Keeper) executeModuleProposal(ctx sdk.Context, proposal Proposal) error {
+	moduleProposal, ok := proposal.(*ModuleProposal)
+	if !ok {
+		return nil
+	}
+	handler := keeper.router.Route(moduleProposal.Content.ProposalRoute())
+	if handler == nil {
+		return ErrUnknownProposalRoute(keeper.codespace, moduleProposal.Content.ProposalRoute())
+	}
+
+	cacheCtx, writeCache := ctx.CacheContext()
+	err := handler.ExecuteProposal(cacheCtx, moduleProposal.Content)
+	if err != nil {
+		return err
+	}
+	writeCache()
+	return nil
+}
+
 func (keeper Keeper) activateVotingPeriod(ctx sdk.Context, proposal Proposal) {
 	proposal.SetVotingStartBlock(ctx.BlockHeight())
 	proposal.SetStatus(StatusVotingPeriod)
@@ -128,33 +229,49 @@ func (keeper Keeper) activateVotingPeriod(ctx sdk.Context, proposal Proposal) {
 // =====================================================
 // Procedures
 
-var (
-	defaultMinDeposit       int64 = 10
-	defaultMaxDepositPeriod int64 = 10000
-	defaultVotingPeriod     int64 = 10000
-)
+// Gets procedure from the params store
+func (keeper Keeper) GetDepositProcedure(ctx sdk.Context) (depositProcedure DepositProcedure) {
+	keeper.paramSpace.Get(ctx, ParamStoreKeyDepositProcedure, &depositProcedure)
+	return
+}
 
-// Gets procedure from store. TODO: move to global param store and allow for updating of this
-func (keeper Keeper) GetDepositProcedure() DepositProcedure {
-	return DepositProcedure{
-		MinDeposit:       sdk.Coins{sdk.NewCoin("steak", defaultMinDeposit)},
-		MaxDepositPeriod: defaultMaxDepositPeriod,
-	}
+// Gets procedure from the params store
+func (keeper Keeper) GetVotingProcedure(ctx sdk.Context) (votingProcedure VotingProcedure) {
+	keeper.paramSpace.Get(ctx, ParamStoreKeyVotingProcedure, &votingProcedure)
+	return
 }
 
-// Gets procedure from store. TODO: move to global param store and allow for updating of this
-func (keeper Keeper) GetVotingProcedure() VotingProcedure {
-	return VotingProcedure{
-		VotingPeriod: defaultVotingPeriod,
-	}
+// Gets procedure from the params store
+func (keeper Keeper) GetTallyingProcedure(ctx sdk.Context) (tallyingProcedure TallyingProcedure) {
+	keeper.paramSpace.Get(ctx, ParamStoreKeyTallyingProcedure, &tallyingProcedure)
+	return
+}
+
+func (keeper Keeper) setDepositProcedure(ctx sdk.Context, depositProcedure DepositProcedure) {
+	keeper.paramSpace.Set(ctx, ParamStoreKeyDepositProcedure, depositProcedure)
 }
 
-// Gets procedure from store. TODO: move to global param store and allow for updating of this
-func (keeper Keeper) GetTallyingProcedure() TallyingProcedure {
-	return TallyingProcedure{
-		Threshold:         sdk.NewRat(1, 2),
-		Veto:              sdk.NewRat(1, 3),
-		GovernancePenalty: sdk.NewRat(1, 100),
+func (keeper Keeper) setVotingProcedure(ctx sdk.Context, votingProcedure VotingProcedure) {
+	keeper.paramSpace.Set(ctx, ParamStoreKeyVotingProcedure, votingProcedure)
+}
+
+func (keeper Keeper) setTallyingProcedure(ctx sdk.Context, tallyingProcedure TallyingProcedure) {
+	keeper.paramSpace.Set(ctx, ParamStoreKeyTallyingProcedure, tallyingProcedure)
+}
+
+// MigrateParams sets the default procedures, which governance used before
+// they moved to the params store, unless they have been set. It is run by an
+// upgrade handler registered by the app.
+func (keeper Keeper) MigrateParams(ctx sdk.Context) {
+	defaults := DefaultGenesisState()
+	if !keeper.paramSpace.Has(ctx, ParamStoreKeyDepositProcedure) {
+		keeper.setDepositProcedure(ctx, defaults.DepositProcedure)
+	}
+	if !keeper.paramSpace.Has(ctx, ParamStoreKeyVotingProcedure) {
+		keeper.setVotingProcedure(ctx, defaults.VotingProcedure)
+	}
+	if !keeper.paramSpace.Has(ctx, ParamStoreKeyTallyingProcedure) {
+		keeper.setTallyingProcedure(ctx, defaults.TallyingProcedure)
 	}
 }
 
@@ -262,7 +379,7 @@ func (keeper Keeper) AddDeposit(ctx sdk.Context, proposalID int64, depositerAddr
 	// Check if deposit tipped proposal into voting period
 	// Active voting period if so
 	activatedVotingPeriod := false
-	if proposal.GetStatus() == StatusDepositPeriod && proposal.GetTotalDeposit().IsGTE(keeper.GetDepositProcedure().MinDeposit) {
+	if proposal.GetStatus() == StatusDepositPeriod && proposal.GetTotalDeposit().IsGTE(keeper.GetDepositProcedure(ctx).MinDeposit) {
 		keeper.activateVotingPeriod(ctx, proposal)
 		activatedVotingPeriod = true
 	}
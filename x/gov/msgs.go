@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // name to idetify transaction types
@@ -14,9 +15,12 @@ const MsgType = "gov"
 type MsgSubmitProposal struct {
 	Title          string         //  Title of the proposal
 	Description    string         //  Description of the proposal
-	ProposalType   ProposalKind   //  Type of proposal. Initial set {PlainTextProposal, SoftwareUpgradeProposal}
+	ProposalType   ProposalKind   //  Type of proposal {Text, ParameterChange, SoftwareUpgrade, Module}
 	Proposer       sdk.AccAddress //  Address of the proposer
 	InitialDeposit sdk.Coins      //  Initial deposit paid by sender. Must be strictly positive.
+
+	Changes []params.ParamChange //  New parameter values of a ParameterChange proposal
+	Content ProposalContent      //  Content of a SoftwareUpgrade or Module proposal, executed by the proposal handler of its route
 }
 
 func NewMsgSubmitProposal(title string, description string, proposalType ProposalKind, proposer sdk.AccAddress, initialDeposit sdk.Coins) MsgSubmitProposal {
@@ -29,6 +33,35 @@ func NewMsgSubmitProposal(title string, description string, proposalType Proposa
 	}
 }
 
+func NewMsgSubmitParameterChangeProposal(title string, description string, proposer sdk.AccAddress, initialDeposit sdk.Coins, changes []params.ParamChange) MsgSubmitProposal {
+	return MsgSubmitProposal{
+		Title:          title,
+		Description:    description,
+		ProposalType:   ProposalTypeParameterChange,
+		Proposer:       proposer,
+		InitialDeposit: initialDeposit,
+		Changes:        changes,
+	}
+}
+
+// NewMsgSubmitModuleProposal creates a proposal executing the content, a
+// SoftwareUpgrade proposal for the contents routed to x/upgrade and a Module
+// proposal otherwise
+func NewMsgSubmitModuleProposal(title string, description string, proposer sdk.AccAddress, initialDeposit sdk.Coins, content ProposalContent) MsgSubmitProposal {
+	proposalType := ProposalTypeModule
+	if content != nil {
+		proposalType = contentProposalType(content)
+	}
+	return MsgSubmitProposal{
+		Title:          title,
+		Description:    description,
+		ProposalType:   proposalType,
+		Proposer:       proposer,
+		InitialDeposit: initialDeposit,
+		Content:        content,
+	}
+}
+
 // Implements Msg.
 func (msg MsgSubmitProposal) Type() string { return MsgType }
 
@@ -52,6 +85,32 @@ func (msg MsgSubmitProposal) ValidateBasic() sdk.Error {
 	if !msg.InitialDeposit.IsNotNegative() {
 		return sdk.ErrInvalidCoins(msg.InitialDeposit.String())
 	}
+	if msg.ProposalType == ProposalTypeParameterChange && len(msg.Changes) == 0 {
+		return ErrInvalidParamChange(DefaultCodespace, "ParameterChange proposal without any parameter changes")
+	}
+	if msg.ProposalType != ProposalTypeParameterChange && len(msg.Changes) != 0 {
+		return ErrInvalidParamChange(DefaultCodespace, fmt.Sprintf("parameter changes given for a %s proposal", msg.ProposalType))
+	}
+	for _, change := range msg.Changes {
+		if len(change.Subspace) == 0 || len(change.Key) == 0 || len(change.Value) == 0 {
+			return ErrInvalidParamChange(DefaultCodespace, fmt.Sprintf("incomplete parameter change %s", change))
+		}
+	}
+	if msg.ProposalType.hasContent() {
+		if msg.Content == nil {
+			return ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("%s proposal without any content", msg.ProposalType))
+		}
+		if contentProposalType(msg.Content) != msg.ProposalType {
+			return ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("content routed to %s given for a %s proposal", msg.Content.ProposalRoute(), msg.ProposalType))
+		}
+		err := msg.Content.ValidateBasic()
+		if err != nil {
+			return err
+		}
+	}
+	if !msg.ProposalType.hasContent() && msg.Content != nil {
+		return ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("content given for a %s proposal", msg.ProposalType))
+	}
 	return nil
 }
 
@@ -7,6 +7,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 var (
@@ -30,8 +31,8 @@ func TestMsgSubmitProposal(t *testing.T) {
 		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeText, addrs[0], coinsPos, true},
 		{"", "the purpose of this proposal is to test", ProposalTypeText, addrs[0], coinsPos, false},
 		{"Test Proposal", "", ProposalTypeText, addrs[0], coinsPos, false},
-		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeParameterChange, addrs[0], coinsPos, true},
-		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeSoftwareUpgrade, addrs[0], coinsPos, true},
+		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeParameterChange, addrs[0], coinsPos, false},
+		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeSoftwareUpgrade, addrs[0], coinsPos, false},
 		{"Test Proposal", "the purpose of this proposal is to test", 0x05, addrs[0], coinsPos, false},
 		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeText, sdk.AccAddress{}, coinsPos, false},
 		{"Test Proposal", "the purpose of this proposal is to test", ProposalTypeText, addrs[0], coinsZero, true},
@@ -49,6 +50,81 @@ func TestMsgSubmitProposal(t *testing.T) {
 	}
 }
 
+// test ValidateBasic for a ParameterChange MsgSubmitProposal
+func TestMsgSubmitParameterChangeProposal(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(1, sdk.Coins{})
+	change := params.NewParamChange(DefaultParamspace, ParamStoreKeyVotingProcedure, `{"voting_period":"100"}`)
+	tests := []struct {
+		changes    []params.ParamChange
+		expectPass bool
+	}{
+		{[]params.ParamChange{change}, true},
+		{[]params.ParamChange{change, params.NewParamChange("stake", "params", "{}")}, true},
+		{nil, false},
+		{[]params.ParamChange{params.NewParamChange("", ParamStoreKeyVotingProcedure, "{}")}, false},
+		{[]params.ParamChange{params.NewParamChange(DefaultParamspace, "", "{}")}, false},
+		{[]params.ParamChange{params.NewParamChange(DefaultParamspace, ParamStoreKeyVotingProcedure, "")}, false},
+	}
+
+	for i, tc := range tests {
+		msg := NewMsgSubmitParameterChangeProposal("Test Proposal", "the purpose of this proposal is to test", addrs[0], coinsPos, tc.changes)
+		if tc.expectPass {
+			require.Nil(t, msg.ValidateBasic(), "test: %v", i)
+		} else {
+			require.NotNil(t, msg.ValidateBasic(), "test: %v", i)
+		}
+	}
+
+	// changes are only allowed on ParameterChange proposals
+	msg := NewMsgSubmitProposal("Test Proposal", "the purpose of this proposal is to test", ProposalTypeText, addrs[0], coinsPos)
+	msg.Changes = []params.ParamChange{change}
+	require.NotNil(t, msg.ValidateBasic())
+}
+
+func TestMsgSubmitModuleProposal(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(1, sdk.Coins{})
+	tests := []struct {
+		content    ProposalContent
+		expectPass bool
+	}{
+		{testProposalContent{Key: "key"}, true},
+		{testProposalContent{}, false},
+		{nil, false},
+	}
+
+	for i, tc := range tests {
+		msg := NewMsgSubmitModuleProposal("Test Proposal", "the purpose of this proposal is to test", addrs[0], coinsPos, tc.content)
+		if tc.expectPass {
+			require.Nil(t, msg.ValidateBasic(), "test: %v", i)
+			require.NotPanics(t, func() { msg.GetSignBytes() }, "test: %v", i)
+		} else {
+			require.NotNil(t, msg.ValidateBasic(), "test: %v", i)
+		}
+	}
+
+	// contents are only allowed on SoftwareUpgrade and Module proposals
+	msg := NewMsgSubmitProposal("Test Proposal", "the purpose of this proposal is to test", ProposalTypeText, addrs[0], coinsPos)
+	msg.Content = testProposalContent{Key: "key"}
+	require.NotNil(t, msg.ValidateBasic())
+
+	// contents routed to x/upgrade make SoftwareUpgrade proposals, the others Module proposals
+	msg = NewMsgSubmitModuleProposal("Test Proposal", "the purpose of this proposal is to test", addrs[0], coinsPos, testUpgradeContent{})
+	require.Equal(t, ProposalTypeSoftwareUpgrade, msg.ProposalType)
+	require.Nil(t, msg.ValidateBasic())
+	msg.ProposalType = ProposalTypeModule
+	require.NotNil(t, msg.ValidateBasic())
+	msg = NewMsgSubmitModuleProposal("Test Proposal", "the purpose of this proposal is to test", addrs[0], coinsPos, testProposalContent{Key: "key"})
+	msg.ProposalType = ProposalTypeSoftwareUpgrade
+	require.NotNil(t, msg.ValidateBasic())
+}
+
+// content of a test software upgrade proposal
+type testUpgradeContent struct{}
+
+func (c testUpgradeContent) ProposalRoute() string    { return SoftwareUpgradeRoute }
+func (c testUpgradeContent) String() string           { return "upgrade" }
+func (c testUpgradeContent) ValidateBasic() sdk.Error { return nil }
+
 // test ValidateBasic for MsgDeposit
 func TestMsgDeposit(t *testing.T) {
 	_, addrs, _, _ := mock.CreateGenAccounts(1, sdk.Coins{})
@@ -1,9 +1,58 @@
 package gov
 
 import (
+	"errors"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// name of the governance subspace of the params store, and the keys of the
+// governance procedures within it
+const (
+	DefaultParamspace              = "gov"
+	ParamStoreKeyDepositProcedure  = "depositprocedure"
+	ParamStoreKeyVotingProcedure   = "votingprocedure"
+	ParamStoreKeyTallyingProcedure = "tallyingprocedure"
 )
 
+// ParamTypeTable - the parameters of the governance subspace
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().
+		RegisterType(ParamStoreKeyDepositProcedure, DepositProcedure{}, validateDepositProcedure).
+		RegisterType(ParamStoreKeyVotingProcedure, VotingProcedure{}, validateVotingProcedure).
+		RegisterType(ParamStoreKeyTallyingProcedure, TallyingProcedure{}, validateTallyingProcedure)
+}
+
+// validate the procedures set by a params change
+func validateDepositProcedure(value interface{}) error {
+	procedure := value.(DepositProcedure)
+	if !procedure.MinDeposit.IsValid() && len(procedure.MinDeposit) != 0 {
+		return errors.New("min deposit is invalid")
+	}
+	if procedure.MaxDepositPeriod <= 0 {
+		return errors.New("max deposit period must be positive")
+	}
+	return nil
+}
+
+func validateVotingProcedure(value interface{}) error {
+	if value.(VotingProcedure).VotingPeriod <= 0 {
+		return errors.New("voting period must be positive")
+	}
+	return nil
+}
+
+func validateTallyingProcedure(value interface{}) error {
+	procedure := value.(TallyingProcedure)
+	for _, fraction := range []sdk.Rat{procedure.Threshold, procedure.Veto, procedure.GovernancePenalty} {
+		if fraction.Rat == nil || fraction.LT(sdk.ZeroRat()) || fraction.GT(sdk.OneRat()) {
+			return errors.New("threshold, veto and governance penalty must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
 // Procedure around Deposits for governance
 type DepositProcedure struct {
 	MinDeposit       sdk.Coins `json:"min_deposit"`        //  Minimum deposit for a proposal to enter voting period.
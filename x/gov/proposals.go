@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 //-----------------------------------------------------------
@@ -58,7 +59,7 @@ type TextProposal struct {
 	ProposalID   int64        `json:"proposal_id"`   //  ID of the proposal
 	Title        string       `json:"title"`         //  Title of the proposal
 	Description  string       `json:"description"`   //  Description of the proposal
-	ProposalType ProposalKind `json:"proposal_type"` //  Type of proposal. Initial set {PlainTextProposal, SoftwareUpgradeProposal}
+	ProposalType ProposalKind `json:"proposal_type"` //  Type of proposal {Text, ParameterChange, SoftwareUpgrade, Module}
 
 	Status ProposalStatus `json:"proposal_status"` //  Status of the Proposal {Pending, Active, Passed, Rejected}
 
@@ -91,6 +92,28 @@ func (tp *TextProposal) SetVotingStartBlock(votingStartBlock int64) {
 	tp.VotingStartBlock = votingStartBlock
 }
 
+//-----------------------------------------------------------
+// Parameter Change Proposals
+type ParameterChangeProposal struct {
+	TextProposal
+
+	Changes []params.ParamChange `json:"changes"` //  New parameter values, written to the params store when the proposal passes
+}
+
+// Implements Proposal Interface
+var _ Proposal = (*ParameterChangeProposal)(nil)
+
+//-----------------------------------------------------------
+// Module Proposals, of the SoftwareUpgrade and Module kinds
+type ModuleProposal struct {
+	TextProposal
+
+	Content ProposalContent `json:"content"` //  Content executed by the proposal handler of its route when the proposal passes
+}
+
+// Implements Proposal Interface
+var _ Proposal = (*ModuleProposal)(nil)
+
 //-----------------------------------------------------------
 // ProposalQueue
 type ProposalQueue []int64
@@ -106,8 +129,27 @@ const (
 	ProposalTypeText            ProposalKind = 0x01
 	ProposalTypeParameterChange ProposalKind = 0x02
 	ProposalTypeSoftwareUpgrade ProposalKind = 0x03
+	ProposalTypeModule          ProposalKind = 0x04
 )
 
+// SoftwareUpgradeRoute - the route of the contents of SoftwareUpgrade
+// proposals, handled by x/upgrade. Contents routed to any other module make
+// Module proposals.
+const SoftwareUpgradeRoute = "upgrade"
+
+// the kind of the proposals with the content
+func contentProposalType(content ProposalContent) ProposalKind {
+	if content.ProposalRoute() == SoftwareUpgradeRoute {
+		return ProposalTypeSoftwareUpgrade
+	}
+	return ProposalTypeModule
+}
+
+// do proposals of the kind carry a content executed by the proposal router?
+func (pt ProposalKind) hasContent() bool {
+	return pt == ProposalTypeSoftwareUpgrade || pt == ProposalTypeModule
+}
+
 // String to proposalType byte.  Returns ff if invalid.
 func ProposalTypeFromString(str string) (ProposalKind, error) {
 	switch str {
@@ -117,6 +159,8 @@ func ProposalTypeFromString(str string) (ProposalKind, error) {
 		return ProposalTypeParameterChange, nil
 	case "SoftwareUpgrade":
 		return ProposalTypeSoftwareUpgrade, nil
+	case "Module":
+		return ProposalTypeModule, nil
 	default:
 		return ProposalKind(0xff), errors.Errorf("'%s' is not a valid proposal type", str)
 	}
@@ -126,7 +170,8 @@ func ProposalTypeFromString(str string) (ProposalKind, error) {
 func validProposalType(pt ProposalKind) bool {
 	if pt == ProposalTypeText ||
 		pt == ProposalTypeParameterChange ||
-		pt == ProposalTypeSoftwareUpgrade {
+		pt == ProposalTypeSoftwareUpgrade ||
+		pt == ProposalTypeModule {
 		return true
 	}
 	return false
@@ -173,6 +218,8 @@ func (pt ProposalKind) String() string {
 		return "ParameterChange"
 	case ProposalTypeSoftwareUpgrade:
 		return "SoftwareUpgrade"
+	case ProposalTypeModule:
+		return "Module"
 	default:
 		return ""
 	}
@@ -0,0 +1,145 @@
+package gov
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// query endpoints supported by the governance Querier
+const (
+	QueryProposal = "proposal"
+	QueryDeposit  = "deposit"
+	QueryDeposits = "deposits"
+	QueryVote     = "vote"
+	QueryVotes    = "votes"
+)
+
+// NewQuerier returns the sdk.Querier answering "custom/gov/<endpoint>" queries
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("no gov query endpoint specified")
+		}
+		switch path[0] {
+		case QueryProposal:
+			return queryProposal(ctx, req, keeper)
+		case QueryDeposit:
+			return queryDeposit(ctx, req, keeper)
+		case QueryDeposits:
+			return queryDeposits(ctx, req, keeper)
+		case QueryVote:
+			return queryVote(ctx, req, keeper)
+		case QueryVotes:
+			return queryVotes(ctx, req, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown gov query endpoint %s", path[0]))
+		}
+	}
+}
+
+// Params for query 'custom/gov/proposal', 'custom/gov/deposits' and 'custom/gov/votes'
+type QueryProposalParams struct {
+	ProposalID int64 `json:"proposal_id"`
+}
+
+// Params for query 'custom/gov/deposit'
+type QueryDepositParams struct {
+	ProposalID int64          `json:"proposal_id"`
+	Depositer  sdk.AccAddress `json:"depositer"`
+}
+
+// Params for query 'custom/gov/vote'
+type QueryVoteParams struct {
+	ProposalID int64          `json:"proposal_id"`
+	Voter      sdk.AccAddress `json:"voter"`
+}
+
+func queryProposal(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryProposalParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	proposal := keeper.GetProposal(ctx, params.ProposalID)
+	if proposal == nil {
+		return nil, ErrUnknownProposal(keeper.codespace, params.ProposalID)
+	}
+	return marshalQueryResult(keeper.cdc, proposal)
+}
+
+func queryDeposit(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryDepositParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	deposit, found := keeper.GetDeposit(ctx, params.ProposalID, params.Depositer)
+	if !found {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("no deposit from %s on proposal %d", params.Depositer, params.ProposalID))
+	}
+	return marshalQueryResult(keeper.cdc, deposit)
+}
+
+func queryDeposits(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryProposalParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	deposits := []Deposit{}
+	depositsIterator := keeper.GetDeposits(ctx, params.ProposalID)
+	for ; depositsIterator.Valid(); depositsIterator.Next() {
+		deposit := Deposit{}
+		keeper.cdc.MustUnmarshalBinary(depositsIterator.Value(), &deposit)
+		deposits = append(deposits, deposit)
+	}
+	depositsIterator.Close()
+	return marshalQueryResult(keeper.cdc, deposits)
+}
+
+func queryVote(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryVoteParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	vote, found := keeper.GetVote(ctx, params.ProposalID, params.Voter)
+	if !found {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("no vote from %s on proposal %d", params.Voter, params.ProposalID))
+	}
+	return marshalQueryResult(keeper.cdc, vote)
+}
+
+func queryVotes(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryProposalParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	votes := []Vote{}
+	votesIterator := keeper.GetVotes(ctx, params.ProposalID)
+	for ; votesIterator.Valid(); votesIterator.Next() {
+		vote := Vote{}
+		keeper.cdc.MustUnmarshalBinary(votesIterator.Value(), &vote)
+		votes = append(votes, vote)
+	}
+	votesIterator.Close()
+	return marshalQueryResult(keeper.cdc, votes)
+}
+
+func marshalQueryResult(cdc *wire.Codec, result interface{}) (res []byte, err sdk.Error) {
+	bz, errRes := wire.MarshalJSONIndent(cdc, result)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("could not marshal result to JSON - %s", errRes.Error()))
+	}
+	return bz, nil
+}
@@ -0,0 +1,75 @@
+package gov
+
+import (
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ProposalContent - the content of a ModuleProposal, executed by the module
+// it is routed to when the proposal passes
+type ProposalContent interface {
+	ProposalRoute() string // route of the proposal handler of the content
+	ValidateBasic() sdk.Error
+	String() string
+}
+
+// ProposalHandler checks and executes the content of the module proposals
+// routed to a module
+type ProposalHandler interface {
+	// checks the content against the state when the proposal is submitted
+	CheckProposal(ctx sdk.Context, content ProposalContent) sdk.Error
+
+	// executes the content once the proposal passes, in a cache context which
+	// is discarded if an error is returned
+	ExecuteProposal(ctx sdk.Context, content ProposalContent) sdk.Error
+}
+
+// ProposalRouter provides the proposal handlers registered by the app for
+// each route of proposal contents
+type ProposalRouter interface {
+	AddRoute(r string, h ProposalHandler) (rtr ProposalRouter)
+	Route(path string) (h ProposalHandler)
+}
+
+// map a proposal content route to a proposal handler
+type proposalRoute struct {
+	r string
+	h ProposalHandler
+}
+
+type proposalRouter struct {
+	routes []proposalRoute
+}
+
+// NewProposalRouter - create new proposal router
+func NewProposalRouter() ProposalRouter {
+	return &proposalRouter{
+		routes: make([]proposalRoute, 0),
+	}
+}
+
+var isAlpha = regexp.MustCompile(`^[a-zA-Z]+$`).MatchString
+
+// AddRoute - register the proposal handler of a route
+func (rtr *proposalRouter) AddRoute(r string, h ProposalHandler) ProposalRouter {
+	if !isAlpha(r) {
+		panic("route expressions can only contain alphabet characters")
+	}
+	if rtr.Route(r) != nil {
+		panic("route " + r + " has already been registered")
+	}
+	rtr.routes = append(rtr.routes, proposalRoute{r, h})
+
+	return rtr
+}
+
+// Route - get the proposal handler of a route, nil if none is registered
+func (rtr *proposalRouter) Route(path string) (h ProposalHandler) {
+	for _, route := range rtr.routes {
+		if route.r == path {
+			return route.h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package gov
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// content of a test module proposal, setting a key of the gov store
+type testProposalContent struct {
+	Key  string `json:"key"`
+	Fail bool   `json:"fail"` // execution fails after setting the key
+}
+
+func (c testProposalContent) ProposalRoute() string { return "test" }
+func (c testProposalContent) String() string        { return c.Key }
+func (c testProposalContent) ValidateBasic() sdk.Error {
+	if len(c.Key) == 0 {
+		return sdk.ErrUnknownRequest("empty key")
+	}
+	return nil
+}
+
+func init() {
+	RegisterProposalContent(testProposalContent{}, "gov/testProposalContent")
+}
+
+// proposal handler of the test contents, rejecting the key "rejected" on submission
+type testProposalHandler struct {
+	keeper Keeper
+}
+
+func (h testProposalHandler) CheckProposal(ctx sdk.Context, content ProposalContent) sdk.Error {
+	if content.(testProposalContent).Key == "rejected" {
+		return sdk.ErrUnknownRequest("rejected key")
+	}
+	return nil
+}
+
+func (h testProposalHandler) ExecuteProposal(ctx sdk.Context, content ProposalContent) sdk.Error {
+	c := content.(testProposalContent)
+	ctx.KVStore(h.keeper.storeKey).Set([]byte(c.Key), []byte{0x01})
+	if c.Fail {
+		return sdk.ErrUnknownRequest("failed execution")
+	}
+	return nil
+}
+
+func TestProposalRouter(t *testing.T) {
+	handler := testProposalHandler{}
+	router := NewProposalRouter().AddRoute("test", handler)
+	require.Equal(t, handler, router.Route("test"))
+	require.Nil(t, router.Route("unknown"))
+
+	// routes are alphabetic and registered once
+	require.Panics(t, func() { router.AddRoute("test", handler) })
+	require.Panics(t, func() { router.AddRoute("test/1", handler) })
+}
@@ -14,6 +14,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 )
 
@@ -26,13 +27,15 @@ func getMockApp(t *testing.T, numGenAccs int) (*mock.App, Keeper, stake.Keeper,
 
 	keyStake := sdk.NewKVStoreKey("stake")
 	keyGov := sdk.NewKVStoreKey("gov")
+	keyParams := sdk.NewKVStoreKey("params")
 
 	ck := bank.NewKeeper(mapp.AccountMapper)
-	sk := stake.NewKeeper(mapp.Cdc, keyStake, ck, mapp.RegisterCodespace(stake.DefaultCodespace))
-	keeper := NewKeeper(mapp.Cdc, keyGov, ck, sk, DefaultCodespace)
+	pk := params.NewKeeper(mapp.Cdc, keyParams)
+	sk := stake.NewKeeper(mapp.Cdc, keyStake, ck, pk.Subspace(stake.DefaultParamspace, stake.ParamTypeTable()), mapp.RegisterCodespace(stake.DefaultCodespace))
+	keeper := NewKeeper(mapp.Cdc, keyGov, pk, pk.Subspace(DefaultParamspace, ParamTypeTable()), ck, sk, NewProposalRouter(), DefaultCodespace)
 	mapp.Router().AddRoute("gov", NewHandler(keeper))
 
-	require.NoError(t, mapp.CompleteSetup([]*sdk.KVStoreKey{keyStake, keyGov}))
+	require.NoError(t, mapp.CompleteSetup([]*sdk.KVStoreKey{keyStake, keyGov, keyParams}))
 
 	mapp.SetEndBlocker(getEndBlocker(keeper))
 	mapp.SetInitChainer(getInitChainer(mapp, keeper, sk))
@@ -65,11 +68,19 @@ func getInitChainer(mapp *mock.App, keeper Keeper, stakeKeeper stake.Keeper) sdk
 		if err != nil {
 			panic(err)
 		}
-		InitGenesis(ctx, keeper, DefaultGenesisState())
+		InitGenesis(ctx, keeper, testGenesisState())
 		return abci.ResponseInitChain{}
 	}
 }
 
+// genesis with shorter deposit and voting periods, lest the tests take forever
+func testGenesisState() GenesisState {
+	genesis := DefaultGenesisState()
+	genesis.DepositProcedure.MaxDepositPeriod = 200
+	genesis.VotingProcedure.VotingPeriod = 200
+	return genesis
+}
+
 // Sorts Addresses
 func SortAddresses(addrs []sdk.AccAddress) {
 	var byteAddrs [][]byte
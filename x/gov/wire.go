@@ -13,6 +13,22 @@ func RegisterWire(cdc *wire.Codec) {
 
 	cdc.RegisterInterface((*Proposal)(nil), nil)
 	cdc.RegisterConcrete(&TextProposal{}, "gov/TextProposal", nil)
+	cdc.RegisterConcrete(&ParameterChangeProposal{}, "gov/ParameterChangeProposal", nil)
+	cdc.RegisterConcrete(&ModuleProposal{}, "gov/ModuleProposal", nil)
+
+	// the concrete contents are registered by the modules handling them
+	cdc.RegisterInterface((*ProposalContent)(nil), nil)
+}
+
+// RegisterProposalContent registers the concrete type of a proposal content on
+// the codec gov messages are signed with, modules call it on init along with
+// registering the type on the app codec in their RegisterWire
+func RegisterProposalContent(content interface{}, name string) {
+	msgCdc.RegisterConcrete(content, name, nil)
 }
 
 var msgCdc = wire.NewCodec()
+
+func init() {
+	msgCdc.RegisterInterface((*ProposalContent)(nil), nil)
+}
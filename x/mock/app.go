@@ -32,6 +32,11 @@ type App struct {
 
 	GenesisAccounts  []auth.Account
 	TotalCoinsSupply sdk.Coins
+
+	// upper bound of the random time, in seconds, between two blocks of the
+	// randomized testing, which then begins the blocks with their header.
+	// Zero, the default, keeps the block time at zero.
+	MaxBlockTime int64
 }
 
 // NewApp partially constructs a new app on the memstore for module and genesis
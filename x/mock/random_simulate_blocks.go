@@ -39,7 +39,12 @@ func (app *App) RandomizedTestingFromSeed(
 	header := abci.Header{Height: 0}
 
 	for i := 0; i < numBlocks; i++ {
-		app.BeginBlock(abci.RequestBeginBlock{})
+		// blocks only carry their header if the app advances the block time
+		req := abci.RequestBeginBlock{}
+		if app.MaxBlockTime > 0 {
+			req.Header = header
+		}
+		app.BeginBlock(req)
 
 		// Make sure invariants hold at beginning of block and when nothing was
 		// done.
@@ -59,6 +64,9 @@ func (app *App) RandomizedTestingFromSeed(
 
 		app.EndBlock(abci.RequestEndBlock{})
 		header.Height++
+		if app.MaxBlockTime > 0 {
+			header.Time += r.Int63n(app.MaxBlockTime) + 1
+		}
 	}
 }
 
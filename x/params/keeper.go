@@ -0,0 +1,98 @@
+package params
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// Keeper of the global parameter store, shared by every module through
+// namespaced subspaces
+type Keeper struct {
+	// The (unexposed) key used to access the params store from the Context.
+	key sdk.StoreKey
+
+	// The wire codec for binary encoding/decoding of parameters.
+	cdc *wire.Codec
+
+	// subspaces created so far, by name
+	spaces map[string]Subspace
+}
+
+// NewKeeper creates a params keeper
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey) Keeper {
+	return Keeper{
+		key:    key,
+		cdc:    cdc,
+		spaces: make(map[string]Subspace),
+	}
+}
+
+// Subspace creates the subspace with the given name, holding the parameter
+// keys and types of the table. Panics if the subspace already exists.
+func (k Keeper) Subspace(name string, table TypeTable) Subspace {
+	if name == "" {
+		panic("cannot create a params subspace with an empty name")
+	}
+	if _, ok := k.spaces[name]; ok {
+		panic(fmt.Sprintf("params subspace %s already exists", name))
+	}
+
+	space := Subspace{
+		key:   k.key,
+		cdc:   k.cdc,
+		name:  []byte(name + "/"),
+		table: table,
+	}
+	k.spaces[name] = space
+	return space
+}
+
+// GetSubspace returns the existing subspace with the given name
+func (k Keeper) GetSubspace(name string) (Subspace, bool) {
+	space, ok := k.spaces[name]
+	return space, ok
+}
+
+// SetRaw sets a parameter from its JSON encoded value, after checking that
+// the subspace and key exist and that the value decodes to the registered type
+// and passes its validator
+func (k Keeper) SetRaw(ctx sdk.Context, change ParamChange) error {
+	space, ok := k.GetSubspace(change.Subspace)
+	if !ok {
+		return fmt.Errorf("unknown params subspace %s", change.Subspace)
+	}
+	return space.SetRaw(ctx, change.Key, []byte(change.Value))
+}
+
+// ValidateChange checks a parameter change without applying it, as SetRaw does
+func (k Keeper) ValidateChange(change ParamChange) error {
+	space, ok := k.GetSubspace(change.Subspace)
+	if !ok {
+		return fmt.Errorf("unknown params subspace %s", change.Subspace)
+	}
+	_, err := space.decode(change.Key, []byte(change.Value))
+	return err
+}
+
+// ParamChange - new JSON encoded value for a parameter of a subspace
+type ParamChange struct {
+	Subspace string `json:"subspace"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// NewParamChange creates a ParamChange
+func NewParamChange(subspace, key, value string) ParamChange {
+	return ParamChange{
+		Subspace: subspace,
+		Key:      key,
+		Value:    value,
+	}
+}
+
+// String implements fmt.Stringer
+func (change ParamChange) String() string {
+	return fmt.Sprintf("%s/%s: %s", change.Subspace, change.Key, change.Value)
+}
@@ -0,0 +1,104 @@
+package params
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+type testParams struct {
+	Threshold sdk.Rat `json:"threshold"`
+	Period    int64   `json:"period"`
+}
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper) {
+	key := sdk.NewKVStoreKey("params")
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), key)
+}
+
+func TestSubspace(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+
+	foo := keeper.Subspace("foo", NewTypeTable().
+		RegisterType("params", testParams{}, nil).
+		RegisterType("denom", new(string), nil))
+	bar := keeper.Subspace("bar", NewTypeTable().RegisterType("denom", "", nil))
+	require.Panics(t, func() { keeper.Subspace("foo", NewTypeTable()) })
+
+	space, ok := keeper.GetSubspace("foo")
+	require.True(t, ok)
+	require.Equal(t, "foo", space.Name())
+	_, ok = keeper.GetSubspace("baz")
+	require.False(t, ok)
+
+	// subspaces don't share keys
+	require.Panics(t, func() {
+		var denom string
+		foo.Get(ctx, "denom", &denom)
+	})
+	foo.Set(ctx, "denom", "steak")
+	require.False(t, bar.Has(ctx, "denom"))
+	denom := "default"
+	bar.GetIfExists(ctx, "denom", &denom)
+	require.Equal(t, "default", denom)
+	foo.GetIfExists(ctx, "denom", &denom)
+	require.Equal(t, "steak", denom)
+
+	// only registered keys and types can be set
+	require.Panics(t, func() { foo.Set(ctx, "unknown", "steak") })
+	require.Panics(t, func() { foo.Set(ctx, "denom", int64(1)) })
+
+	expected := testParams{sdk.NewRat(1, 2), 10}
+	foo.Set(ctx, "params", expected)
+	var params testParams
+	foo.Get(ctx, "params", &params)
+	require.True(t, expected.Threshold.Equal(params.Threshold))
+	require.Equal(t, expected.Period, params.Period)
+}
+
+func TestSetRaw(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+	space := keeper.Subspace("foo", NewTypeTable().RegisterType("params", testParams{}, func(value interface{}) error {
+		if value.(testParams).Period <= 0 {
+			return errors.New("period must be positive")
+		}
+		return nil
+	}))
+
+	tests := []struct {
+		change ParamChange
+		valid  bool
+	}{
+		{NewParamChange("foo", "params", `{"threshold":"1/3","period":"20"}`), true},
+		{NewParamChange("bar", "params", `{"threshold":"1/3","period":"20"}`), false},
+		{NewParamChange("foo", "unknown", `{"threshold":"1/3","period":"20"}`), false},
+		{NewParamChange("foo", "params", `{"threshold":1}`), false},
+		{NewParamChange("foo", "params", `not json`), false},
+		{NewParamChange("foo", "params", `{"threshold":"1/2","period":"0"}`), false},
+	}
+
+	for i, tc := range tests {
+		require.Equal(t, tc.valid, keeper.ValidateChange(tc.change) == nil, "test case %d", i)
+		require.Equal(t, tc.valid, keeper.SetRaw(ctx, tc.change) == nil, "test case %d", i)
+	}
+
+	var params testParams
+	space.Get(ctx, "params", &params)
+	require.True(t, sdk.NewRat(1, 3).Equal(params.Threshold))
+	require.Equal(t, int64(20), params.Period)
+}
@@ -0,0 +1,137 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// ValidatorFn checks a value of the registered type of a parameter, before it
+// is set by a params change
+type ValidatorFn func(value interface{}) error
+
+// the registered type of a parameter along with its validator
+type paramType struct {
+	ty       reflect.Type
+	validate ValidatorFn
+}
+
+// TypeTable - the parameter keys of a subspace along with the type of value
+// each of them holds and the function validating those values
+type TypeTable map[string]paramType
+
+// NewTypeTable creates an empty TypeTable
+func NewTypeTable() TypeTable {
+	return make(TypeTable)
+}
+
+// RegisterType registers the parameter key with the type of the given value,
+// which may also be passed as a pointer, and the validator of its values. A
+// nil validator accepts any value decoding to the type.
+func (t TypeTable) RegisterType(key string, value interface{}, validate ValidatorFn) TypeTable {
+	if _, ok := t[key]; ok {
+		panic(fmt.Sprintf("parameter key %s already registered", key))
+	}
+	ty := reflect.TypeOf(value)
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	t[key] = paramType{ty, validate}
+	return t
+}
+
+// Subspace - the parameters of a single module, stored under the prefix
+// "<name>/" in the params store
+type Subspace struct {
+	key   sdk.StoreKey
+	cdc   *wire.Codec
+	name  []byte
+	table TypeTable
+}
+
+// Name returns the name of the subspace
+func (s Subspace) Name() string {
+	return string(s.name[:len(s.name)-1])
+}
+
+// get the key for a parameter of the subspace
+// VALUE: amino binary encoding of the registered type
+func (s Subspace) paramKey(key string) []byte {
+	return append(append([]byte{}, s.name...), []byte(key)...)
+}
+
+// Get the parameter into ptr, panics if it has not been set
+func (s Subspace) Get(ctx sdk.Context, key string, ptr interface{}) {
+	store := ctx.KVStore(s.key)
+	bz := store.Get(s.paramKey(key))
+	if bz == nil {
+		panic(fmt.Sprintf("parameter %s/%s has not been set", s.Name(), key))
+	}
+	s.cdc.MustUnmarshalBinary(bz, ptr)
+}
+
+// GetIfExists gets the parameter into ptr, leaving it untouched if the
+// parameter has not been set
+func (s Subspace) GetIfExists(ctx sdk.Context, key string, ptr interface{}) {
+	store := ctx.KVStore(s.key)
+	bz := store.Get(s.paramKey(key))
+	if bz == nil {
+		return
+	}
+	s.cdc.MustUnmarshalBinary(bz, ptr)
+}
+
+// Has checks if the parameter has been set
+func (s Subspace) Has(ctx sdk.Context, key string) bool {
+	store := ctx.KVStore(s.key)
+	return store.Has(s.paramKey(key))
+}
+
+// Set the parameter, panics if the key is not registered or the value is
+// not of the registered type
+func (s Subspace) Set(ctx sdk.Context, key string, param interface{}) {
+	pt, ok := s.table[key]
+	if !ok {
+		panic(fmt.Sprintf("parameter %s/%s not registered", s.Name(), key))
+	}
+	if reflect.TypeOf(param) != pt.ty {
+		panic(fmt.Sprintf("parameter %s/%s expects type %s, got %T", s.Name(), key, pt.ty, param))
+	}
+
+	store := ctx.KVStore(s.key)
+	store.Set(s.paramKey(key), s.cdc.MustMarshalBinary(param))
+}
+
+// SetRaw sets the parameter from its JSON encoded value, once validated
+func (s Subspace) SetRaw(ctx sdk.Context, key string, value []byte) error {
+	param, err := s.decode(key, value)
+	if err != nil {
+		return err
+	}
+	s.Set(ctx, key, param)
+	return nil
+}
+
+// decode a JSON encoded value into the registered type of the parameter and
+// validate it
+func (s Subspace) decode(key string, value []byte) (interface{}, error) {
+	pt, ok := s.table[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s/%s", s.Name(), key)
+	}
+	ptr := reflect.New(pt.ty)
+	err := s.cdc.UnmarshalJSON(value, ptr.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter %s/%s: %s", s.Name(), key, err.Error())
+	}
+	param := ptr.Elem().Interface()
+	if pt.validate != nil {
+		err = pt.validate(param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter %s/%s: %s", s.Name(), key, err.Error())
+		}
+	}
+	return param, nil
+}
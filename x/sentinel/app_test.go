@@ -0,0 +1,141 @@
+package sentinel
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/sentinel/tags"
+)
+
+// getMockApp returns an initialized mock application for this module.
+func getMockApp(t *testing.T) (*mock.App, Keeper) {
+	mApp := mock.NewApp()
+	// advance the block time by up to ten minutes per block
+	mApp.MaxBlockTime = 600
+
+	RegisterWire(mApp.Cdc)
+
+	keySentinel := sdk.NewKVStoreKey("sentinel")
+	keyParams := sdk.NewKVStoreKey("params")
+	coinKeeper := bank.NewKeeper(mApp.AccountMapper)
+	paramsKeeper := params.NewKeeper(mApp.Cdc, keyParams)
+	keeper := NewKeeper(mApp.Cdc, keySentinel, coinKeeper, mApp.AccountMapper, paramsKeeper.Subspace(DefaultParamspace, ParamTypeTable()), mApp.RegisterCodespace(DefaultCodeSpace))
+
+	mApp.Router().AddRoute("sentinel", NewHandler(keeper))
+	mApp.SetEndBlocker(getEndBlocker(mApp, keeper))
+	mApp.SetInitChainer(getInitChainer(mApp, keeper))
+
+	require.NoError(t, mApp.CompleteSetup([]*sdk.KVStoreKey{keySentinel, keyParams}))
+	return mApp, keeper
+}
+
+// getEndBlocker returns a sentinel endblocker. The deposits slashed by the
+// EndBlocker, reported in the amount tags of the resolved disputes and
+// tallied proposals, are burned, so that the supply of the mock app drops by
+// them. Any other change of the total coins panics.
+func getEndBlocker(mapp *mock.App, keeper Keeper) sdk.EndBlocker {
+	return func(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+		before := totalCoins(ctx, mapp, keeper)
+		resTags := EndBlocker(ctx, keeper)
+		burned := before.Minus(totalCoins(ctx, mapp, keeper))
+		slashed := slashedCoins(resTags)
+		if !burned.IsEqual(slashed) {
+			panic(fmt.Sprintf("EndBlocker burned %s, but reported %s slashed", burned, slashed))
+		}
+		mapp.TotalCoinsSupply = mapp.TotalCoinsSupply.Minus(burned)
+		return abci.ResponseEndBlock{
+			Tags: resTags,
+		}
+	}
+}
+
+// sum the amount tags of the resolved disputes and tallied proposals
+func slashedCoins(resTags sdk.Tags) (slashed sdk.Coins) {
+	var action []byte
+	for _, tag := range resTags {
+		switch string(tag.Key) {
+		case tags.Action:
+			action = tag.Value
+		case tags.Amount:
+			if !bytes.Equal(action, tags.ActionResolveDispute) && !bytes.Equal(action, tags.ActionTallyProposal) {
+				continue
+			}
+			coins, err := sdk.ParseCoins(string(tag.Value))
+			if err != nil {
+				panic(err)
+			}
+			slashed = slashed.Plus(coins)
+		}
+	}
+	return slashed
+}
+
+// getInitChainer initializes the chainer of the mock app and sets the genesis
+// state. The params allow the coins of the mock accounts, and their periods
+// are a few blocks of the mock app long, so that the sessions, disputes,
+// proposals, subscriptions, heartbeats and deposits run out during the test.
+func getInitChainer(mapp *mock.App, keeper Keeper) sdk.InitChainer {
+	return func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		mapp.InitChainer(ctx, req)
+
+		sentinelGenesis := DefaultGenesisState()
+		sentinelGenesis.Params.AllowedDenoms = []string{"foocoin"}
+		sentinelGenesis.Params.MinDeposit = sdk.Coins{sdk.NewCoin("foocoin", 1)}
+		sentinelGenesis.Params.MinMasterBond = sdk.NewCoin("foocoin", 1)
+		sentinelGenesis.Params.RefundTimeout = 2 * mapp.MaxBlockTime
+		sentinelGenesis.Params.PriceChangeInterval = 2 * mapp.MaxBlockTime
+		sentinelGenesis.Params.SessionTimeout = 8 * mapp.MaxBlockTime
+		sentinelGenesis.Params.ChallengePeriod = 2 * mapp.MaxBlockTime
+		sentinelGenesis.Params.DisputePeriod = 3 * mapp.MaxBlockTime
+		sentinelGenesis.Params.VotingPeriod = 3 * mapp.MaxBlockTime
+		sentinelGenesis.Params.HeartbeatWindow = 2 * mapp.MaxBlockTime
+		sentinelGenesis.Params.UnbondingTime = 4 * mapp.MaxBlockTime
+
+		err := InitGenesis(ctx, keeper, sentinelGenesis)
+		if err != nil {
+			panic(err)
+		}
+
+		return abci.ResponseInitChain{}
+	}
+}
+
+func TestSentinelWithRandomMessages(t *testing.T) {
+	mapp, keeper := getMockApp(t)
+	setup := func(r *rand.Rand, keys []crypto.PrivKey) {
+		return
+	}
+
+	mapp.RandomizedTesting(
+		t,
+		[]mock.TestAndRunTx{
+			TestAndRunRegisterVpnService(keeper),
+			TestAndRunUpdateVpnService(keeper),
+			TestAndRunDeleteVpnService(keeper),
+			TestAndRunPayVpnService(keeper),
+			TestAndRunGetVpnPayment(keeper),
+			TestAndRunRefund(keeper),
+			TestAndRunRegisterMasterNode(keeper),
+			TestAndRunOpenDispute(keeper),
+			TestAndRunDisputeVerdict(keeper),
+			TestAndRunSubmitSlashProposal(keeper),
+			TestAndRunVote(keeper),
+			TestAndRunAddPlan(keeper),
+			TestAndRunSubscribe(keeper),
+			TestAndRunNodeHeartbeat(keeper),
+		},
+		[]mock.RandSetup{setup},
+		[]mock.Invariant{ModuleInvariants(keeper)},
+		100, 60, 30,
+	)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	flag "github.com/spf13/pflag"
+)
+
+// nolint
+const (
+	FlagAddress = "address"
+
+	FlagMoniker       = "moniker"
+	FlagEndpoint      = "endpoint"
+	FlagUploadSpeed   = "upload-speed"
+	FlagDownloadSpeed = "download-speed"
+	FlagPricePerGb    = "price-per-gb"
+	FlagEncMethod     = "enc-method"
+	FlagLatitude      = "latitude"
+	FlagLongitude     = "longitude"
+	FlagCity          = "city"
+	FlagCountry       = "country"
+	FlagNodeType      = "node-type"
+	FlagVersion       = "version"
+	FlagDeposit       = "deposit"
+	FlagFraction      = "fraction"
+	FlagRating        = "rating"
+	FlagSessionId     = "session-id"
+	FlagVerdict       = "verdict"
+	FlagNodes         = "nodes"
+	FlagPrice         = "price"
+	FlagDuration      = "duration"
+	FlagDataCap       = "data-cap"
+	FlagPlanId        = "plan-id"
+	FlagBond          = "bond"
+	FlagKind          = "kind"
+	FlagParams        = "params"
+	FlagProposalId    = "proposal-id"
+	FlagOption        = "option"
+	FlagSessions      = "sessions"
+	FlagLoad          = "load"
+
+	FlagMaxPricePerGb    = "max-price-per-gb"
+	FlagMinUploadSpeed   = "min-upload-speed"
+	FlagMinDownloadSpeed = "min-download-speed"
+	FlagSortBy           = "sort-by"
+	FlagClient           = "client"
+	FlagNode             = "node"
+	FlagStatus           = "status"
+	FlagProvider         = "provider"
+	FlagPage             = "page"
+	FlagLimit            = "limit"
+)
+
+// common flagsets to add to various functions
+var (
+	fsNode           = flag.NewFlagSet("", flag.ContinueOnError)
+	fsNodeUpdate     = flag.NewFlagSet("", flag.ContinueOnError)
+	fsNodeFilters    = flag.NewFlagSet("", flag.ContinueOnError)
+	fsSessionFilters = flag.NewFlagSet("", flag.ContinueOnError)
+	fsPlanFilters    = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func init() {
+	fsNode.String(FlagMoniker, "", "dVPN node name")
+	fsNode.StringSlice(FlagEndpoint, nil, "public endpoint of the dVPN node as host:port/protocol, e.g. [2001:db8::1]:51820/udp (repeatable)")
+	fsNode.Int64(FlagUploadSpeed, 0, "upload speed of the dVPN node")
+	fsNode.Int64(FlagDownloadSpeed, 0, "download speed of the dVPN node")
+	fsNode.String(FlagPricePerGb, "", "price per GB of bandwidth, e.g. 10sut")
+	fsNode.String(FlagEncMethod, "", "encryption method of the dVPN node")
+	fsNode.Int64(FlagLatitude, 0, "latitude of the dVPN node, multiplied by 10000")
+	fsNode.Int64(FlagLongitude, 0, "longitude of the dVPN node, multiplied by 10000")
+	fsNode.String(FlagCity, "", "city of the dVPN node")
+	fsNode.String(FlagCountry, "", "country of the dVPN node")
+	fsNode.String(FlagNodeType, "", "type of the dVPN node")
+	fsNode.String(FlagVersion, "", "software version of the dVPN node")
+	fsNode.String(FlagDeposit, "", "deposit locked while the dVPN node is registered, e.g. 100sut")
+
+	fsNodeUpdate.String(FlagMoniker, "", "new dVPN node name")
+	fsNodeUpdate.StringSlice(FlagEndpoint, nil, "new public endpoint of the dVPN node as host:port/protocol, replacing all the endpoints (repeatable)")
+	fsNodeUpdate.Int64(FlagUploadSpeed, 0, "new upload speed of the dVPN node")
+	fsNodeUpdate.Int64(FlagDownloadSpeed, 0, "new download speed of the dVPN node")
+	fsNodeUpdate.String(FlagPricePerGb, "", "new price per GB of bandwidth, e.g. 10sut")
+	fsNodeUpdate.String(FlagEncMethod, "", "new encryption method of the dVPN node")
+	fsNodeUpdate.String(FlagNodeType, "", "new type of the dVPN node")
+	fsNodeUpdate.String(FlagVersion, "", "new software version of the dVPN node")
+
+	fsNodeFilters.String(FlagCountry, "", "only list dVPN nodes in this country")
+	fsNodeFilters.String(FlagCity, "", "only list dVPN nodes in this city")
+	fsNodeFilters.String(FlagMaxPricePerGb, "", "only list dVPN nodes accepting one of these coins at no more than this per GB")
+	fsNodeFilters.Int64(FlagMinUploadSpeed, 0, "only list dVPN nodes with at least this upload speed")
+	fsNodeFilters.Int64(FlagMinDownloadSpeed, 0, "only list dVPN nodes with at least this download speed")
+	fsNodeFilters.String(FlagEncMethod, "", "only list dVPN nodes with this encryption method")
+	fsNodeFilters.String(FlagNodeType, "", "only list dVPN nodes of this type")
+	fsNodeFilters.String(FlagVersion, "", "only list dVPN nodes running this software version")
+	fsNodeFilters.String(FlagSortBy, "", "order the dVPN nodes by descending rating, completion, bandwidth or sessions instead of by address")
+	fsNodeFilters.Int(FlagPage, 1, "page of the results to list")
+	fsNodeFilters.Int(FlagLimit, 30, "number of dVPN nodes per page")
+
+	fsSessionFilters.String(FlagClient, "", "bech32 address of the client of the sessions")
+	fsSessionFilters.String(FlagNode, "", "bech32 address of the dVPN node of the sessions")
+	fsSessionFilters.String(FlagStatus, "", "only list sessions with this status: active, closing or disputed")
+	fsSessionFilters.Int(FlagPage, 1, "page of the results to list")
+	fsSessionFilters.Int(FlagLimit, 30, "number of sessions per page")
+
+	fsPlanFilters.String(FlagProvider, "", "only list plans published by this dVPN node or master node")
+	fsPlanFilters.String(FlagNode, "", "only list plans served by this dVPN node")
+	fsPlanFilters.Int(FlagPage, 1, "page of the results to list")
+	fsPlanFilters.Int(FlagLimit, 30, "number of plans per page")
+}
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/sentinel"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// get the command to query a dVPN node
+func GetCmdQueryNode(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node [address]",
+		Short: "Query a dVPN node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryStore(sentinel.GetVpnServiceKey(addr), storeName)
+			if err != nil {
+				return err
+			} else if len(res) == 0 {
+				return fmt.Errorf("No dVPN node found with address %s", args[0])
+			}
+
+			var vpn senttype.Registervpn
+			cdc.MustUnmarshalBinary(res, &vpn)
+			output, err := wire.MarshalJSONIndent(cdc, sentinel.VpnNode{Address: addr, Node: vpn})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to list the dVPN nodes
+func GetCmdQueryNodes(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Query for the dVPN nodes, optionally filtered",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxPricePerGb, err := sdk.ParseCoins(viper.GetString(FlagMaxPricePerGb))
+			if err != nil {
+				return err
+			}
+
+			params := sentinel.QueryNodesParams{
+				Country:          viper.GetString(FlagCountry),
+				City:             viper.GetString(FlagCity),
+				MaxPricePerGb:    maxPricePerGb,
+				MinUploadSpeed:   viper.GetInt64(FlagMinUploadSpeed),
+				MinDownloadSpeed: viper.GetInt64(FlagMinDownloadSpeed),
+				EncMethod:        viper.GetString(FlagEncMethod),
+				NodeType:         viper.GetString(FlagNodeType),
+				Version:          viper.GetString(FlagVersion),
+				SortBy:           viper.GetString(FlagSortBy),
+				Page:             viper.GetInt(FlagPage),
+				Limit:            viper.GetInt(FlagLimit),
+			}
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryNodes), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsNodeFilters)
+	return cmd
+}
+
+// get the command to list the sessions of a client or a dVPN node
+func GetCmdQuerySessions(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Query for the open sessions of a client or a dVPN node, with their locked, released and remaining coins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := sentinel.QuerySessionsParams{
+				Status: viper.GetString(FlagStatus),
+				Page:   viper.GetInt(FlagPage),
+				Limit:  viper.GetInt(FlagLimit),
+			}
+			var err error
+			if client := viper.GetString(FlagClient); client != "" {
+				params.Client, err = sdk.AccAddressFromBech32(client)
+				if err != nil {
+					return err
+				}
+			}
+			if node := viper.GetString(FlagNode); node != "" {
+				params.Node, err = sdk.AccAddressFromBech32(node)
+				if err != nil {
+					return err
+				}
+			}
+			if len(params.Client) == 0 && len(params.Node) == 0 {
+				return fmt.Errorf("--%s or --%s is required", FlagClient, FlagNode)
+			}
+
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QuerySessions), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsSessionFilters)
+	return cmd
+}
+
+// get the command to query the change history of a dVPN node
+func GetCmdQueryNodeHistory(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node-history [address]",
+		Short: "Query the changes of a dVPN node, oldest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(sentinel.QueryNodeParams{Address: addr})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryNodeHistory), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to query a master node along with its bond
+func GetCmdQueryMasterNode(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "master-node [address]",
+		Short: "Query a master node and its bond",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(sentinel.QueryNodeParams{Address: addr})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryMasterNode), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to query the dispute of a session
+func GetCmdQueryDispute(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dispute [session-id]",
+		Short: "Query the dispute of a session along with the verdicts of the master nodes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			bz, err := cdc.MarshalJSON(sentinel.QuerySessionParams{SessionId: args[0]})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryDispute), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to list the subscription plans
+func GetCmdQueryPlans(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plans",
+		Short: "Query for the subscription plans, optionally of a provider or a dVPN node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := sentinel.QueryPlansParams{
+				Page:  viper.GetInt(FlagPage),
+				Limit: viper.GetInt(FlagLimit),
+			}
+			var err error
+			if provider := viper.GetString(FlagProvider); provider != "" {
+				params.Provider, err = sdk.AccAddressFromBech32(provider)
+				if err != nil {
+					return err
+				}
+			}
+			if node := viper.GetString(FlagNode); node != "" {
+				params.Node, err = sdk.AccAddressFromBech32(node)
+				if err != nil {
+					return err
+				}
+			}
+
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryPlans), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsPlanFilters)
+	return cmd
+}
+
+// get the command to query a subscription along with its usage
+func GetCmdQuerySubscription(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscription [subscription-id]",
+		Short: "Query a subscription, whether it is active and the usage receipts of its dVPN nodes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			bz, err := cdc.MarshalJSON(sentinel.QuerySubscriptionParams{SubscriptionId: args[0]})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QuerySubscription), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to query a sentinel proposal along with its current tally
+func GetCmdQueryProposal(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposal [proposal-id]",
+		Short: "Query a proposal in its voting period and its current tally",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			bz, err := cdc.MarshalJSON(sentinel.QueryProposalParams{ProposalId: args[0]})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryProposal), bz)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// get the command to list the sentinel proposals in their voting period
+func GetCmdQueryProposals(storeName string, cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposals",
+		Short: "Query for the proposals in their voting period and their current tallies",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			ctx := context.NewCoreContextFromViper()
+			res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sentinel.QueryProposals), nil)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,528 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+	"github.com/cosmos/cosmos-sdk/x/sentinel"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// register a dVPN node
+func GetCmdRegisterVpnService(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-vpn",
+		Short: "Register the sender as a dVPN node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			endpoints, err := senttype.ParseEndpoints(viper.GetStringSlice(FlagEndpoint))
+			if err != nil {
+				return err
+			}
+
+			pricePerGb, err := sdk.ParseCoins(viper.GetString(FlagPricePerGb))
+			if err != nil {
+				return err
+			}
+
+			deposit, err := sdk.ParseCoins(viper.GetString(FlagDeposit))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgRegisterVpnService(
+				viper.GetString(FlagMoniker),
+				from,
+				endpoints,
+				viper.GetInt64(FlagUploadSpeed),
+				viper.GetInt64(FlagDownloadSpeed),
+				pricePerGb,
+				viper.GetString(FlagEncMethod),
+				viper.GetInt64(FlagLatitude),
+				viper.GetInt64(FlagLongitude),
+				viper.GetString(FlagCity),
+				viper.GetString(FlagCountry),
+				viper.GetString(FlagNodeType),
+				viper.GetString(FlagVersion),
+				deposit,
+			)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsNode)
+	return cmd
+}
+
+// update the fields of a registered dVPN node, unset flags are left unchanged
+func GetCmdUpdateVpnService(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-vpn",
+		Short: "Update the dVPN node registered by the sender",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			endpoints, err := senttype.ParseEndpoints(viper.GetStringSlice(FlagEndpoint))
+			if err != nil {
+				return err
+			}
+
+			pricePerGb, err := sdk.ParseCoins(viper.GetString(FlagPricePerGb))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgUpdateVpnService(
+				from,
+				viper.GetString(FlagMoniker),
+				endpoints,
+				viper.GetInt64(FlagUploadSpeed),
+				viper.GetInt64(FlagDownloadSpeed),
+				pricePerGb,
+				viper.GetString(FlagEncMethod),
+				viper.GetString(FlagNodeType),
+				viper.GetString(FlagVersion),
+			)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().AddFlagSet(fsNodeUpdate)
+	return cmd
+}
+
+// register a master node
+func GetCmdRegisterMasterNode(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-master",
+		Short: "Register the sender as a master node, bonding tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			bond, err := sdk.ParseCoin(viper.GetString(FlagBond))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgRegisterMasterNode(from, bond)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagBond, "", "bond locked while the sender is a master node, weighting its votes on proposals, e.g. 100sut")
+	return cmd
+}
+
+// delete a dVPN node
+func GetCmdDeleteVpnService(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-vpn",
+		Short: "Delete your dVPN node, master nodes remove other dVPN nodes through proposals",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			vaddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgDeleteVpnUser(from, vaddr)
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 address of the dVPN node")
+	return cmd
+}
+
+// delete a master node
+func GetCmdDeleteMasterNode(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-master",
+		Short: "Delete your master node, other master nodes are removed through governance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			maddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgDeleteMasterNode(from, maddr)
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 address of the master node")
+	return cmd
+}
+
+// rate a dVPN node, as a client with a settled session on it
+func GetCmdRateNode(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rate-node",
+		Short: "Rate a dVPN node, as a client with a settled session on it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			vaddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgRateNode(from, vaddr, viper.GetInt64(FlagRating))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 address of the dVPN node")
+	cmd.Flags().Int64(FlagRating, 0, "rating of the dVPN node, from 1 to 5")
+	return cmd
+}
+
+// report the liveness of a dVPN node
+func GetCmdNodeHeartbeat(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "heartbeat",
+		Short: "Report the status of a dVPN node, which is hidden from the node listings once it misses heartbeats",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgNodeHeartbeat(from, viper.GetString(FlagStatus), viper.GetInt64(FlagSessions), viper.GetInt64(FlagLoad))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagStatus, sentinel.NodeStatusOnline, "status of the dVPN node: online, busy or maintenance")
+	cmd.Flags().Int64(FlagSessions, 0, "number of sessions served by the dVPN node")
+	cmd.Flags().Int64(FlagLoad, 0, "percent of the capacity of the dVPN node in use, from 0 to 100")
+	return cmd
+}
+
+// open a dispute on a session, as its client or dVPN node
+func GetCmdOpenDispute(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open-dispute",
+		Short: "Dispute a session and freeze its funds until the master nodes settle it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgOpenDispute(from, []byte(viper.GetString(FlagSessionId)))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagSessionId, "", "id of the disputed session")
+	return cmd
+}
+
+// submit the verdict of a master node on a dispute
+func GetCmdDisputeVerdict(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dispute-verdict",
+		Short: "Submit the verdict of a master node on a disputed session",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			var verdict uint8
+			switch viper.GetString(FlagVerdict) {
+			case "client":
+				verdict = sentinel.VerdictForClient
+			case "node":
+				verdict = sentinel.VerdictForNode
+			default:
+				return fmt.Errorf("verdict must be either client or node")
+			}
+
+			msg := sentinel.NewMsgDisputeVerdict(from, []byte(viper.GetString(FlagSessionId)), verdict)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagSessionId, "", "id of the disputed session")
+	cmd.Flags().String(FlagVerdict, "", "party the verdict is in favor of, client or node")
+	return cmd
+}
+
+// publish a subscription plan, as a dVPN node or a master node
+func GetCmdAddPlan(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-plan",
+		Short: "Publish a subscription plan, served by the sender as a dVPN node or by the given dVPN nodes of a master node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			var nodes []sdk.AccAddress
+			for _, node := range viper.GetStringSlice(FlagNodes) {
+				addr, err := sdk.AccAddressFromBech32(node)
+				if err != nil {
+					return err
+				}
+				nodes = append(nodes, addr)
+			}
+
+			price, err := sdk.ParseCoins(viper.GetString(FlagPrice))
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgAddPlan(from, nodes, price, viper.GetInt64(FlagDuration), viper.GetInt64(FlagDataCap))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().StringSlice(FlagNodes, nil, "bech32 address of a dVPN node serving the plan, for plans of master nodes (repeatable)")
+	cmd.Flags().String(FlagPrice, "", "price of a subscription period, e.g. 100sut")
+	cmd.Flags().Int64(FlagDuration, 0, "seconds of a subscription period")
+	cmd.Flags().Int64(FlagDataCap, 0, "bytes of a subscription period, unlimited if zero")
+	return cmd
+}
+
+// subscribe to a plan, signing the usage receipts with the key of the sender
+func GetCmdSubscribe(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Buy a period of a subscription plan",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			kb, err := keys.GetKeyBase()
+			if err != nil {
+				return err
+			}
+			info, err := kb.Get(ctx.FromAddressName)
+			if err != nil {
+				return err
+			}
+
+			msg := sentinel.NewMsgSubscribe(from, []byte(viper.GetString(FlagPlanId)), info.GetPubKey())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagPlanId, "", "id of the plan")
+	return cmd
+}
+
+// submit a sentinel proposal of a master node
+func GetCmdSubmitProposal(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit-proposal",
+		Short: "Submit a proposal, voted on by the master nodes, to remove a dVPN node, slash its deposit or change the sentinel params",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			var msg sentinel.MsgSubmitProposal
+			switch viper.GetString(FlagKind) {
+			case "remove-node":
+				node, err := sdk.AccAddressFromBech32(viper.GetString(FlagNode))
+				if err != nil {
+					return err
+				}
+				msg = sentinel.NewMsgSubmitProposal(from, sentinel.ProposalKindRemoveNode, node, nil)
+			case "slash-deposit":
+				node, err := sdk.AccAddressFromBech32(viper.GetString(FlagNode))
+				if err != nil {
+					return err
+				}
+				fraction, err := sdk.NewRatFromDecimal(viper.GetString(FlagFraction), 18)
+				if err != nil {
+					return err
+				}
+				msg = sentinel.NewMsgSubmitSlashProposal(from, node, fraction)
+			case "change-params":
+				bz, err := ioutil.ReadFile(viper.GetString(FlagParams))
+				if err != nil {
+					return err
+				}
+				var params sentinel.Params
+				err = cdc.UnmarshalJSON(bz, &params)
+				if err != nil {
+					return err
+				}
+				msg = sentinel.NewMsgSubmitProposal(from, sentinel.ProposalKindChangeParams, nil, &params)
+			default:
+				return fmt.Errorf("kind must be one of remove-node, slash-deposit or change-params")
+			}
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagKind, "", "kind of the proposal, remove-node, slash-deposit or change-params")
+	cmd.Flags().String(FlagNode, "", "bech32 address of the dVPN node to remove or slash")
+	cmd.Flags().String(FlagFraction, "", "fraction of the bonded and unbonding deposit to slash, e.g. 0.5")
+	cmd.Flags().String(FlagParams, "", "JSON file of all the sentinel params to set")
+	return cmd
+}
+
+// vote on a sentinel proposal as a master node
+func GetCmdVote(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vote",
+		Short: "Vote on a proposal as a master node, with the weight of its bond",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.NewCoreContextFromViper().WithDecoder(authcmd.GetAccountDecoder(cdc))
+
+			from, err := ctx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+
+			var option uint8
+			switch viper.GetString(FlagOption) {
+			case "yes":
+				option = sentinel.VoteOptionYes
+			case "no":
+				option = sentinel.VoteOptionNo
+			default:
+				return fmt.Errorf("option must be either yes or no")
+			}
+
+			msg := sentinel.NewMsgVote(from, []byte(viper.GetString(FlagProposalId)), option)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			// build and sign the transaction, then broadcast to Tendermint
+			return ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
+		},
+	}
+
+	cmd.Flags().String(FlagProposalId, "", "id of the proposal")
+	cmd.Flags().String(FlagOption, "", "vote option, yes or no")
+	return cmd
+}
@@ -0,0 +1,256 @@
+package sentinel
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// UnbondingDeposit - the deposit of a deleted dVPN node, along with the bond
+// of a removed master node of the same address, returned once the unbonding
+// time has passed and slashable until then
+type UnbondingDeposit struct {
+	Address        sdk.AccAddress `json:"address"`         // address of the deleted dVPN node or removed master node
+	Coins          sdk.Coins      `json:"coins"`           // coins which are unbonding
+	CompletionTime int64          `json:"completion_time"` // unix time at which the coins are returned
+}
+
+// DepositSlash - a slash of a fraction of the deposit of a dVPN node
+type DepositSlash struct {
+	Node     sdk.AccAddress `json:"node"`     // address of the dVPN node
+	Fraction sdk.Rat        `json:"fraction"` // fraction of the bonded and unbonding deposit to burn
+}
+
+// NewDepositSlash creates a deposit slash
+func NewDepositSlash(node sdk.AccAddress, fraction sdk.Rat) DepositSlash {
+	return DepositSlash{
+		Node:     node,
+		Fraction: fraction,
+	}
+}
+
+// ValidateBasic performs the stateless checks of the slash
+func (slash DepositSlash) ValidateBasic() sdk.Error {
+	if len(slash.Node) == 0 {
+		return sdk.ErrInvalidAddress("VPN Address type is Invalid")
+	}
+	if slash.Fraction.Rat == nil || !slash.Fraction.GT(sdk.ZeroRat()) || slash.Fraction.GT(sdk.OneRat()) {
+		return ErrInvalidSlashFraction("Slash fraction must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+// IsEmpty checks if no field of the slash is set
+func (slash DepositSlash) IsEmpty() bool {
+	return len(slash.Node) == 0 && (slash.Fraction.Rat == nil || slash.Fraction.IsZero())
+}
+
+func (slash DepositSlash) String() string {
+	if slash.Fraction.Rat == nil {
+		return fmt.Sprintf("Deposit Slash\n  Node: %s\n  Fraction: 0", slash.Node)
+	}
+	return fmt.Sprintf("Deposit Slash\n  Node: %s\n  Fraction: %s", slash.Node, slash.Fraction.FloatString())
+}
+
+// get the bonded deposit of a dVPN node
+func (keeper Keeper) GetNodeDeposit(ctx sdk.Context, addr sdk.AccAddress) (deposit sdk.Coins) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetNodeDepositKey(addr))
+	if bz == nil {
+		return nil
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &deposit)
+	return deposit
+}
+
+// set the bonded deposit of a dVPN node, removed once it is zero
+func (keeper Keeper) SetNodeDeposit(ctx sdk.Context, addr sdk.AccAddress, deposit sdk.Coins) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	if deposit.IsZero() {
+		store.Delete(GetNodeDepositKey(addr))
+		return
+	}
+	bz := keeper.cdc.MustMarshalBinary(deposit)
+	store.Set(GetNodeDepositKey(addr), bz)
+}
+
+// iterate through the bonded deposits, execute func for each
+func (keeper Keeper) IterateNodeDeposits(ctx sdk.Context, fn func(addr sdk.AccAddress, deposit sdk.Coins) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, NodeDepositKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var deposit sdk.Coins
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &deposit)
+		if fn(GetAddressFromKey(iterator.Key()), deposit) {
+			break
+		}
+	}
+}
+
+// get the unbonding deposit of a deleted dVPN node
+func (keeper Keeper) GetUnbondingDeposit(ctx sdk.Context, addr sdk.AccAddress) (ubd UnbondingDeposit, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetUnbondingDepositKey(addr))
+	if bz == nil {
+		return ubd, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &ubd)
+	return ubd, true
+}
+
+// set an unbonding deposit along with its entry in the deposit queue
+func (keeper Keeper) SetUnbondingDeposit(ctx sdk.Context, ubd UnbondingDeposit) {
+	keeper.RemoveUnbondingDeposit(ctx, ubd.Address)
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(ubd)
+	store.Set(GetUnbondingDepositKey(ubd.Address), bz)
+	store.Set(GetDepositQueueKey(ubd.CompletionTime, ubd.Address), []byte{})
+}
+
+// remove an unbonding deposit along with its queue entry
+func (keeper Keeper) RemoveUnbondingDeposit(ctx sdk.Context, addr sdk.AccAddress) {
+	ubd, found := keeper.GetUnbondingDeposit(ctx, addr)
+	if !found {
+		return
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetUnbondingDepositKey(addr))
+	store.Delete(GetDepositQueueKey(ubd.CompletionTime, addr))
+}
+
+// iterate through the unbonding deposits, execute func for each
+func (keeper Keeper) IterateUnbondingDeposits(ctx sdk.Context, fn func(ubd UnbondingDeposit) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, UnbondingDepositKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var ubd UnbondingDeposit
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &ubd)
+		if fn(ubd) {
+			break
+		}
+	}
+}
+
+// lock the deposit of a registering dVPN node in escrow
+func (keeper Keeper) bondDeposit(ctx sdk.Context, addr sdk.AccAddress, deposit sdk.Coins) sdk.Error {
+	minDeposit := keeper.GetParams(ctx).MinDeposit
+	if !deposit.IsGTE(minDeposit) {
+		return ErrInvalidDeposit(fmt.Sprintf("Deposit must be at least %s", minDeposit))
+	}
+	if deposit.IsZero() {
+		return nil
+	}
+	_, _, err := keeper.coinKeeper.SubtractCoins(ctx, addr, deposit)
+	if err != nil {
+		return err
+	}
+	keeper.SetNodeDeposit(ctx, addr, keeper.GetNodeDeposit(ctx, addr).Plus(deposit))
+	return nil
+}
+
+// start unbonding the deposit of a deleted dVPN node
+func (keeper Keeper) unbondDeposit(ctx sdk.Context, addr sdk.AccAddress) {
+	deposit := keeper.GetNodeDeposit(ctx, addr)
+	if deposit.IsZero() {
+		return
+	}
+	keeper.SetNodeDeposit(ctx, addr, nil)
+	keeper.startUnbonding(ctx, addr, deposit)
+}
+
+// start unbonding coins of an address. Coins which are already unbonding are
+// merged with them and returned at the later time.
+func (keeper Keeper) startUnbonding(ctx sdk.Context, addr sdk.AccAddress, coins sdk.Coins) {
+	ubd := UnbondingDeposit{
+		Address:        addr,
+		Coins:          coins,
+		CompletionTime: ctx.BlockHeader().Time + keeper.GetParams(ctx).UnbondingTime,
+	}
+	if prev, found := keeper.GetUnbondingDeposit(ctx, addr); found {
+		ubd.Coins = ubd.Coins.Plus(prev.Coins)
+	}
+	keeper.SetUnbondingDeposit(ctx, ubd)
+}
+
+// get the addresses of the unbonding deposits of the queue with a completion
+// time up to now
+func (keeper Keeper) getMatureUnbondingDeposits(ctx sdk.Context, now int64) (addrs []sdk.AccAddress) {
+	if now < 0 {
+		return nil
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := store.Iterator(DepositQueueKey, GetDepositQueueTimeKey(now+1))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		addrs = append(addrs, GetAddressFromDepositQueueKey(iterator.Key()))
+	}
+	return addrs
+}
+
+// CompleteUnbondingDeposit returns the unbonding deposit of a deleted dVPN node
+// or removed master node
+func (keeper Keeper) CompleteUnbondingDeposit(ctx sdk.Context, addr sdk.AccAddress) (sdk.Coins, sdk.Error) {
+	ubd, found := keeper.GetUnbondingDeposit(ctx, addr)
+	if !found {
+		return nil, ErrInvalidDeposit("No unbonding deposit found")
+	}
+	if ubd.Coins.IsPositive() {
+		_, _, err := keeper.coinKeeper.AddCoins(ctx, addr, ubd.Coins)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keeper.RemoveUnbondingDeposit(ctx, addr)
+	return ubd.Coins, nil
+}
+
+// SlashDeposit burns a fraction of the bonded and unbonding deposit of a dVPN
+// node, returning the burned coins. Deposits are only slashed by dispute
+// verdicts and passed sentinel or governance proposals.
+func (keeper Keeper) SlashDeposit(ctx sdk.Context, slash DepositSlash) (slashed sdk.Coins, err sdk.Error) {
+	err = slash.ValidateBasic()
+	if err != nil {
+		return nil, err
+	}
+
+	if !keeper.hasDeposit(ctx, slash.Node) {
+		return nil, ErrInvalidDeposit("No deposit found for the VPN node")
+	}
+	deposit := keeper.GetNodeDeposit(ctx, slash.Node)
+	ubd, unbonding := keeper.GetUnbondingDeposit(ctx, slash.Node)
+
+	depositSlash := slashCoins(deposit, slash.Fraction)
+	keeper.SetNodeDeposit(ctx, slash.Node, deposit.Minus(depositSlash))
+	slashed = depositSlash
+	if unbonding {
+		ubdSlash := slashCoins(ubd.Coins, slash.Fraction)
+		ubd.Coins = ubd.Coins.Minus(ubdSlash)
+		keeper.SetUnbondingDeposit(ctx, ubd)
+		slashed = slashed.Plus(ubdSlash)
+	}
+	return slashed, nil
+}
+
+// check if a dVPN node has a bonded or unbonding deposit to slash
+func (keeper Keeper) hasDeposit(ctx sdk.Context, addr sdk.AccAddress) bool {
+	if !keeper.GetNodeDeposit(ctx, addr).IsZero() {
+		return true
+	}
+	_, unbonding := keeper.GetUnbondingDeposit(ctx, addr)
+	return unbonding
+}
+
+// the fraction of each coin, truncated
+func slashCoins(coins sdk.Coins, fraction sdk.Rat) (slashed sdk.Coins) {
+	for _, coin := range coins {
+		amount := new(big.Int).Mul(coin.Amount.BigInt(), fraction.Num().BigInt())
+		amount.Quo(amount, fraction.Denom().BigInt())
+		if amount.Sign() > 0 {
+			slashed = append(slashed, sdk.Coin{Denom: coin.Denom, Amount: sdk.NewIntFromBigInt(amount)})
+		}
+	}
+	return slashed
+}
@@ -0,0 +1,108 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func newTestRegisterMsg(addr sdk.AccAddress, deposit int64) MsgRegisterVpnService {
+	return NewMsgRegisterVpnService("node", addr, newTestEndpoints("8.8.8.8"), 1000, 1000, sdk.Coins{{"sut", sdk.NewInt(10)}}, "AES-256-CBC",
+		174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1", sdk.Coins{{"sut", sdk.NewInt(deposit)}})
+}
+
+func TestDepositUnbonding(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	unbondingTime := keeper.GetParams(ctx).UnbondingTime
+
+	// the deposit must be at least the min deposit
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 99))
+	require.Equal(t, CodeInvalidDeposit, err.Code())
+	_, err = keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], initCoins.Int64()+1))
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+	_, found := keeper.GetVpnService(ctx, addrs[0])
+	require.False(t, found)
+
+	_, err = keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 150))
+	require.Nil(t, err)
+	require.Equal(t, int64(150), keeper.GetNodeDeposit(ctx, addrs[0]).AmountOf("sut").Int64())
+	require.Equal(t, initCoins.Int64()-150, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	// the deposit starts unbonding once the node is deleted
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	require.True(t, keeper.GetNodeDeposit(ctx, addrs[0]).IsZero())
+	ubd, found := keeper.GetUnbondingDeposit(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, 1000+unbondingTime, ubd.CompletionTime)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + unbondingTime - 1})
+	require.Empty(t, EndBlocker(ctx, keeper))
+	require.Equal(t, initCoins.Int64()-150, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + unbondingTime})
+	require.NotEmpty(t, EndBlocker(ctx, keeper))
+	_, found = keeper.GetUnbondingDeposit(ctx, addrs[0])
+	require.False(t, found)
+	require.Empty(t, keeper.getMatureUnbondingDeposits(ctx, ctx.BlockHeader().Time))
+	require.Equal(t, initCoins.Int64(), ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+}
+
+func TestSlashDeposit(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+
+	slashed, err := keeper.SlashDeposit(ctx, NewDepositSlash(addrs[0], sdk.NewRat(1, 3)))
+	require.Nil(t, err)
+	require.Equal(t, int64(33), slashed.AmountOf("sut").Int64())
+	require.Equal(t, int64(67), keeper.GetNodeDeposit(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	// unbonding deposits remain slashable
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	slashed, err = keeper.SlashDeposit(ctx, NewDepositSlash(addrs[0], sdk.OneRat()))
+	require.Nil(t, err)
+	require.Equal(t, int64(67), slashed.AmountOf("sut").Int64())
+	ubd, found := keeper.GetUnbondingDeposit(ctx, addrs[0])
+	require.True(t, found)
+	require.True(t, ubd.Coins.IsZero())
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: ubd.CompletionTime})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, initCoins.Int64()-100, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	// nodes without a deposit cannot be slashed
+	_, err = keeper.SlashDeposit(ctx, NewDepositSlash(addrs[1], sdk.OneRat()))
+	require.Equal(t, CodeInvalidDeposit, err.Code())
+}
+
+func TestDepositSlashValidateBasic(t *testing.T) {
+	cases := []struct {
+		slash      DepositSlash
+		expectPass bool
+	}{
+		{NewDepositSlash(addrs[0], sdk.NewRat(1, 2)), true},
+		{NewDepositSlash(addrs[0], sdk.OneRat()), true},
+		{NewDepositSlash(addrs[0], sdk.ZeroRat()), false},
+		{NewDepositSlash(addrs[0], sdk.NewRat(3, 2)), false},
+		{NewDepositSlash(addrs[0], sdk.NewRat(-1, 2)), false},
+		{NewDepositSlash(nil, sdk.NewRat(1, 2)), false},
+		{DepositSlash{}, false},
+	}
+
+	for i, tc := range cases {
+		err := tc.slash.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+	require.True(t, DepositSlash{}.IsEmpty())
+	require.False(t, NewDepositSlash(addrs[0], sdk.OneRat()).IsEmpty())
+}
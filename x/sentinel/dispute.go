@@ -0,0 +1,249 @@
+package sentinel
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// Verdicts of a master node on a dispute
+const (
+	VerdictNone      uint8 = 0 // no majority, the session resumes
+	VerdictForClient uint8 = 1 // the client is refunded the unreleased coins and the deposit of the dVPN node is slashed
+	VerdictForNode   uint8 = 2 // the dVPN node is paid the unreleased coins
+)
+
+// name of a verdict, as tagged
+func verdictString(verdict uint8) string {
+	switch verdict {
+	case VerdictForClient:
+		return "client"
+	case VerdictForNode:
+		return "node"
+	}
+	return "none"
+}
+
+// DisputeVerdict - the verdict of a master node on a dispute
+type DisputeVerdict struct {
+	MasterNode sdk.AccAddress `json:"master_node"`
+	Verdict    uint8          `json:"verdict"`
+}
+
+// Dispute - a dispute on the payment of a session, whose funds are frozen
+// until the deadline, when it is settled by the bond-weighted majority verdict
+// of the master nodes
+type Dispute struct {
+	SessionId     []byte           `json:"session_id"`
+	Opener        sdk.AccAddress   `json:"opener"`         // client or dVPN node of the session
+	SessionStatus uint8            `json:"session_status"` // status of the session before the dispute, restored without a majority
+	Deadline      int64            `json:"deadline"`       // unix time until which master nodes may submit verdicts
+	Verdicts      []DisputeVerdict `json:"verdicts"`
+}
+
+// check if a master node has submitted a verdict on the dispute
+func (dispute Dispute) HasVerdict(masterNode sdk.AccAddress) bool {
+	for _, verdict := range dispute.Verdicts {
+		if bytes.Equal(verdict.MasterNode, masterNode) {
+			return true
+		}
+	}
+	return false
+}
+
+// the verdict of the majority of the master nodes, each verdict counting with
+// the weight of its master node, VerdictNone on a tie
+func (dispute Dispute) MajorityVerdict(weight func(addr sdk.AccAddress) sdk.Int) uint8 {
+	forClient, forNode := sdk.ZeroInt(), sdk.ZeroInt()
+	for _, verdict := range dispute.Verdicts {
+		switch verdict.Verdict {
+		case VerdictForClient:
+			forClient = forClient.Add(weight(verdict.MasterNode))
+		case VerdictForNode:
+			forNode = forNode.Add(weight(verdict.MasterNode))
+		}
+	}
+	switch {
+	case forClient.GT(forNode):
+		return VerdictForClient
+	case forNode.GT(forClient):
+		return VerdictForNode
+	}
+	return VerdictNone
+}
+
+// get the dispute of a session
+func (keeper Keeper) GetDispute(ctx sdk.Context, sessionId []byte) (dispute Dispute, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetDisputeKey(sessionId))
+	if bz == nil {
+		return dispute, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &dispute)
+	return dispute, true
+}
+
+// set a dispute along with its entry in the dispute queue
+func (keeper Keeper) SetDispute(ctx sdk.Context, dispute Dispute) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(dispute)
+	store.Set(GetDisputeKey(dispute.SessionId), bz)
+	store.Set(GetDisputeQueueKey(dispute.Deadline, dispute.SessionId), []byte{})
+}
+
+// remove a dispute along with its queue entry
+func (keeper Keeper) RemoveDispute(ctx sdk.Context, sessionId []byte) {
+	dispute, found := keeper.GetDispute(ctx, sessionId)
+	if !found {
+		return
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetDisputeKey(sessionId))
+	store.Delete(GetDisputeQueueKey(dispute.Deadline, sessionId))
+}
+
+// iterate through the disputes, execute func for each
+func (keeper Keeper) IterateDisputes(ctx sdk.Context, fn func(dispute Dispute) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, DisputeKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var dispute Dispute
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &dispute)
+		if fn(dispute) {
+			break
+		}
+	}
+}
+
+// get the session ids of the disputes of the queue with a deadline up to now
+func (keeper Keeper) getExpiredDisputeIds(ctx sdk.Context, now int64) (ids [][]byte) {
+	if now < 0 {
+		return nil
+	}
+	return keeper.getQueuedSessionIds(ctx, DisputeQueueKey, GetDisputeQueueTimeKey(now+1))
+}
+
+// OpenDispute freezes the funds of a session on the request of its client or
+// dVPN node, until the master nodes settle it at the end of the dispute period.
+// Active sessions may be disputed, as well as closing sessions until the end
+// of their challenge period.
+func (keeper Keeper) OpenDispute(ctx sdk.Context, msg MsgOpenDispute) (Dispute, sdk.Error) {
+	session, found := keeper.GetSession(ctx, msg.Sessionid)
+	if !found {
+		return Dispute{}, ErrInvalidSessionid("Invalid session Id")
+	}
+	if !bytes.Equal(msg.From, session.CAddress) && !bytes.Equal(msg.From, session.VpnAddress()) {
+		return Dispute{}, ErrInvalidDispute("Only the client or the VPN node of a session may dispute it")
+	}
+
+	now := ctx.BlockHeader().Time
+	params := keeper.GetParams(ctx)
+	switch {
+	case session.Status == senttype.StatusDisputed:
+		return Dispute{}, ErrInvalidDispute("Session is already disputed")
+	case session.Status == senttype.StatusClosing && now-session.ClosingTime >= params.ChallengePeriod:
+		return Dispute{}, ErrTimeInterval("Challenge period of the session is over")
+	}
+
+	dispute := Dispute{
+		SessionId:     msg.Sessionid,
+		Opener:        msg.From,
+		SessionStatus: session.Status,
+		Deadline:      now + params.DisputePeriod,
+	}
+	session.Status = senttype.StatusDisputed
+	keeper.SetSession(ctx, msg.Sessionid, session)
+	keeper.SetDispute(ctx, dispute)
+	return dispute, nil
+}
+
+// SubmitVerdict records the verdict of a master node on a dispute, until its
+// deadline. The parties of the session may not judge it.
+func (keeper Keeper) SubmitVerdict(ctx sdk.Context, msg MsgDisputeVerdict) sdk.Error {
+	dispute, found := keeper.GetDispute(ctx, msg.Sessionid)
+	if !found {
+		return ErrInvalidDispute("No dispute found for the session")
+	}
+	if !keeper.IsMasterNode(ctx, msg.From) {
+		return ErrUnauthorizedVerdict("Only master nodes may submit verdicts")
+	}
+	session, found := keeper.GetSession(ctx, msg.Sessionid)
+	if !found {
+		panic("dispute points to a missing session")
+	}
+	if bytes.Equal(msg.From, session.CAddress) || bytes.Equal(msg.From, session.VpnAddress()) {
+		return ErrUnauthorizedVerdict("Parties of a dispute may not judge it")
+	}
+	if ctx.BlockHeader().Time >= dispute.Deadline {
+		return ErrTimeInterval("Deadline of the dispute is over")
+	}
+	if dispute.HasVerdict(msg.From) {
+		return ErrDuplicateVerdict("Master node has already submitted a verdict")
+	}
+
+	dispute.Verdicts = append(dispute.Verdicts, DisputeVerdict{MasterNode: msg.From, Verdict: msg.Verdict})
+	keeper.SetDispute(ctx, dispute)
+	return nil
+}
+
+// ResolveDispute settles a disputed session by the majority verdict of the
+// current master nodes, weighted by their bonds. The winning dVPN node is paid
+// the unreleased coins, the winning client is refunded them and the deposit of
+// the node is slashed by the dispute slash. Without a majority the session
+// resumes, a closing session with a new challenge period, so that a dispute
+// does not run out the time of the node to claim its latest client signature.
+func (keeper Keeper) ResolveDispute(ctx sdk.Context, sessionId []byte) (verdict uint8, slashed sdk.Coins, err sdk.Error) {
+	dispute, found := keeper.GetDispute(ctx, sessionId)
+	if !found {
+		return VerdictNone, nil, ErrInvalidDispute("No dispute found for the session")
+	}
+	session, found := keeper.GetSession(ctx, sessionId)
+	if !found {
+		panic("dispute points to a missing session")
+	}
+	keeper.RemoveDispute(ctx, sessionId)
+
+	verdict = dispute.MajorityVerdict(func(addr sdk.AccAddress) sdk.Int {
+		return keeper.masterNodeWeight(ctx, addr)
+	})
+	switch verdict {
+	case VerdictForClient:
+		session.CloseReason = senttype.CloseReasonDisputeForClient
+		_, err = keeper.SettleSession(ctx, sessionId, session)
+		if err != nil {
+			return verdict, nil, err
+		}
+		fraction := keeper.GetParams(ctx).DisputeSlash
+		if fraction.Rat == nil || !fraction.GT(sdk.ZeroRat()) {
+			return verdict, nil, nil
+		}
+		slashed, err = keeper.SlashDeposit(ctx, NewDepositSlash(session.VpnAddress(), fraction))
+		if err != nil && err.Code() == CodeInvalidDeposit {
+			// the dVPN node has no deposit left to slash
+			return verdict, nil, nil
+		}
+		return verdict, slashed, err
+	case VerdictForNode:
+		remaining := session.RemainingCoins()
+		if remaining.IsPositive() {
+			_, _, err = keeper.coinKeeper.AddCoins(ctx, session.VpnAddress(), remaining)
+			if err != nil {
+				return verdict, nil, err
+			}
+		}
+		session.ReleasedCoins = session.TotalLockedCoins
+		session.CloseReason = senttype.CloseReasonDisputeForNode
+		_, err = keeper.SettleSession(ctx, sessionId, session)
+		return verdict, nil, err
+	}
+	session.Status = dispute.SessionStatus
+	if session.Status == senttype.StatusClosing {
+		store := ctx.KVStore(keeper.sentStoreKey)
+		store.Delete(GetSessionClosingQueueKey(session.ClosingTime, sessionId))
+		session.ClosingTime = ctx.BlockHeader().Time
+	}
+	keeper.SetSession(ctx, sessionId, session)
+	return VerdictNone, nil, nil
+}
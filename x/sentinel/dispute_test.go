@@ -0,0 +1,247 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// register addrs[0] as a dVPN node with a deposit of 100sut and open a
+// session of 10sut for the client addrs[1], disputed by the client
+func newTestDispute(t *testing.T) (sdk.Context, bank.Keeper, Keeper, []byte, Dispute) {
+	ctx, ck, keeper := createTestInput(t)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(sdk.Coins{{"sut", sdk.NewInt(10)}}, addrs[0], addrs[1], ed25519.GenPrivKey().PubKey()))
+	require.Nil(t, err)
+	dispute, err := keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[1], []byte(id)))
+	require.Nil(t, err)
+	return ctx, ck, keeper, []byte(id), dispute
+}
+
+func TestOpenDispute(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	params := keeper.GetParams(ctx)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	clientKey := ed25519.GenPrivKey()
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sessionId := []byte(id)
+
+	// only the parties of the session may dispute it
+	_, err = keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[2], sessionId))
+	require.Equal(t, CodeInvalidDispute, err.Code())
+	_, err = keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[2], []byte("missing")))
+	require.Equal(t, CodeInvalidSessionid, err.Code())
+
+	dispute, err := keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[0], sessionId))
+	require.Nil(t, err)
+	require.Equal(t, 1000+params.DisputePeriod, dispute.Deadline)
+	require.Equal(t, senttype.StatusActive, dispute.SessionStatus)
+	session, _ := keeper.GetSession(ctx, sessionId)
+	require.Equal(t, senttype.StatusDisputed, session.Status)
+	_, err = keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[1], sessionId))
+	require.Equal(t, CodeInvalidDispute, err.Code())
+
+	// the funds of a disputed session are frozen
+	sig, signErr := clientKey.Sign(senttype.ClientStdSignBytes(coins, sessionId, 1, false))
+	require.Nil(t, signErr)
+	_, _, err = keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins, sessionId, 1, addrs[0], sig, false))
+	require.NotNil(t, err)
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.NotNil(t, err)
+
+	// nor is a disputed session settled at its timeout
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.SessionTimeout})
+	EndBlocker(ctx, keeper)
+	_, found := keeper.GetSession(ctx, sessionId)
+	require.True(t, found)
+}
+
+func TestSubmitVerdict(t *testing.T) {
+	ctx, _, keeper, sessionId, dispute := newTestDispute(t)
+	masterNode := sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())
+
+	// only master nodes which are not parties of the session may judge it
+	err := keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(addrs[2], sessionId, VerdictForClient))
+	require.Equal(t, CodeUnauthorizedVerdict, err.Code())
+	keeper.SetMasterNode(ctx, addrs[0])
+	err = keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(addrs[0], sessionId, VerdictForNode))
+	require.Equal(t, CodeUnauthorizedVerdict, err.Code())
+
+	keeper.SetMasterNode(ctx, masterNode)
+	require.Nil(t, keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(masterNode, sessionId, VerdictForClient)))
+	err = keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(masterNode, sessionId, VerdictForNode))
+	require.Equal(t, CodeDuplicateVerdict, err.Code())
+
+	// verdicts are accepted until the deadline
+	keeper.SetMasterNode(ctx, addrs[2])
+	ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline})
+	err = keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(addrs[2], sessionId, VerdictForNode))
+	require.Equal(t, CodeTimeInterval, err.Code())
+	err = keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(addrs[2], []byte("missing"), VerdictForNode))
+	require.Equal(t, CodeInvalidDispute, err.Code())
+
+	dispute, found := keeper.GetDispute(ctx, sessionId)
+	require.True(t, found)
+	require.Equal(t, []DisputeVerdict{{masterNode, VerdictForClient}}, dispute.Verdicts)
+}
+
+func TestResolveDispute(t *testing.T) {
+	masterNodes := []sdk.AccAddress{addrs[2], sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())}
+	tests := []struct {
+		verdicts    []uint8
+		expected    uint8
+		nodeCoins   int64
+		clientCoins int64
+		deposit     int64
+	}{
+		{[]uint8{VerdictForClient}, VerdictForClient, 100, 200, 90},
+		{[]uint8{VerdictForNode, VerdictForNode}, VerdictForNode, 110, 190, 100},
+		{[]uint8{VerdictForClient, VerdictForNode}, VerdictNone, 100, 190, 100},
+		{nil, VerdictNone, 100, 190, 100},
+	}
+
+	for i, tc := range tests {
+		ctx, ck, keeper, sessionId, dispute := newTestDispute(t)
+		for j, verdict := range tc.verdicts {
+			keeper.SetMasterNode(ctx, masterNodes[j])
+			keeper.SetMasterBond(ctx, masterNodes[j], sdk.NewCoin("sut", 100))
+			require.Nil(t, keeper.SubmitVerdict(ctx, NewMsgDisputeVerdict(masterNodes[j], sessionId, verdict)), "test: %v", i)
+		}
+
+		// the dispute is resolved at its deadline
+		ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline - 1})
+		EndBlocker(ctx, keeper)
+		_, found := keeper.GetDispute(ctx, sessionId)
+		require.True(t, found, "test: %v", i)
+		ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline})
+		EndBlocker(ctx, keeper)
+		_, found = keeper.GetDispute(ctx, sessionId)
+		require.False(t, found, "test: %v", i)
+
+		session, found := keeper.GetSession(ctx, sessionId)
+		if tc.expected == VerdictNone {
+			require.True(t, found, "test: %v", i)
+			require.Equal(t, senttype.StatusActive, session.Status, "test: %v", i)
+		} else {
+			require.False(t, found, "test: %v", i)
+			// only a verdict against the node counts against its reputation
+			disputed := int64(0)
+			if tc.expected == VerdictForClient {
+				disputed = 1
+			}
+			require.Equal(t, disputed, keeper.GetReputation(ctx, addrs[0]).DisputedSessions, "test: %v", i)
+		}
+		require.Equal(t, tc.nodeCoins, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64(), "test: %v", i)
+		require.Equal(t, tc.clientCoins, ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64(), "test: %v", i)
+		require.Equal(t, tc.deposit, keeper.GetNodeDeposit(ctx, addrs[0]).AmountOf("sut").Int64(), "test: %v", i)
+	}
+}
+
+func TestResolveDisputeOfClosingSession(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	params := keeper.GetParams(ctx)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	clientKey := ed25519.GenPrivKey()
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sessionId := []byte(id)
+
+	// the client refunds the session and disputes it right away
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout})
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.Nil(t, err)
+	dispute, err := keeper.OpenDispute(ctx, NewMsgOpenDispute(addrs[1], sessionId))
+	require.Nil(t, err)
+
+	// without a majority the challenge period restarts at the deadline
+	ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline})
+	EndBlocker(ctx, keeper)
+	session, found := keeper.GetSession(ctx, sessionId)
+	require.True(t, found)
+	require.Equal(t, senttype.StatusClosing, session.Status)
+	require.Equal(t, dispute.Deadline, session.ClosingTime)
+	require.Empty(t, keeper.getChallengedSessionIds(ctx, 1000+params.RefundTimeout))
+
+	// so that the dVPN node may still claim its latest client signature
+	ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline + params.ChallengePeriod - 1})
+	EndBlocker(ctx, keeper)
+	sig, signErr := clientKey.Sign(senttype.ClientStdSignBytes(coins, sessionId, 1, false))
+	require.Nil(t, signErr)
+	nodeCoins := ck.GetCoins(ctx, addrs[0])
+	paid, _, err := keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins, sessionId, 1, addrs[0], sig, false))
+	require.Nil(t, err)
+	require.Equal(t, coins, paid)
+	require.Equal(t, nodeCoins.Plus(coins), ck.GetCoins(ctx, addrs[0]))
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: dispute.Deadline + params.ChallengePeriod})
+	EndBlocker(ctx, keeper)
+	_, found = keeper.GetSession(ctx, sessionId)
+	require.False(t, found)
+}
+
+func TestMajorityVerdict(t *testing.T) {
+	bonds := map[string]int64{addrs[0].String(): 3, addrs[1].String(): 1, addrs[2].String(): 1}
+	weight := func(addr sdk.AccAddress) sdk.Int {
+		return sdk.NewInt(bonds[addr.String()])
+	}
+	removed := sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())
+	verdict := func(addr sdk.AccAddress, v uint8) DisputeVerdict {
+		return DisputeVerdict{addr, v}
+	}
+
+	tests := []struct {
+		verdicts []DisputeVerdict
+		expected uint8
+	}{
+		{nil, VerdictNone},
+		{[]DisputeVerdict{verdict(addrs[0], VerdictForClient)}, VerdictForClient},
+		{[]DisputeVerdict{verdict(addrs[1], VerdictForClient), verdict(addrs[2], VerdictForNode)}, VerdictNone},
+		{[]DisputeVerdict{verdict(addrs[0], VerdictForNode), verdict(addrs[1], VerdictForNode), verdict(addrs[2], VerdictForClient)}, VerdictForNode},
+		{[]DisputeVerdict{verdict(addrs[0], VerdictForClient), verdict(addrs[1], VerdictForNode), verdict(addrs[2], VerdictForNode)}, VerdictForClient},
+		{[]DisputeVerdict{verdict(addrs[1], VerdictForNode), verdict(removed, VerdictForClient), verdict(removed, VerdictForClient)}, VerdictForNode},
+		{[]DisputeVerdict{verdict(removed, VerdictForClient)}, VerdictNone},
+	}
+
+	for i, tc := range tests {
+		require.Equal(t, tc.expected, Dispute{Verdicts: tc.verdicts}.MajorityVerdict(weight), "test: %v", i)
+	}
+}
+
+func TestMsgDisputeValidateBasic(t *testing.T) {
+	cases := []struct {
+		msg        sdk.Msg
+		expectPass bool
+	}{
+		{NewMsgOpenDispute(addrs[1], []byte("session")), true},
+		{NewMsgOpenDispute(nil, []byte("session")), false},
+		{NewMsgOpenDispute(addrs[1], nil), false},
+		{NewMsgDisputeVerdict(addrs[2], []byte("session"), VerdictForClient), true},
+		{NewMsgDisputeVerdict(addrs[2], []byte("session"), VerdictForNode), true},
+		{NewMsgDisputeVerdict(addrs[2], []byte("session"), VerdictNone), false},
+		{NewMsgDisputeVerdict(addrs[2], []byte("session"), 3), false},
+		{NewMsgDisputeVerdict(nil, []byte("session"), VerdictForNode), false},
+		{NewMsgDisputeVerdict(addrs[2], nil, VerdictForNode), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
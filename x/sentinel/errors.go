@@ -7,22 +7,39 @@ import (
 var (
 	DefaultCodeSpace sdk.CodespaceType = 19
 
-	CodeInvalidPubKey          sdk.CodeType = 1
-	CodeTimeInterval           sdk.CodeType = 2
-	CodeInvalidIpAdress        sdk.CodeType = 3
-	CodeUnknownIpAddress       sdk.CodeType = 4
-	CodeUnknownSessionid       sdk.CodeType = 5
-	CodeInvalidSessionid       sdk.CodeType = 6
-	CodeInvalidNetspeed        sdk.CodeType = 7
-	CodeInvalidPricePerGb      sdk.CodeType = 8
-	CodeMarshal                sdk.CodeType = 9
-	CodeUnMarshal              sdk.CodeType = 10
-	CodeKeyBase                sdk.CodeType = 11
-	CodeSignMsg                sdk.CodeType = 12
-	CodeAccountAddressExist    sdk.CodeType = 13
-	CodeAccountAddressNotExist sdk.CodeType = 14
-	CodeInvalidLocation        sdk.CodeType = 15
-	CodeBech32Decode           sdk.CodeType = 16
+	CodeInvalidPubKey             sdk.CodeType = 1
+	CodeTimeInterval              sdk.CodeType = 2
+	CodeInvalidIpAdress           sdk.CodeType = 3
+	CodeUnknownIpAddress          sdk.CodeType = 4
+	CodeUnknownSessionid          sdk.CodeType = 5
+	CodeInvalidSessionid          sdk.CodeType = 6
+	CodeInvalidNetspeed           sdk.CodeType = 7
+	CodeInvalidPricePerGb         sdk.CodeType = 8
+	CodeMarshal                   sdk.CodeType = 9
+	CodeUnMarshal                 sdk.CodeType = 10
+	CodeKeyBase                   sdk.CodeType = 11
+	CodeSignMsg                   sdk.CodeType = 12
+	CodeAccountAddressExist       sdk.CodeType = 13
+	CodeAccountAddressNotExist    sdk.CodeType = 14
+	CodeInvalidLocation           sdk.CodeType = 15
+	CodeBech32Decode              sdk.CodeType = 16
+	CodeInvalidDenom              sdk.CodeType = 17
+	CodeInvalidDeposit            sdk.CodeType = 18
+	CodeInvalidSlashFraction      sdk.CodeType = 19
+	CodeUnauthorizedNodeRemoval   sdk.CodeType = 20
+	CodeUnauthorizedMasterRemoval sdk.CodeType = 21
+	CodePriceChangeInterval       sdk.CodeType = 23
+	CodeInvalidUsage              sdk.CodeType = 24
+	CodeInvalidRating             sdk.CodeType = 25
+	CodeUnauthorizedRating        sdk.CodeType = 26
+	CodeInvalidDispute            sdk.CodeType = 27
+	CodeUnauthorizedVerdict       sdk.CodeType = 28
+	CodeDuplicateVerdict          sdk.CodeType = 29
+	CodeInvalidPlan               sdk.CodeType = 30
+	CodeInvalidSubscription       sdk.CodeType = 31
+	CodeInvalidBond               sdk.CodeType = 32
+	CodeInvalidProposal           sdk.CodeType = 33
+	CodeInvalidHeartbeat          sdk.CodeType = 34
 )
 
 func ErrInvalidPubKey(msg string) sdk.Error {
@@ -89,3 +106,71 @@ func ErrBech32Decode(msg string) sdk.Error {
 
 	return sdk.NewError(DefaultCodeSpace, CodeBech32Decode, msg)
 }
+func ErrInvalidDenom(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidDenom, msg)
+}
+func ErrInvalidDeposit(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidDeposit, msg)
+}
+func ErrInvalidSlashFraction(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidSlashFraction, msg)
+}
+func ErrUnauthorizedNodeRemoval(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeUnauthorizedNodeRemoval, msg)
+}
+func ErrUnauthorizedMasterRemoval(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeUnauthorizedMasterRemoval, msg)
+}
+func ErrPriceChangeInterval(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodePriceChangeInterval, msg)
+}
+func ErrInvalidUsage(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidUsage, msg)
+}
+func ErrInvalidRating(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidRating, msg)
+}
+func ErrUnauthorizedRating(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeUnauthorizedRating, msg)
+}
+func ErrInvalidDispute(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidDispute, msg)
+}
+func ErrUnauthorizedVerdict(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeUnauthorizedVerdict, msg)
+}
+func ErrDuplicateVerdict(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeDuplicateVerdict, msg)
+}
+func ErrInvalidPlan(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidPlan, msg)
+}
+func ErrInvalidSubscription(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidSubscription, msg)
+}
+func ErrInvalidBond(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidBond, msg)
+}
+func ErrInvalidProposal(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidProposal, msg)
+}
+func ErrInvalidHeartbeat(msg string) sdk.Error {
+
+	return sdk.NewError(DefaultCodeSpace, CodeInvalidHeartbeat, msg)
+}
@@ -0,0 +1,369 @@
+package sentinel
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// GenesisState - all sentinel state that must be provided at genesis
+type GenesisState struct {
+	Params       Params           `json:"params"`
+	VpnNodes     []VpnNode        `json:"vpn_nodes"`
+	MasterNodes  []sdk.AccAddress `json:"master_nodes"`
+	Sessions     []GenesisSession `json:"sessions"`
+	SessionCount int64            `json:"session_count"` // number of sessions opened, from which session ids are derived
+
+	NodeDeposits      []GenesisNodeDeposit `json:"node_deposits"`
+	UnbondingDeposits []UnbondingDeposit   `json:"unbonding_deposits"`
+	VpnChanges        []VpnChange          `json:"vpn_changes"`
+
+	UsageReceipts []senttype.UsageReceipt `json:"usage_receipts"`
+	Reputations   []Reputation            `json:"reputations"`
+	NodeRatings   []NodeRating            `json:"node_ratings"`
+	Disputes      []Dispute               `json:"disputes"`
+
+	Subscriptions GenesisSubscriptions `json:"subscriptions"`
+	Governance    GenesisGovernance    `json:"governance"`
+}
+
+// GenesisSession - an open session along with its id
+type GenesisSession struct {
+	SessionId string           `json:"session_id"`
+	Session   senttype.Session `json:"session"`
+}
+
+// GenesisSubscriptions - the subscription plans and the subscriptions along
+// with their usage receipts, and the counts from which their ids are derived
+type GenesisSubscriptions struct {
+	Plans             []Plan              `json:"plans"`
+	PlanCount         int64               `json:"plan_count"`
+	Subscriptions     []Subscription      `json:"subscriptions"`
+	SubscriptionCount int64               `json:"subscription_count"`
+	Usages            []SubscriptionUsage `json:"usages"`
+}
+
+// GenesisGovernance - the bonds of the master nodes and the proposals in their
+// voting period, along with the count from which proposal ids are derived
+type GenesisGovernance struct {
+	MasterBonds   []GenesisMasterBond `json:"master_bonds"`
+	Proposals     []Proposal          `json:"proposals"`
+	ProposalCount int64               `json:"proposal_count"`
+}
+
+// GenesisNodeDeposit - the bonded deposit of a dVPN node
+type GenesisNodeDeposit struct {
+	Address sdk.AccAddress `json:"address"`
+	Deposit sdk.Coins      `json:"deposit"`
+}
+
+func NewGenesisState(params Params, vpnNodes []VpnNode, masterNodes []sdk.AccAddress, sessions []GenesisSession, sessionCount int64,
+	nodeDeposits []GenesisNodeDeposit, unbondingDeposits []UnbondingDeposit, vpnChanges []VpnChange,
+	usageReceipts []senttype.UsageReceipt, reputations []Reputation, nodeRatings []NodeRating,
+	disputes []Dispute, subscriptions GenesisSubscriptions, governance GenesisGovernance) GenesisState {
+
+	return GenesisState{
+		Params:            params,
+		VpnNodes:          vpnNodes,
+		MasterNodes:       masterNodes,
+		Sessions:          sessions,
+		SessionCount:      sessionCount,
+		NodeDeposits:      nodeDeposits,
+		UnbondingDeposits: unbondingDeposits,
+		VpnChanges:        vpnChanges,
+		UsageReceipts:     usageReceipts,
+		Reputations:       reputations,
+		NodeRatings:       nodeRatings,
+		Disputes:          disputes,
+		Subscriptions:     subscriptions,
+		Governance:        governance,
+	}
+}
+
+// get raw genesis raw message for testing
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Params: DefaultParams(),
+	}
+}
+
+// ValidateGenesis checks the params and the counts of data, before the
+// genesis state is loaded. The records are checked when they are loaded.
+func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+	if data.SessionCount < 0 || data.Subscriptions.PlanCount < 0 || data.Subscriptions.SubscriptionCount < 0 || data.Governance.ProposalCount < 0 {
+		return errors.Errorf("genesis counts must not be negative, counts: %d, %d, %d, %d",
+			data.SessionCount, data.Subscriptions.PlanCount, data.Subscriptions.SubscriptionCount, data.Governance.ProposalCount)
+	}
+	return nil
+}
+
+// InitGenesis sets the params, registered dVPN nodes and their liveness
+// statuses, master nodes, open sessions, session count, deposits, removal
+// votes, dVPN node changes, usage receipts, reputations, ratings, disputes,
+// plans, subscriptions, master node bonds and proposals found in data. Session coins, deposits and bonds are expected to be already
+// deducted from the client, node and master node accounts, as they are in an
+// exported genesis.
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) error {
+	keeper.setStoreVersion(ctx, StoreVersion)
+	keeper.SetParams(ctx, data.Params)
+
+	for _, node := range data.VpnNodes {
+		if len(node.Address) == 0 {
+			return errors.Errorf("genesis dVPN node has an empty address, node: %v", node.Node)
+		}
+		keeper.SetVpnService(ctx, node.Address, node.Node)
+		// nodes without a status are given the genesis time as their latest heartbeat
+		if node.Status == nil {
+			keeper.recordRegistrationHeartbeat(ctx, node.Address)
+			continue
+		}
+		if !bytes.Equal(node.Status.Node, node.Address) {
+			return errors.Errorf("genesis dVPN node status is not of its node, status: %v", node.Status)
+		}
+		keeper.SetNodeStatus(ctx, *node.Status)
+	}
+
+	for _, addr := range data.MasterNodes {
+		if len(addr) == 0 {
+			return errors.New("genesis master node has an empty address")
+		}
+		keeper.SetMasterNode(ctx, addr)
+	}
+
+	for _, session := range data.Sessions {
+		if len(session.SessionId) == 0 {
+			return errors.Errorf("genesis session has an empty id, session: %v", session.Session)
+		}
+		keeper.SetSession(ctx, []byte(session.SessionId), session.Session)
+	}
+
+	if data.SessionCount < 0 {
+		return errors.Errorf("genesis session count is negative, count: %d", data.SessionCount)
+	}
+	keeper.SetSessionCount(ctx, data.SessionCount)
+
+	for _, deposit := range data.NodeDeposits {
+		if len(deposit.Address) == 0 {
+			return errors.Errorf("genesis node deposit has an empty address, deposit: %v", deposit.Deposit)
+		}
+		keeper.SetNodeDeposit(ctx, deposit.Address, deposit.Deposit)
+	}
+
+	for _, ubd := range data.UnbondingDeposits {
+		if len(ubd.Address) == 0 {
+			return errors.Errorf("genesis unbonding deposit has an empty address, deposit: %v", ubd.Coins)
+		}
+		keeper.SetUnbondingDeposit(ctx, ubd)
+	}
+
+	for _, change := range data.VpnChanges {
+		if len(change.Node) == 0 {
+			return errors.Errorf("genesis dVPN node change has an empty address, change: %v", change)
+		}
+		keeper.SetVpnChange(ctx, change)
+	}
+
+	for _, receipt := range data.UsageReceipts {
+		if _, found := keeper.GetSession(ctx, receipt.SessionId); !found {
+			return errors.Errorf("genesis usage receipt has no open session, receipt: %v", receipt)
+		}
+		keeper.SetUsageReceipt(ctx, receipt)
+	}
+
+	for _, rep := range data.Reputations {
+		if len(rep.Node) == 0 {
+			return errors.Errorf("genesis reputation has an empty address, reputation: %v", rep)
+		}
+		if rep.BandwidthServed == (sdk.Int{}) || rep.BandwidthServed.Sign() < 0 {
+			return errors.Errorf("genesis reputation has a negative or missing bandwidth, reputation: %v", rep)
+		}
+		keeper.SetReputation(ctx, rep)
+	}
+
+	for _, rating := range data.NodeRatings {
+		if len(rating.Node) == 0 || len(rating.Client) == 0 {
+			return errors.Errorf("genesis node rating has an empty address, rating: %v", rating)
+		}
+		if rating.Rating < 0 || rating.Rating > MaxRating {
+			return errors.Errorf("genesis node rating is out of range, rating: %v", rating)
+		}
+		keeper.SetNodeRating(ctx, rating)
+	}
+
+	for _, dispute := range data.Disputes {
+		session, found := keeper.GetSession(ctx, dispute.SessionId)
+		if !found || session.Status != senttype.StatusDisputed {
+			return errors.Errorf("genesis dispute has no disputed session, dispute: %v", dispute)
+		}
+		keeper.SetDispute(ctx, dispute)
+	}
+
+	err := initGenesisSubscriptions(ctx, keeper, data.Subscriptions)
+	if err != nil {
+		return err
+	}
+	return initGenesisGovernance(ctx, keeper, data.Governance)
+}
+
+func initGenesisSubscriptions(ctx sdk.Context, keeper Keeper, data GenesisSubscriptions) error {
+	if data.PlanCount < 0 || data.SubscriptionCount < 0 {
+		return errors.Errorf("genesis plan or subscription count is negative, counts: %d, %d", data.PlanCount, data.SubscriptionCount)
+	}
+	keeper.SetPlanCount(ctx, data.PlanCount)
+	keeper.SetSubscriptionCount(ctx, data.SubscriptionCount)
+
+	for _, plan := range data.Plans {
+		if len(plan.Id) == 0 || len(plan.Provider) == 0 {
+			return errors.Errorf("genesis plan has an empty id or provider, plan: %v", plan)
+		}
+		keeper.SetPlan(ctx, plan)
+	}
+
+	for _, sub := range data.Subscriptions {
+		if len(sub.Id) == 0 || len(sub.Client) == 0 || len(sub.Provider) == 0 || sub.ClientPubKey == nil {
+			return errors.Errorf("genesis subscription has an empty id, client, provider or client key, subscription: %v", sub)
+		}
+		if sub.Used == (sdk.Int{}) || sub.Used.Sign() < 0 {
+			return errors.Errorf("genesis subscription has a negative or missing used bytes, subscription: %v", sub)
+		}
+		keeper.SetSubscription(ctx, sub)
+	}
+
+	for _, usage := range data.Usages {
+		sub, found := keeper.GetSubscription(ctx, usage.Receipt.SessionId)
+		if !found || !sub.HasNode(usage.Node) {
+			return errors.Errorf("genesis subscription usage has no subscription served by its node, usage: %v", usage)
+		}
+		keeper.SetSubscriptionUsage(ctx, usage)
+	}
+	return nil
+}
+
+func initGenesisGovernance(ctx sdk.Context, keeper Keeper, data GenesisGovernance) error {
+	for _, bond := range data.MasterBonds {
+		if !keeper.IsMasterNode(ctx, bond.Address) {
+			return errors.Errorf("genesis master node bond has no master node, bond: %v", bond)
+		}
+		keeper.SetMasterBond(ctx, bond.Address, bond.Bond)
+	}
+
+	if data.ProposalCount < 0 {
+		return errors.Errorf("genesis proposal count is negative, count: %d", data.ProposalCount)
+	}
+	keeper.SetProposalCount(ctx, data.ProposalCount)
+
+	for _, proposal := range data.Proposals {
+		if len(proposal.Id) == 0 || len(proposal.Proposer) == 0 {
+			return errors.Errorf("genesis proposal has an empty id or proposer, proposal: %v", proposal)
+		}
+		if proposal.Kind == ProposalKindChangeParams && proposal.Params == nil {
+			return errors.Errorf("genesis params change proposal has no params, proposal: %v", proposal)
+		}
+		if proposal.Kind == ProposalKindSlashDeposit && NewDepositSlash(proposal.Node, proposal.Fraction).ValidateBasic() != nil {
+			return errors.Errorf("genesis deposit slash proposal has an invalid node or fraction, proposal: %v", proposal)
+		}
+		keeper.SetProposal(ctx, proposal)
+	}
+	return nil
+}
+
+// WriteGenesis returns a GenesisState for a given context and keeper. The
+// GenesisState will contain the params, dVPN nodes with their liveness
+// statuses, master nodes, sessions, session count, deposits, removal votes,
+// dVPN node changes, usage receipts, reputations, ratings, disputes, plans,
+// subscriptions, master node bonds and proposals found in the keeper.
+func WriteGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	vpnNodes := keeper.withStatuses(ctx, keeper.GetVpnServices(ctx))
+
+	var masterNodes []sdk.AccAddress
+	keeper.IterateMasterNodes(ctx, func(addr sdk.AccAddress) (stop bool) {
+		masterNodes = append(masterNodes, addr)
+		return false
+	})
+
+	var sessions []GenesisSession
+	keeper.IterateSessions(ctx, func(sessionId []byte, session senttype.Session) (stop bool) {
+		sessions = append(sessions, GenesisSession{string(sessionId), session})
+		return false
+	})
+
+	var nodeDeposits []GenesisNodeDeposit
+	keeper.IterateNodeDeposits(ctx, func(addr sdk.AccAddress, deposit sdk.Coins) (stop bool) {
+		nodeDeposits = append(nodeDeposits, GenesisNodeDeposit{addr, deposit})
+		return false
+	})
+
+	var unbondingDeposits []UnbondingDeposit
+	keeper.IterateUnbondingDeposits(ctx, func(ubd UnbondingDeposit) (stop bool) {
+		unbondingDeposits = append(unbondingDeposits, ubd)
+		return false
+	})
+
+	var vpnChanges []VpnChange
+	keeper.IterateVpnChanges(ctx, func(change VpnChange) (stop bool) {
+		vpnChanges = append(vpnChanges, change)
+		return false
+	})
+
+	var usageReceipts []senttype.UsageReceipt
+	keeper.IterateUsageReceipts(ctx, func(receipt senttype.UsageReceipt) (stop bool) {
+		usageReceipts = append(usageReceipts, receipt)
+		return false
+	})
+
+	var reputations []Reputation
+	keeper.IterateReputations(ctx, func(rep Reputation) (stop bool) {
+		reputations = append(reputations, rep)
+		return false
+	})
+
+	var nodeRatings []NodeRating
+	keeper.IterateNodeRatings(ctx, func(rating NodeRating) (stop bool) {
+		nodeRatings = append(nodeRatings, rating)
+		return false
+	})
+
+	var disputes []Dispute
+	keeper.IterateDisputes(ctx, func(dispute Dispute) (stop bool) {
+		disputes = append(disputes, dispute)
+		return false
+	})
+
+	subscriptions := GenesisSubscriptions{
+		PlanCount:         keeper.GetPlanCount(ctx),
+		SubscriptionCount: keeper.GetSubscriptionCount(ctx),
+	}
+	keeper.IteratePlans(ctx, func(plan Plan) (stop bool) {
+		subscriptions.Plans = append(subscriptions.Plans, plan)
+		return false
+	})
+	keeper.IterateSubscriptions(ctx, func(sub Subscription) (stop bool) {
+		subscriptions.Subscriptions = append(subscriptions.Subscriptions, sub)
+		return false
+	})
+	keeper.IterateSubscriptionUsages(ctx, func(usage SubscriptionUsage) (stop bool) {
+		subscriptions.Usages = append(subscriptions.Usages, usage)
+		return false
+	})
+
+	governance := GenesisGovernance{
+		ProposalCount: keeper.GetProposalCount(ctx),
+	}
+	keeper.IterateMasterBonds(ctx, func(addr sdk.AccAddress, bond sdk.Coin) (stop bool) {
+		governance.MasterBonds = append(governance.MasterBonds, GenesisMasterBond{addr, bond})
+		return false
+	})
+	keeper.IterateProposals(ctx, func(proposal Proposal) (stop bool) {
+		governance.Proposals = append(governance.Proposals, proposal)
+		return false
+	})
+
+	return NewGenesisState(keeper.GetParams(ctx), vpnNodes, masterNodes, sessions, keeper.GetSessionCount(ctx), nodeDeposits, unbondingDeposits,
+		vpnChanges, usageReceipts, reputations, nodeRatings, disputes, subscriptions, governance)
+}
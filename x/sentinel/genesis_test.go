@@ -0,0 +1,108 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestInitGenesisEmptyAddress(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	genesis := DefaultGenesisState()
+	genesis.MasterNodes = []sdk.AccAddress{nil}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.VpnNodes = []VpnNode{{nil, newTestVpnNode(), nil, nil}}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.VpnNodes = []VpnNode{{addrs[0], newTestVpnNode(), &NodeStatus{Node: addrs[1], Active: true}, nil}}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.Subscriptions.Usages = []SubscriptionUsage{{addrs[0], senttype.NewUsageReceipt([]byte("missing"), 1, 0, 0, 0, nil, nil)}}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.Governance.MasterBonds = []GenesisMasterBond{{addrs[0], sdk.NewCoin("sut", 100)}}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.SessionCount = -1
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.UsageReceipts = []senttype.UsageReceipt{senttype.NewUsageReceipt([]byte("missing"), 1, 0, 0, 0, nil, nil)}
+	require.NotNil(t, InitGenesis(ctx, keeper, genesis))
+}
+
+func TestGenesisImportExport(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	session := senttype.GetNewSessionMap(coins, pks[0], pks[1], addrs[1], 1537361017)
+	plan := Plan{"00000000000000000001", addrs[0], []sdk.AccAddress{addrs[0]}, coins, 3600, 0}
+	sub := Subscription{"00000000000000000001", plan.Id, addrs[1], pks[1], addrs[0], plan.Nodes, coins, 1537361017, 1537364617, 0, sdk.NewInt(1100)}
+	params := DefaultParams()
+	params.VotingPeriod = 86400
+	proposal := Proposal{"00000000000000000001", ProposalKindChangeParams, addrs[2], nil, &params, sdk.Rat{}, 1537447417, []ProposalVote{{addrs[2], VoteOptionYes}}}
+	genesis := NewGenesisState(
+		DefaultParams(),
+		[]VpnNode{{addrs[0], newTestVpnNode(), nil, nil}},
+		[]sdk.AccAddress{addrs[2]},
+		[]GenesisSession{{"0123456789abcdef0123", session}},
+		7,
+		[]GenesisNodeDeposit{{addrs[0], sdk.Coins{{"sut", sdk.NewInt(100)}}}},
+		[]UnbondingDeposit{{addrs[1], sdk.Coins{{"sut", sdk.NewInt(50)}}, 1537361017}},
+		[]VpnChange{{addrs[0], 0, 10, 1537361017, newTestVpnNode(), newTestVpnNode()}},
+		[]senttype.UsageReceipt{senttype.NewUsageReceipt([]byte("0123456789abcdef0123"), 1, 100, 1000, 60, nil, nil)},
+		[]Reputation{{addrs[0], 3, 2, 1, sdk.NewInt(5000), 1, 4}},
+		[]NodeRating{{addrs[0], addrs[1], 4}},
+		nil,
+		GenesisSubscriptions{
+			Plans:             []Plan{plan},
+			PlanCount:         1,
+			Subscriptions:     []Subscription{sub},
+			SubscriptionCount: 1,
+			Usages:            []SubscriptionUsage{{addrs[0], senttype.NewUsageReceipt([]byte(sub.Id), 1, 100, 1000, 60, nil, nil)}},
+		},
+		GenesisGovernance{
+			MasterBonds:   []GenesisMasterBond{{addrs[2], sdk.NewCoin("sut", 100)}},
+			Proposals:     []Proposal{proposal},
+			ProposalCount: 1,
+		},
+	)
+	err := InitGenesis(ctx, keeper, genesis)
+	require.Nil(t, err)
+
+	// the same address may be both a dVPN node and a master node
+	keeper.SetMasterNode(ctx, addrs[0])
+	genesis.MasterNodes = []sdk.AccAddress{addrs[0], addrs[2]}
+
+	exported := WriteGenesis(ctx, keeper)
+	require.Equal(t, genesis.Params, exported.Params)
+	require.Equal(t, len(genesis.VpnNodes), len(exported.VpnNodes))
+	require.Equal(t, genesis.VpnNodes[0].Address, exported.VpnNodes[0].Address)
+	require.Equal(t, genesis.VpnNodes[0].Node, exported.VpnNodes[0].Node)
+	require.Equal(t, &NodeStatus{Node: addrs[0], LastHeartbeat: ctx.BlockHeader().Time, Active: true}, exported.VpnNodes[0].Status)
+	require.Equal(t, len(genesis.MasterNodes), len(exported.MasterNodes))
+	require.Equal(t, len(genesis.Sessions), len(exported.Sessions))
+	require.Equal(t, genesis.Sessions[0].SessionId, exported.Sessions[0].SessionId)
+	require.Equal(t, genesis.SessionCount, exported.SessionCount)
+	require.Equal(t, session.TotalLockedCoins, exported.Sessions[0].Session.TotalLockedCoins)
+	require.Equal(t, session.CAddress, exported.Sessions[0].Session.CAddress)
+	require.Equal(t, genesis.NodeDeposits, exported.NodeDeposits)
+	require.Equal(t, genesis.UnbondingDeposits, exported.UnbondingDeposits)
+	require.Equal(t, genesis.VpnChanges, exported.VpnChanges)
+	require.Equal(t, genesis.UsageReceipts, exported.UsageReceipts)
+	require.Equal(t, genesis.Reputations, exported.Reputations)
+	require.Equal(t, genesis.NodeRatings, exported.NodeRatings)
+	require.Equal(t, genesis.Subscriptions, exported.Subscriptions)
+	require.Equal(t, genesis.Governance, exported.Governance)
+	require.Equal(t, []sdk.AccAddress{addrs[1]}, keeper.getMatureUnbondingDeposits(ctx, 1537361017))
+}
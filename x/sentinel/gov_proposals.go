@@ -0,0 +1,98 @@
+package sentinel
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// GovRoute - the route of the governance proposal contents of the module
+const GovRoute = "sentinel"
+
+// SlashNodeDepositProposal - the content of a governance proposal slashing
+// the deposit of a dVPN node once it passes
+type SlashNodeDepositProposal struct {
+	Slash DepositSlash `json:"slash"`
+}
+
+// NewSlashNodeDepositProposal creates the content of a deposit slash proposal
+func NewSlashNodeDepositProposal(slash DepositSlash) SlashNodeDepositProposal {
+	return SlashNodeDepositProposal{
+		Slash: slash,
+	}
+}
+
+var _ gov.ProposalContent = SlashNodeDepositProposal{}
+
+// nolint
+func (p SlashNodeDepositProposal) ProposalRoute() string    { return GovRoute }
+func (p SlashNodeDepositProposal) ValidateBasic() sdk.Error { return p.Slash.ValidateBasic() }
+func (p SlashNodeDepositProposal) String() string           { return p.Slash.String() }
+
+// RemoveMasterNodeProposal - the content of a governance proposal removing a
+// master node once it passes
+type RemoveMasterNodeProposal struct {
+	MasterNode sdk.AccAddress `json:"master_node"`
+}
+
+// NewRemoveMasterNodeProposal creates the content of a master node removal proposal
+func NewRemoveMasterNodeProposal(masterNode sdk.AccAddress) RemoveMasterNodeProposal {
+	return RemoveMasterNodeProposal{
+		MasterNode: masterNode,
+	}
+}
+
+var _ gov.ProposalContent = RemoveMasterNodeProposal{}
+
+// nolint
+func (p RemoveMasterNodeProposal) ProposalRoute() string { return GovRoute }
+func (p RemoveMasterNodeProposal) String() string {
+	return fmt.Sprintf("Master Node Removal\n  Master Node: %s", p.MasterNode)
+}
+
+func (p RemoveMasterNodeProposal) ValidateBasic() sdk.Error {
+	if len(p.MasterNode) == 0 {
+		return sdk.ErrInvalidAddress("Master node address is Invalid")
+	}
+	return nil
+}
+
+// NewGovProposalHandler returns the handler of the sentinel governance
+// proposals, registered by the app on the gov proposal router under GovRoute
+func NewGovProposalHandler(keeper Keeper) gov.ProposalHandler {
+	return govProposalHandler{keeper}
+}
+
+type govProposalHandler struct {
+	keeper Keeper
+}
+
+func (h govProposalHandler) CheckProposal(ctx sdk.Context, content gov.ProposalContent) sdk.Error {
+	switch content := content.(type) {
+	case SlashNodeDepositProposal:
+		if !h.keeper.hasDeposit(ctx, content.Slash.Node) {
+			return ErrInvalidDeposit(fmt.Sprintf("No deposit found for %s", content.Slash.Node))
+		}
+		return nil
+	case RemoveMasterNodeProposal:
+		if !h.keeper.IsMasterNode(ctx, content.MasterNode) {
+			return ErrAccountAddressNotExist(fmt.Sprintf("%s is not a master node", content.MasterNode))
+		}
+		return nil
+	default:
+		return sdk.ErrUnknownRequest("Unrecognized sentinel proposal content")
+	}
+}
+
+func (h govProposalHandler) ExecuteProposal(ctx sdk.Context, content gov.ProposalContent) sdk.Error {
+	switch content := content.(type) {
+	case SlashNodeDepositProposal:
+		_, err := h.keeper.SlashDeposit(ctx, content.Slash)
+		return err
+	case RemoveMasterNodeProposal:
+		return h.keeper.RemoveMasterNode(ctx, content.MasterNode)
+	default:
+		return sdk.ErrUnknownRequest("Unrecognized sentinel proposal content")
+	}
+}
@@ -0,0 +1,41 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestGovProposalHandler(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	handler := NewGovProposalHandler(keeper)
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+
+	// only deposits and master nodes which exist may be proposed for a slash
+	// or a removal
+	slash := NewSlashNodeDepositProposal(NewDepositSlash(addrs[1], sdk.NewRat(1, 4)))
+	require.Equal(t, CodeInvalidDeposit, handler.CheckProposal(ctx, slash).Code())
+	removal := NewRemoveMasterNodeProposal(addrs[1])
+	require.Equal(t, CodeAccountAddressNotExist, handler.CheckProposal(ctx, removal).Code())
+
+	slash = NewSlashNodeDepositProposal(NewDepositSlash(addrs[0], sdk.NewRat(1, 4)))
+	require.Nil(t, handler.CheckProposal(ctx, slash))
+	require.Nil(t, handler.ExecuteProposal(ctx, slash))
+	require.Equal(t, int64(75), keeper.GetNodeDeposit(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	keeper.SetMasterNode(ctx, addrs[1])
+	require.Nil(t, handler.CheckProposal(ctx, removal))
+	require.Nil(t, handler.ExecuteProposal(ctx, removal))
+	require.False(t, keeper.IsMasterNode(ctx, addrs[1]))
+}
+
+func TestGovProposalContentValidateBasic(t *testing.T) {
+	require.Nil(t, NewSlashNodeDepositProposal(NewDepositSlash(addrs[0], sdk.NewRat(1, 2))).ValidateBasic())
+	require.NotNil(t, NewSlashNodeDepositProposal(NewDepositSlash(addrs[0], sdk.NewRat(2, 1))).ValidateBasic())
+	require.NotNil(t, NewSlashNodeDepositProposal(DepositSlash{}).ValidateBasic())
+	require.Nil(t, NewRemoveMasterNodeProposal(addrs[1]).ValidateBasic())
+	require.NotNil(t, NewRemoveMasterNodeProposal(nil).ValidateBasic())
+}
@@ -0,0 +1,371 @@
+package sentinel
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Kinds of sentinel proposals
+const (
+	ProposalKindRemoveNode   uint8 = 1 // removes a registered dVPN node
+	ProposalKindChangeParams uint8 = 2 // replaces the sentinel params
+	ProposalKindSlashDeposit uint8 = 3 // slashes the deposit of a dVPN node
+)
+
+// Options of a master node vote on a proposal
+const (
+	VoteOptionYes uint8 = 1
+	VoteOptionNo  uint8 = 2
+)
+
+// name of a proposal kind, as tagged
+func proposalKindString(kind uint8) string {
+	switch kind {
+	case ProposalKindRemoveNode:
+		return "removeNode"
+	case ProposalKindChangeParams:
+		return "changeParams"
+	case ProposalKindSlashDeposit:
+		return "slashDeposit"
+	}
+	return "unknown"
+}
+
+// GenesisMasterBond - the bond of a master node
+type GenesisMasterBond struct {
+	Address sdk.AccAddress `json:"address"`
+	Bond    sdk.Coin       `json:"bond"`
+}
+
+// ProposalVote - the vote of a master node on a proposal
+type ProposalVote struct {
+	MasterNode sdk.AccAddress `json:"master_node"`
+	Option     uint8          `json:"option"`
+}
+
+// Proposal - a sentinel proposal of a master node, on which the master nodes
+// vote with the weight of their bonds until the end of the voting period,
+// when it is tallied by the EndBlocker
+type Proposal struct {
+	Id        string         `json:"id"`
+	Kind      uint8          `json:"kind"`
+	Proposer  sdk.AccAddress `json:"proposer"`
+	Node      sdk.AccAddress `json:"node"`     // dVPN node removed or slashed by the proposal
+	Params    *Params        `json:"params"`   // params set by a params change proposal
+	Fraction  sdk.Rat        `json:"fraction"` // fraction of the deposit burned by a deposit slash proposal
+	VotingEnd int64          `json:"voting_end"`
+	Votes     []ProposalVote `json:"votes"`
+}
+
+// set the vote of a master node, replacing its previous vote
+func (proposal *Proposal) setVote(masterNode sdk.AccAddress, option uint8) {
+	for i, vote := range proposal.Votes {
+		if bytes.Equal(vote.MasterNode, masterNode) {
+			proposal.Votes[i].Option = option
+			return
+		}
+	}
+	proposal.Votes = append(proposal.Votes, ProposalVote{MasterNode: masterNode, Option: option})
+}
+
+// TallyResult - the bonds of the master nodes which voted for and against a
+// proposal, and of all the master nodes
+type TallyResult struct {
+	Yes   sdk.Int `json:"yes"`
+	No    sdk.Int `json:"no"`
+	Total sdk.Int `json:"total"`
+}
+
+// Tally sums the weights of the votes on the proposal, weightOf returning zero
+// for addresses which are no longer master nodes
+func (proposal Proposal) Tally(weightOf func(addr sdk.AccAddress) sdk.Int, total sdk.Int) TallyResult {
+	result := TallyResult{Yes: sdk.ZeroInt(), No: sdk.ZeroInt(), Total: total}
+	for _, vote := range proposal.Votes {
+		switch vote.Option {
+		case VoteOptionYes:
+			result.Yes = result.Yes.Add(weightOf(vote.MasterNode))
+		case VoteOptionNo:
+			result.No = result.No.Add(weightOf(vote.MasterNode))
+		}
+	}
+	return result
+}
+
+// check if the voting bonds reach the quorum and the bonds voting for the
+// proposal are above the threshold of the voting bonds
+func (result TallyResult) Passes(quorum sdk.Rat, threshold sdk.Rat) bool {
+	voted := result.Yes.Add(result.No)
+	if result.Total.IsZero() || voted.IsZero() {
+		return false
+	}
+	if sdk.NewRatFromInt(voted, result.Total).LT(quorum) {
+		return false
+	}
+	return sdk.NewRatFromInt(result.Yes, voted).GT(threshold)
+}
+
+// ProposalInfo - a proposal in its voting period along with its current tally
+type ProposalInfo struct {
+	Proposal Proposal    `json:"proposal"`
+	Tally    TallyResult `json:"tally"`
+}
+
+// MasterNodeInfo - a master node along with its bond, which is its voting weight
+type MasterNodeInfo struct {
+	Address sdk.AccAddress `json:"address"`
+	Bond    sdk.Coin       `json:"bond"`
+}
+
+// get the bond of a master node
+func (keeper Keeper) GetMasterBond(ctx sdk.Context, addr sdk.AccAddress) (bond sdk.Coin, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetMasterBondKey(addr))
+	if bz == nil {
+		return bond, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &bond)
+	return bond, true
+}
+
+// set the bond of a master node
+func (keeper Keeper) SetMasterBond(ctx sdk.Context, addr sdk.AccAddress, bond sdk.Coin) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(bond)
+	store.Set(GetMasterBondKey(addr), bz)
+}
+
+// iterate through the bonds of the master nodes, execute func for each
+func (keeper Keeper) IterateMasterBonds(ctx sdk.Context, fn func(addr sdk.AccAddress, bond sdk.Coin) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, MasterBondKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var bond sdk.Coin
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &bond)
+		if fn(GetAddressFromKey(iterator.Key()), bond) {
+			break
+		}
+	}
+}
+
+// lock the bond of a registering master node in escrow
+func (keeper Keeper) bondMasterNode(ctx sdk.Context, addr sdk.AccAddress, bond sdk.Coin) sdk.Error {
+	minBond := keeper.GetParams(ctx).MinMasterBond
+	if !bond.IsGTE(minBond) {
+		return ErrInvalidBond(fmt.Sprintf("Bond must be at least %s", minBond))
+	}
+	if bond.IsPositive() {
+		_, _, err := keeper.coinKeeper.SubtractCoins(ctx, addr, sdk.Coins{bond})
+		if err != nil {
+			return err
+		}
+	}
+	keeper.SetMasterBond(ctx, addr, bond)
+	return nil
+}
+
+// start unbonding the bond of a removed master node, along with any unbonding
+// deposit of the address
+func (keeper Keeper) unbondMasterNode(ctx sdk.Context, addr sdk.AccAddress) {
+	bond, found := keeper.GetMasterBond(ctx, addr)
+	if !found {
+		return
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetMasterBondKey(addr))
+	if bond.IsPositive() {
+		keeper.startUnbonding(ctx, addr, sdk.Coins{bond})
+	}
+}
+
+// the voting weight of an address: the bond of a master node, zero for other
+// addresses and master nodes registered without a bond
+func (keeper Keeper) masterNodeWeight(ctx sdk.Context, addr sdk.AccAddress) sdk.Int {
+	if !keeper.IsMasterNode(ctx, addr) {
+		return sdk.ZeroInt()
+	}
+	bond, found := keeper.GetMasterBond(ctx, addr)
+	if !found {
+		return sdk.ZeroInt()
+	}
+	return bond.Amount
+}
+
+// the sum of the voting weights of the master nodes
+func (keeper Keeper) totalMasterNodeWeight(ctx sdk.Context) sdk.Int {
+	total := sdk.ZeroInt()
+	keeper.IterateMasterNodes(ctx, func(addr sdk.AccAddress) (stop bool) {
+		total = total.Add(keeper.masterNodeWeight(ctx, addr))
+		return false
+	})
+	return total
+}
+
+// tally a proposal with the bonds of the current master nodes
+func (keeper Keeper) tally(ctx sdk.Context, proposal Proposal) TallyResult {
+	return proposal.Tally(func(addr sdk.AccAddress) sdk.Int {
+		return keeper.masterNodeWeight(ctx, addr)
+	}, keeper.totalMasterNodeWeight(ctx))
+}
+
+// GetProposals returns the proposals in their voting period, ordered by id,
+// along with their current tally
+func (keeper Keeper) GetProposals(ctx sdk.Context) []ProposalInfo {
+	proposals := []ProposalInfo{}
+	keeper.IterateProposals(ctx, func(proposal Proposal) (stop bool) {
+		proposals = append(proposals, ProposalInfo{proposal, keeper.tally(ctx, proposal)})
+		return false
+	})
+	return proposals
+}
+
+// get a proposal by id
+func (keeper Keeper) GetProposal(ctx sdk.Context, proposalId []byte) (proposal Proposal, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetProposalKey(proposalId))
+	if bz == nil {
+		return proposal, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &proposal)
+	return proposal, true
+}
+
+// set a proposal along with its entry in the proposal queue
+func (keeper Keeper) SetProposal(ctx sdk.Context, proposal Proposal) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(proposal)
+	store.Set(GetProposalKey([]byte(proposal.Id)), bz)
+	store.Set(GetProposalQueueKey(proposal.VotingEnd, []byte(proposal.Id)), []byte{})
+}
+
+// remove a proposal along with its queue entry
+func (keeper Keeper) RemoveProposal(ctx sdk.Context, proposal Proposal) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetProposalKey([]byte(proposal.Id)))
+	store.Delete(GetProposalQueueKey(proposal.VotingEnd, []byte(proposal.Id)))
+}
+
+// iterate through the proposals, ordered by id, execute func for each
+func (keeper Keeper) IterateProposals(ctx sdk.Context, fn func(proposal Proposal) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, ProposalKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var proposal Proposal
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &proposal)
+		if fn(proposal) {
+			break
+		}
+	}
+}
+
+// get the number of proposals submitted on the chain
+func (keeper Keeper) GetProposalCount(ctx sdk.Context) int64 {
+	return keeper.getCount(ctx, ProposalCountKey)
+}
+
+// set the number of proposals submitted on the chain
+func (keeper Keeper) SetProposalCount(ctx sdk.Context, count int64) {
+	keeper.setCount(ctx, ProposalCountKey, count)
+}
+
+// get the ids of the proposals of the queue with a voting period ended by now
+func (keeper Keeper) getEndedProposalIds(ctx sdk.Context, now int64) (ids [][]byte) {
+	if now < 0 {
+		return nil
+	}
+	return keeper.getQueuedSessionIds(ctx, ProposalQueueKey, GetProposalQueueTimeKey(now+1))
+}
+
+// SubmitProposal opens the voting on a proposal of a master node until the end
+// of the voting period
+func (keeper Keeper) SubmitProposal(ctx sdk.Context, msg MsgSubmitProposal) (Proposal, sdk.Error) {
+	if !keeper.IsMasterNode(ctx, msg.From) {
+		return Proposal{}, sdk.ErrUnauthorized("Only master nodes may submit proposals")
+	}
+	params := keeper.GetParams(ctx)
+	switch msg.Kind {
+	case ProposalKindRemoveNode:
+		if _, found := keeper.GetVpnService(ctx, msg.Node); !found {
+			return Proposal{}, ErrInvalidProposal(fmt.Sprintf("%s is not registered as VPN node", msg.Node))
+		}
+	case ProposalKindChangeParams:
+		if msg.Params.MinMasterBond.Denom != params.MinMasterBond.Denom {
+			return Proposal{}, ErrInvalidProposal("Denom of the master node bonds may not change")
+		}
+	case ProposalKindSlashDeposit:
+		if !keeper.hasDeposit(ctx, msg.Node) {
+			return Proposal{}, ErrInvalidDeposit(fmt.Sprintf("No deposit found for %s", msg.Node))
+		}
+	}
+
+	count := keeper.GetProposalCount(ctx) + 1
+	proposal := Proposal{
+		Id:        string(GetProposalId(count)),
+		Kind:      msg.Kind,
+		Proposer:  msg.From,
+		Node:      msg.Node,
+		Params:    msg.Params,
+		Fraction:  msg.Fraction,
+		VotingEnd: ctx.BlockHeader().Time + params.VotingPeriod,
+	}
+	keeper.SetProposalCount(ctx, count)
+	keeper.SetProposal(ctx, proposal)
+	return proposal, nil
+}
+
+// Vote records the vote of a master node on a proposal until the end of its
+// voting period, replacing its previous vote
+func (keeper Keeper) Vote(ctx sdk.Context, msg MsgVote) sdk.Error {
+	proposal, found := keeper.GetProposal(ctx, msg.ProposalId)
+	if !found {
+		return ErrInvalidProposal("No proposal found with the id")
+	}
+	if !keeper.IsMasterNode(ctx, msg.From) {
+		return sdk.ErrUnauthorized("Only master nodes may vote on proposals")
+	}
+	if ctx.BlockHeader().Time >= proposal.VotingEnd {
+		return ErrTimeInterval("Voting period of the proposal is over")
+	}
+	proposal.setVote(msg.From, msg.Option)
+	keeper.SetProposal(ctx, proposal)
+	return nil
+}
+
+// TallyProposal removes a proposal at the end of its voting period, tallied
+// with the bonds of the current master nodes, and executes it if it passes,
+// returning the coins slashed by a passed deposit slash proposal. A dVPN node
+// which is no longer registered is not removed again, nor is a node whose
+// deposit was fully returned slashed.
+func (keeper Keeper) TallyProposal(ctx sdk.Context, proposalId []byte) (proposal Proposal, result TallyResult, slashed sdk.Coins, passed bool, err sdk.Error) {
+	proposal, found := keeper.GetProposal(ctx, proposalId)
+	if !found {
+		return proposal, result, nil, false, ErrInvalidProposal("No proposal found with the id")
+	}
+	keeper.RemoveProposal(ctx, proposal)
+
+	result = keeper.tally(ctx, proposal)
+	params := keeper.GetParams(ctx)
+	if !result.Passes(params.ProposalQuorum, params.ProposalThreshold) {
+		return proposal, result, nil, false, nil
+	}
+
+	switch proposal.Kind {
+	case ProposalKindRemoveNode:
+		if _, found := keeper.GetVpnService(ctx, proposal.Node); found {
+			keeper.removeVpnService(ctx, proposal.Node)
+		}
+	case ProposalKindChangeParams:
+		keeper.SetParams(ctx, *proposal.Params)
+	case ProposalKindSlashDeposit:
+		if keeper.hasDeposit(ctx, proposal.Node) {
+			slashed, err = keeper.SlashDeposit(ctx, NewDepositSlash(proposal.Node, proposal.Fraction))
+			if err != nil {
+				return proposal, result, nil, false, err
+			}
+		}
+	}
+	return proposal, result, slashed, true, nil
+}
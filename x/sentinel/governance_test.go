@@ -0,0 +1,206 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestMasterNodeBond(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	unbondingTime := keeper.GetParams(ctx).UnbondingTime
+
+	// the bond must be at least the minimum bond, in its denom
+	_, err := keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 99)))
+	require.Equal(t, CodeInvalidBond, err.Code())
+	_, err = keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("btc", 100)))
+	require.Equal(t, CodeInvalidBond, err.Code())
+	_, err = keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 300)))
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+	require.False(t, keeper.IsMasterNode(ctx, addrs[0]))
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err = keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 150)))
+	require.Nil(t, err)
+	require.Equal(t, initCoins.Int64()-150, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+	require.Equal(t, sdk.NewInt(150), keeper.masterNodeWeight(ctx, addrs[0]))
+	_, err = keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 50)))
+	require.Equal(t, CodeAccountAddressExist, err.Code())
+
+	// the bond is returned once unbonded after the removal of the master node
+	_, err = keeper.DeleteMasterNode(ctx, NewMsgDeleteMasterNode(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	_, found := keeper.GetMasterBond(ctx, addrs[0])
+	require.False(t, found)
+	require.True(t, keeper.masterNodeWeight(ctx, addrs[0]).IsZero())
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + unbondingTime - 1})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, initCoins.Int64()-150, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + unbondingTime})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, initCoins.Int64(), ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+}
+
+func TestProposals(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	for i, amount := range []int64{150, 100, 50} {
+		_, err := keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[i], sdk.NewCoin("sut", amount)))
+		require.Nil(t, err)
+	}
+	nodeAddr := sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())
+	keeper.SetVpnService(ctx, nodeAddr, newTestVpnNode())
+	votingPeriod := keeper.GetParams(ctx).VotingPeriod
+
+	// only master nodes may submit proposals
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err := keeper.SubmitProposal(ctx, NewMsgSubmitProposal(nodeAddr, ProposalKindRemoveNode, nodeAddr, nil))
+	require.Equal(t, sdk.CodeUnauthorized, err.Code())
+	_, err = keeper.SubmitProposal(ctx, NewMsgSubmitProposal(addrs[0], ProposalKindRemoveNode, addrs[1], nil))
+	require.Equal(t, CodeInvalidProposal, err.Code())
+	params := keeper.GetParams(ctx)
+	params.MinMasterBond = sdk.NewCoin("btc", 1)
+	_, err = keeper.SubmitProposal(ctx, NewMsgSubmitProposal(addrs[0], ProposalKindChangeParams, nil, &params))
+	require.Equal(t, CodeInvalidProposal, err.Code())
+
+	removal, err := keeper.SubmitProposal(ctx, NewMsgSubmitProposal(addrs[0], ProposalKindRemoveNode, nodeAddr, nil))
+	require.Nil(t, err)
+	require.Equal(t, 1000+votingPeriod, removal.VotingEnd)
+	params = keeper.GetParams(ctx)
+	params.VotingPeriod = 86400
+	change, err := keeper.SubmitProposal(ctx, NewMsgSubmitProposal(addrs[1], ProposalKindChangeParams, nil, &params))
+	require.Nil(t, err)
+	require.Equal(t, "00000000000000000002", change.Id)
+
+	vote := func(from sdk.AccAddress, proposal Proposal, option uint8) sdk.Error {
+		return keeper.Vote(ctx, NewMsgVote(from, []byte(proposal.Id), option))
+	}
+	require.Equal(t, sdk.CodeUnauthorized, vote(nodeAddr, removal, VoteOptionYes).Code())
+	require.Equal(t, CodeInvalidProposal, vote(addrs[0], Proposal{Id: "unknown"}, VoteOptionYes).Code())
+
+	// the removal passes with 150 of the 250 voting bonds
+	require.Nil(t, vote(addrs[0], removal, VoteOptionYes))
+	require.Nil(t, vote(addrs[1], removal, VoteOptionNo))
+
+	// the params change is rejected once the vote of the larger bond changes
+	require.Nil(t, vote(addrs[1], change, VoteOptionYes))
+	require.Nil(t, vote(addrs[2], change, VoteOptionYes))
+	require.Nil(t, vote(addrs[1], change, VoteOptionNo))
+	proposals := keeper.GetProposals(ctx)
+	require.Equal(t, 2, len(proposals))
+	require.Equal(t, TallyResult{sdk.NewInt(50), sdk.NewInt(100), sdk.NewInt(300)}, proposals[1].Tally)
+
+	// the proposals are tallied at the end of their voting period
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + votingPeriod - 1})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, 2, len(keeper.GetProposals(ctx)))
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + votingPeriod})
+	require.Equal(t, CodeTimeInterval, vote(addrs[2], change, VoteOptionYes).Code())
+	tags := EndBlocker(ctx, keeper)
+	require.NotEmpty(t, tags)
+	require.Empty(t, keeper.GetProposals(ctx))
+	_, found := keeper.GetVpnService(ctx, nodeAddr)
+	require.False(t, found)
+	require.Equal(t, DefaultParams().VotingPeriod, keeper.GetParams(ctx).VotingPeriod)
+}
+
+func TestSlashDepositProposal(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	_, err := keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 100)))
+	require.Nil(t, err)
+	_, err = keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[1], 100))
+	require.Nil(t, err)
+	votingPeriod := keeper.GetParams(ctx).VotingPeriod
+
+	// only nodes with a deposit may be slashed
+	_, err = keeper.SubmitProposal(ctx, NewMsgSubmitSlashProposal(addrs[0], addrs[2], sdk.NewRat(1, 2)))
+	require.Equal(t, CodeInvalidDeposit, err.Code())
+
+	// the deposit is only slashed once the proposal passes
+	proposal, err := keeper.SubmitProposal(ctx, NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.NewRat(1, 2)))
+	require.Nil(t, err)
+	require.Nil(t, keeper.Vote(ctx, NewMsgVote(addrs[0], []byte(proposal.Id), VoteOptionYes)))
+	require.Equal(t, int64(100), keeper.GetNodeDeposit(ctx, addrs[1]).AmountOf("sut").Int64())
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: votingPeriod})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, int64(50), keeper.GetNodeDeposit(ctx, addrs[1]).AmountOf("sut").Int64())
+
+	// a rejected proposal slashes nothing
+	proposal, err = keeper.SubmitProposal(ctx, NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.OneRat()))
+	require.Nil(t, err)
+	require.Nil(t, keeper.Vote(ctx, NewMsgVote(addrs[0], []byte(proposal.Id), VoteOptionNo)))
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 2 * votingPeriod})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, int64(50), keeper.GetNodeDeposit(ctx, addrs[1]).AmountOf("sut").Int64())
+}
+
+func TestTallyResultPasses(t *testing.T) {
+	quorum, threshold := sdk.NewRat(1, 3), sdk.NewRat(1, 2)
+	cases := []struct {
+		yes, no, total int64
+		expectPass     bool
+	}{
+		{0, 0, 0, false},
+		{0, 0, 300, false},
+		{99, 0, 300, false},
+		{100, 0, 300, true},
+		{100, 100, 300, false},
+		{101, 100, 300, true},
+		{150, 150, 300, false},
+		{50, 100, 300, false},
+	}
+
+	for i, tc := range cases {
+		result := TallyResult{sdk.NewInt(tc.yes), sdk.NewInt(tc.no), sdk.NewInt(tc.total)}
+		require.Equal(t, tc.expectPass, result.Passes(quorum, threshold), "test: %v", i)
+	}
+}
+
+func TestMsgProposalValidateBasic(t *testing.T) {
+	params := DefaultParams()
+	invalidParams := DefaultParams()
+	invalidParams.ProposalQuorum = sdk.NewRat(3, 2)
+	cases := []struct {
+		msg        sdk.Msg
+		expectPass bool
+	}{
+		{NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 100)), true},
+		{NewMsgRegisterMasterNode(nil, sdk.NewCoin("sut", 100)), false},
+		{NewMsgRegisterMasterNode(addrs[0], sdk.Coin{}), false},
+		{NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", -1)), false},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindRemoveNode, addrs[1], nil), true},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindChangeParams, nil, &params), true},
+		{NewMsgSubmitProposal(nil, ProposalKindRemoveNode, addrs[1], nil), false},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindRemoveNode, nil, nil), false},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindRemoveNode, addrs[1], &params), false},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindChangeParams, nil, nil), false},
+		{NewMsgSubmitProposal(addrs[0], ProposalKindChangeParams, nil, &invalidParams), false},
+		{NewMsgSubmitProposal(addrs[0], 4, addrs[1], nil), false},
+		{NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.NewRat(1, 2)), true},
+		{NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.OneRat()), true},
+		{NewMsgSubmitSlashProposal(addrs[0], nil, sdk.NewRat(1, 2)), false},
+		{NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.ZeroRat()), false},
+		{NewMsgSubmitSlashProposal(addrs[0], addrs[1], sdk.NewRat(3, 2)), false},
+		{MsgSubmitProposal{addrs[0], ProposalKindRemoveNode, addrs[1], nil, sdk.NewRat(1, 2)}, false},
+		{NewMsgVote(addrs[0], []byte("00000000000000000001"), VoteOptionYes), true},
+		{NewMsgVote(addrs[0], []byte("00000000000000000001"), VoteOptionNo), true},
+		{NewMsgVote(nil, []byte("00000000000000000001"), VoteOptionYes), false},
+		{NewMsgVote(addrs[0], nil, VoteOptionYes), false},
+		{NewMsgVote(addrs[0], []byte("00000000000000000001"), 0), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
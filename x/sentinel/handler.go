@@ -1,7 +1,10 @@
 package sentinel
 
 import (
+	"strconv"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/sentinel/tags"
 )
 
 func NewHandler(k Keeper) sdk.Handler {
@@ -9,6 +12,8 @@ func NewHandler(k Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case MsgRegisterVpnService:
 			return handleRegisterVpnService(ctx, k, msg)
+		case MsgUpdateVpnService:
+			return handleMsgUpdateVpnService(ctx, k, msg)
 		case MsgDeleteVpnUser:
 			return handleDeleteVpnUser(ctx, k, msg)
 		case MsgRegisterMasterNode:
@@ -19,6 +24,26 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleMsgPayVpnService(ctx, k, msg)
 		case MsgGetVpnPayment:
 			return handleMsgGetVpnPayment(ctx, k, msg)
+		case MsgSubmitUsageReceipt:
+			return handleMsgSubmitUsageReceipt(ctx, k, msg)
+		case MsgRateNode:
+			return handleMsgRateNode(ctx, k, msg)
+		case MsgOpenDispute:
+			return handleMsgOpenDispute(ctx, k, msg)
+		case MsgDisputeVerdict:
+			return handleMsgDisputeVerdict(ctx, k, msg)
+		case MsgAddPlan:
+			return handleMsgAddPlan(ctx, k, msg)
+		case MsgSubscribe:
+			return handleMsgSubscribe(ctx, k, msg)
+		case MsgSubmitSubscriptionUsage:
+			return handleMsgSubmitSubscriptionUsage(ctx, k, msg)
+		case MsgSubmitProposal:
+			return handleMsgSubmitProposal(ctx, k, msg)
+		case MsgVote:
+			return handleMsgVote(ctx, k, msg)
+		case MsgNodeHeartbeat:
+			return handleMsgNodeHeartbeat(ctx, k, msg)
 		case MsgRefund:
 			return handleMsgRefund(ctx, k, msg)
 		case MsgSendTokens:
@@ -35,8 +60,13 @@ func handleMsgRegisterMasterNode(ctx sdk.Context, keeper Keeper, msg MsgRegister
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionRegisterMasterNode,
+		tags.MasterNode, []byte(msg.Address.String()),
+		tags.Amount, []byte(msg.Bond.String()),
+	)
 	return sdk.Result{
-		Tags: msg.Tags(),
+		Tags: tags,
 		Data: d,
 	}
 }
@@ -47,7 +77,12 @@ func handleMsgMsgSendTokens(ctx sdk.Context, keeper Keeper, msg MsgSendTokens) s
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tags := sdk.NewTags("Transfer to Address:", []byte(address.String()))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSendTokens,
+		tags.Sender, []byte(msg.From.String()),
+		tags.Recipient, []byte(address.String()),
+		tags.Amount, []byte(msg.Coins.String()),
+	)
 	return sdk.Result{
 		Data: d,
 		Tags: tags,
@@ -62,23 +97,49 @@ func handleRegisterVpnService(ctx sdk.Context, keeper Keeper, msg MsgRegisterVpn
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
 
-	tag := sdk.NewTags("vpn registered address", []byte(msg.From.String()))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionRegisterNode,
+		tags.Node, []byte(msg.From.String()),
+		tags.Amount, []byte(msg.Deposit.String()),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgUpdateVpnService(ctx sdk.Context, keeper Keeper, msg MsgUpdateVpnService) sdk.Result {
+	change, err := keeper.UpdateVpnService(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	resTags := sdk.NewTags(
+		tags.Action, tags.ActionUpdateNode,
+		tags.Node, []byte(msg.From.String()),
+	)
+	if change.IsPriceChange() {
+		resTags = resTags.AppendTag(tags.PricePerGb, []byte(change.New.PricePerGb.String()))
+	}
 	return sdk.Result{
 		Data: d,
-		Tags: tag,
+		Tags: resTags,
 	}
 }
 
 func handleDeleteVpnUser(ctx sdk.Context, keeper Keeper, msg MsgDeleteVpnUser) sdk.Result {
-	_, err := keeper.DeleteVpnService(ctx, msg)
+	err := keeper.DeleteVpnService(ctx, msg)
 	if err != nil {
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tag := sdk.NewTags("deleted Vpn address", []byte(msg.Vaddr))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionDeleteNode,
+		tags.Node, []byte(msg.Vaddr.String()),
+	)
 	return sdk.Result{
 		Data: d,
-		Tags: tag,
+		Tags: tags,
 	}
 }
 
@@ -88,38 +149,180 @@ func handleMsgDeleteMasterNode(ctx sdk.Context, keeper Keeper, msg MsgDeleteMast
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tag := sdk.NewTags("deleted MasterNode address", []byte(msg.Maddr))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionDeleteMasterNode,
+		tags.MasterNode, []byte(msg.Maddr.String()),
+	)
 	return sdk.Result{
 		Data: d,
-		Tags: tag,
+		Tags: tags,
 	}
 }
 
 func handleMsgPayVpnService(ctx sdk.Context, keeper Keeper, msg MsgPayVpnService) sdk.Result {
-	id, toataLockedCoins, err := keeper.PayVpnService(ctx, msg)
+	id, totalLockedCoins, err := keeper.PayVpnService(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionPayVpnService,
+		tags.SessionId, []byte(id),
+		tags.Node, []byte(msg.Vpnaddr.String()),
+		tags.Client, []byte(msg.From.String()),
+		tags.Amount, []byte(totalLockedCoins.String()),
+	)
+	// the session id, of fixed length, so that the ids of the sessions opened
+	// by a transaction can be split from its data
+	return sdk.Result{
+		Data: []byte(id),
+		Tags: tags,
+	}
+}
+func handleMsgGetVpnPayment(ctx sdk.Context, keeper Keeper, msg MsgGetVpnPayment) sdk.Result {
+
+	paid, session, err := keeper.GetVpnPayment(ctx, msg)
 	if err != nil {
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tag := sdk.NewTags("sender address", []byte(msg.From.String())).
-		AppendTag("seesion id", []byte(id)).
-		AppendTag("Total Locked coins", []byte(toataLockedCoins.String()))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSessionPayment,
+		tags.SessionId, msg.Sessionid,
+		tags.Node, []byte(session.VpnAddress().String()),
+		tags.Client, []byte(session.CAddress.String()),
+		tags.Amount, []byte(paid.String()),
+		tags.Released, []byte(session.ReleasedCoins.String()),
+		tags.Status, []byte(sessionStatus(session)),
+	)
 	return sdk.Result{
 		Data: d,
-		Tags: tag,
+		Tags: tags,
 	}
 }
-func handleMsgGetVpnPayment(ctx sdk.Context, keeper Keeper, msg MsgGetVpnPayment) sdk.Result {
 
-	sessionid, clientAddr, toataLockedCoins, err := keeper.GetVpnPayment(ctx, msg)
+func handleMsgSubmitUsageReceipt(ctx sdk.Context, keeper Keeper, msg MsgSubmitUsageReceipt) sdk.Result {
+	paid, session, err := keeper.SubmitUsageReceipt(ctx, msg)
 	if err != nil {
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tags := sdk.NewTags("Vpn Provider Address:", []byte(msg.From.String())).
-		AppendTag("seesionId", sessionid).
-		AppendTag("Client Address", []byte(clientAddr.String())).
-		AppendTag("Total Locked coins", []byte(toataLockedCoins.String()))
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSessionUsage,
+		tags.SessionId, msg.Receipt.SessionId,
+		tags.Node, []byte(session.VpnAddress().String()),
+		tags.Client, []byte(session.CAddress.String()),
+		tags.Bytes, []byte(msg.Receipt.TotalBytes().String()),
+		tags.Amount, []byte(paid.String()),
+		tags.Released, []byte(session.ReleasedCoins.String()),
+		tags.Status, []byte(sessionStatus(session)),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgRateNode(ctx sdk.Context, keeper Keeper, msg MsgRateNode) sdk.Result {
+	err := keeper.RateNode(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionRateNode,
+		tags.Node, []byte(msg.Node.String()),
+		tags.Client, []byte(msg.From.String()),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgOpenDispute(ctx sdk.Context, keeper Keeper, msg MsgOpenDispute) sdk.Result {
+	dispute, err := keeper.OpenDispute(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionOpenDispute,
+		tags.SessionId, msg.Sessionid,
+		tags.Sender, []byte(msg.From.String()),
+		tags.Deadline, []byte(strconv.FormatInt(dispute.Deadline, 10)),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgDisputeVerdict(ctx sdk.Context, keeper Keeper, msg MsgDisputeVerdict) sdk.Result {
+	err := keeper.SubmitVerdict(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionDisputeVerdict,
+		tags.SessionId, msg.Sessionid,
+		tags.MasterNode, []byte(msg.From.String()),
+		tags.Verdict, []byte(verdictString(msg.Verdict)),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgAddPlan(ctx sdk.Context, keeper Keeper, msg MsgAddPlan) sdk.Result {
+	plan, err := keeper.AddPlan(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionAddPlan,
+		tags.PlanId, []byte(plan.Id),
+		tags.Provider, []byte(plan.Provider.String()),
+	)
+	return sdk.Result{
+		Data: []byte(plan.Id),
+		Tags: tags,
+	}
+}
+
+func handleMsgSubscribe(ctx sdk.Context, keeper Keeper, msg MsgSubscribe) sdk.Result {
+	sub, err := keeper.Subscribe(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSubscribe,
+		tags.SubscriptionId, []byte(sub.Id),
+		tags.PlanId, []byte(sub.PlanId),
+		tags.Client, []byte(sub.Client.String()),
+		tags.Provider, []byte(sub.Provider.String()),
+		tags.End, []byte(strconv.FormatInt(sub.End, 10)),
+	)
+	return sdk.Result{
+		Data: []byte(sub.Id),
+		Tags: tags,
+	}
+}
+
+func handleMsgSubmitSubscriptionUsage(ctx sdk.Context, keeper Keeper, msg MsgSubmitSubscriptionUsage) sdk.Result {
+	sub, err := keeper.SubmitSubscriptionUsage(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSubscriptionUsage,
+		tags.SubscriptionId, []byte(sub.Id),
+		tags.Node, []byte(msg.From.String()),
+		tags.Bytes, []byte(msg.Receipt.TotalBytes().String()),
+		tags.Used, []byte(sub.Used.String()),
+	)
 	return sdk.Result{
 		Data: d,
 		Tags: tags,
@@ -132,10 +335,69 @@ func handleMsgRefund(ctx sdk.Context, keeper Keeper, msg MsgRefund) sdk.Result {
 		return err.Result()
 	}
 	d, _ := keeper.cdc.MarshalJSON(msg)
-	tags := sdk.NewTags("client Refund Address:", []byte(address.String())).
-		AppendTag("Refunded balance", []byte(refundedBal.String()))
+	// the balance is refunded once the challenge period is over
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionCloseSession,
+		tags.SessionId, msg.Sessionid,
+		tags.Client, []byte(address.String()),
+		tags.Amount, []byte(refundedBal.String()),
+	)
 	return sdk.Result{
 		Data: d,
 		Tags: tags,
 	}
 }
+
+func handleMsgSubmitProposal(ctx sdk.Context, keeper Keeper, msg MsgSubmitProposal) sdk.Result {
+	proposal, err := keeper.SubmitProposal(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionSubmitProposal,
+		tags.ProposalId, []byte(proposal.Id),
+		tags.Kind, []byte(proposalKindString(proposal.Kind)),
+		tags.Proposer, []byte(proposal.Proposer.String()),
+	)
+	return sdk.Result{
+		Data: []byte(proposal.Id),
+		Tags: tags,
+	}
+}
+
+func handleMsgVote(ctx sdk.Context, keeper Keeper, msg MsgVote) sdk.Result {
+	err := keeper.Vote(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(msg)
+	tags := sdk.NewTags(
+		tags.Action, tags.ActionVote,
+		tags.ProposalId, msg.ProposalId,
+		tags.MasterNode, []byte(msg.From.String()),
+	)
+	return sdk.Result{
+		Data: d,
+		Tags: tags,
+	}
+}
+
+func handleMsgNodeHeartbeat(ctx sdk.Context, keeper Keeper, msg MsgNodeHeartbeat) sdk.Result {
+	status, reactivated, err := keeper.NodeHeartbeat(ctx, msg)
+	if err != nil {
+		return err.Result()
+	}
+	d, _ := keeper.cdc.MarshalJSON(status)
+	resTags := sdk.NewTags(
+		tags.Action, tags.ActionNodeHeartbeat,
+		tags.Node, []byte(msg.From.String()),
+		tags.Status, []byte(msg.Status),
+	)
+	if reactivated {
+		resTags = resTags.AppendTag(tags.Reactivated, []byte("true"))
+	}
+	return sdk.Result{
+		Data: d,
+		Tags: resTags,
+	}
+}
@@ -0,0 +1,51 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/sentinel/tags"
+)
+
+func TestHandlerTags(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	handler := NewHandler(keeper)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+
+	res := handler(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.True(t, res.IsOK())
+	require.Equal(t, sdk.NewTags(
+		tags.Action, tags.ActionRegisterNode,
+		tags.Node, []byte(addrs[0].String()),
+		tags.Amount, []byte("100sut"),
+	), res.Tags)
+
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	res = handler(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], pks[1]))
+	require.True(t, res.IsOK())
+	require.Equal(t, sdk.NewTags(
+		tags.Action, tags.ActionPayVpnService,
+		tags.SessionId, res.Data,
+		tags.Node, []byte(addrs[0].String()),
+		tags.Client, []byte(addrs[1].String()),
+		tags.Amount, []byte("10sut"),
+	), res.Tags)
+
+	// the end block actions are tagged with the same keys
+	session, _ := keeper.GetSession(ctx, res.Data)
+	params := keeper.GetParams(ctx)
+	params.HeartbeatWindow = 0
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.SessionTimeout})
+	require.Equal(t, sdk.NewTags(
+		tags.Action, tags.ActionSettleSession,
+		tags.SessionId, res.Data,
+		tags.Client, []byte(addrs[1].String()),
+		tags.Node, []byte(addrs[0].String()),
+		tags.Released, []byte(session.ReleasedCoins.String()),
+		tags.Amount, []byte("10sut"),
+	), EndBlocker(ctx, keeper))
+}
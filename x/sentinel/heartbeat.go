@@ -0,0 +1,162 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// statuses reported by dVPN nodes in their heartbeats
+const (
+	NodeStatusOnline      = "online"      // accepting new sessions
+	NodeStatusBusy        = "busy"        // serving its sessions, not accepting new ones
+	NodeStatusMaintenance = "maintenance" // about to go down, not accepting new sessions
+)
+
+// highest load of a dVPN node, loads are percents of its capacity in use
+const MaxNodeLoad int64 = 100
+
+// NodeStatus - the liveness of a dVPN node, as of its latest heartbeat. A
+// node which misses heartbeats for the heartbeat window is inactive, and
+// hidden from the node listings, until its next heartbeat.
+type NodeStatus struct {
+	Node           sdk.AccAddress `json:"node"`            // address of the dVPN node
+	Status         string         `json:"status"`          // reported status, empty until the first heartbeat
+	ActiveSessions int64          `json:"active_sessions"` // reported number of sessions being served
+	Load           int64          `json:"load"`            // reported percent of the capacity in use
+	LastHeartbeat  int64          `json:"last_heartbeat"`  // time of the latest heartbeat, or of the registration
+	Active         bool           `json:"active"`
+}
+
+// check if a status may be reported in a heartbeat
+func isValidNodeStatus(status string) bool {
+	switch status {
+	case NodeStatusOnline, NodeStatusBusy, NodeStatusMaintenance:
+		return true
+	}
+	return false
+}
+
+// get the liveness status of a dVPN node
+func (keeper Keeper) GetNodeStatus(ctx sdk.Context, addr sdk.AccAddress) (status NodeStatus, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetNodeStatusKey(addr))
+	if bz == nil {
+		return status, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &status)
+	return status, true
+}
+
+// set the liveness status of a dVPN node, queued by its latest heartbeat
+// while it is active
+func (keeper Keeper) SetNodeStatus(ctx sdk.Context, status NodeStatus) {
+	keeper.deleteNodeStatus(ctx, status.Node)
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Set(GetNodeStatusKey(status.Node), keeper.cdc.MustMarshalBinary(status))
+	if status.Active {
+		store.Set(GetHeartbeatQueueKey(status.LastHeartbeat, status.Node), []byte{})
+	}
+}
+
+// delete the liveness status of a dVPN node along with its queue entry
+func (keeper Keeper) deleteNodeStatus(ctx sdk.Context, addr sdk.AccAddress) {
+	prev, found := keeper.GetNodeStatus(ctx, addr)
+	if !found {
+		return
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetNodeStatusKey(addr))
+	store.Delete(GetHeartbeatQueueKey(prev.LastHeartbeat, addr))
+}
+
+// check if a dVPN node is listed, nodes without a status are active
+func (keeper Keeper) IsNodeActive(ctx sdk.Context, addr sdk.AccAddress) bool {
+	status, found := keeper.GetNodeStatus(ctx, addr)
+	return !found || status.Active
+}
+
+// record the registration of a dVPN node as its first heartbeat, so that it
+// is active for the heartbeat window
+func (keeper Keeper) recordRegistrationHeartbeat(ctx sdk.Context, addr sdk.AccAddress) {
+	keeper.SetNodeStatus(ctx, NodeStatus{Node: addr, LastHeartbeat: ctx.BlockHeader().Time, Active: true})
+}
+
+// NodeHeartbeat records the status reported by a registered dVPN node and
+// reactivates it if it was inactive
+func (keeper Keeper) NodeHeartbeat(ctx sdk.Context, msg MsgNodeHeartbeat) (status NodeStatus, reactivated bool, err sdk.Error) {
+	if _, found := keeper.GetVpnService(ctx, msg.From); !found {
+		return status, false, ErrAccountAddressNotExist("Address is not registered as VPN node")
+	}
+	prev, found := keeper.GetNodeStatus(ctx, msg.From)
+	status = NodeStatus{
+		Node:           msg.From,
+		Status:         msg.Status,
+		ActiveSessions: msg.ActiveSessions,
+		Load:           msg.Load,
+		LastHeartbeat:  ctx.BlockHeader().Time,
+		Active:         true,
+	}
+	keeper.SetNodeStatus(ctx, status)
+	return status, found && !prev.Active, nil
+}
+
+// get the addresses of the active dVPN nodes with a latest heartbeat up to
+// cutoff
+func (keeper Keeper) getMissedHeartbeatNodes(ctx sdk.Context, cutoff int64) (addrs []sdk.AccAddress) {
+	if cutoff < 0 {
+		return nil
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := store.Iterator(HeartbeatQueueKey, GetHeartbeatQueueTimeKey(cutoff+1))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		addrs = append(addrs, GetAddressFromHeartbeatQueueKey(iterator.Key()))
+	}
+	return addrs
+}
+
+// DeactivateNode flags an active dVPN node which missed its heartbeats as
+// inactive
+func (keeper Keeper) DeactivateNode(ctx sdk.Context, addr sdk.AccAddress) sdk.Error {
+	status, found := keeper.GetNodeStatus(ctx, addr)
+	if !found || !status.Active {
+		return ErrInvalidHeartbeat("No active dVPN node found with the address")
+	}
+	status.Active = false
+	keeper.SetNodeStatus(ctx, status)
+	return nil
+}
+
+// iterate through the liveness statuses of the dVPN nodes, execute func for each
+func (keeper Keeper) IterateNodeStatuses(ctx sdk.Context, fn func(status NodeStatus) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, NodeStatusKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var status NodeStatus
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &status)
+		if fn(status) {
+			break
+		}
+	}
+}
+
+// attach the liveness statuses to a listing of dVPN nodes
+func (keeper Keeper) withStatuses(ctx sdk.Context, nodes []VpnNode) []VpnNode {
+	for i := range nodes {
+		if status, found := keeper.GetNodeStatus(ctx, nodes[i].Address); found {
+			nodes[i].Status = &status
+		}
+	}
+	return nodes
+}
+
+// get the active dVPN nodes, ordered by address
+func (keeper Keeper) GetActiveVpnServices(ctx sdk.Context) []VpnNode {
+	active := []VpnNode{}
+	for _, node := range keeper.withStatuses(ctx, keeper.GetVpnServices(ctx)) {
+		if node.Status == nil || node.Status.Active {
+			active = append(active, node)
+		}
+	}
+	return active
+}
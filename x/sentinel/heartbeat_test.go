@@ -0,0 +1,99 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestNodeHeartbeat(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	window := keeper.GetParams(ctx).HeartbeatWindow
+
+	// the registration counts as the first heartbeat
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+	status, found := keeper.GetNodeStatus(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, NodeStatus{Node: addrs[0], LastHeartbeat: 1000, Active: true}, status)
+	_, _, err = keeper.NodeHeartbeat(ctx, NewMsgNodeHeartbeat(addrs[1], NodeStatusOnline, 0, 0))
+	require.Equal(t, CodeAccountAddressNotExist, err.Code())
+
+	// a heartbeat within the window keeps the node active
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1500})
+	status, reactivated, err := keeper.NodeHeartbeat(ctx, NewMsgNodeHeartbeat(addrs[0], NodeStatusBusy, 12, 80))
+	require.Nil(t, err)
+	require.False(t, reactivated)
+	require.Equal(t, NodeStatus{addrs[0], NodeStatusBusy, 12, 80, 1500, true}, status)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + window})
+	require.Empty(t, EndBlocker(ctx, keeper))
+	require.Len(t, keeper.GetActiveVpnServices(ctx), 1)
+
+	// the node is hidden from the listings once it misses the window
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1500 + window})
+	require.NotEmpty(t, EndBlocker(ctx, keeper))
+	require.False(t, keeper.IsNodeActive(ctx, addrs[0]))
+	require.Empty(t, keeper.GetActiveVpnServices(ctx))
+	_, found = keeper.GetVpnService(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, CodeInvalidHeartbeat, keeper.DeactivateNode(ctx, addrs[0]).Code())
+
+	// and listed again on its next heartbeat
+	_, reactivated, err = keeper.NodeHeartbeat(ctx, NewMsgNodeHeartbeat(addrs[0], NodeStatusOnline, 0, 10))
+	require.Nil(t, err)
+	require.True(t, reactivated)
+	nodes := keeper.GetActiveVpnServices(ctx)
+	require.Len(t, nodes, 1)
+	require.Equal(t, int64(1500+window), nodes[0].Status.LastHeartbeat)
+
+	// nodes without a status are listed, the status is removed with the node
+	keeper.SetVpnService(ctx, addrs[1], newTestVpnNode())
+	require.Len(t, keeper.GetActiveVpnServices(ctx), 2)
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	_, found = keeper.GetNodeStatus(ctx, addrs[0])
+	require.False(t, found)
+	require.Empty(t, keeper.getMissedHeartbeatNodes(ctx, 2*(1500+window)))
+}
+
+func TestZeroHeartbeatWindow(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	params := keeper.GetParams(ctx)
+	params.HeartbeatWindow = 0
+	keeper.SetParams(ctx, params)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err := keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + DefaultParams().UnbondingTime})
+	require.Empty(t, EndBlocker(ctx, keeper))
+	require.True(t, keeper.IsNodeActive(ctx, addrs[0]))
+}
+
+func TestMsgNodeHeartbeatValidateBasic(t *testing.T) {
+	cases := []struct {
+		msg        MsgNodeHeartbeat
+		expectPass bool
+	}{
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusOnline, 0, 0), true},
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusBusy, 20, MaxNodeLoad), true},
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusMaintenance, 1, 50), true},
+		{NewMsgNodeHeartbeat(nil, NodeStatusOnline, 0, 0), false},
+		{NewMsgNodeHeartbeat(addrs[0], "", 0, 0), false},
+		{NewMsgNodeHeartbeat(addrs[0], "offline", 0, 0), false},
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusOnline, -1, 0), false},
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusOnline, 0, -1), false},
+		{NewMsgNodeHeartbeat(addrs[0], NodeStatusOnline, 0, MaxNodeLoad+1), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
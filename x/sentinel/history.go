@@ -0,0 +1,91 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// VpnChange - an update of a registered dVPN node
+type VpnChange struct {
+	Node     sdk.AccAddress       `json:"node"`     // address of the dVPN node
+	Sequence int64                `json:"sequence"` // position of the change in the history of the node
+	Height   int64                `json:"height"`   // block height of the change
+	Time     int64                `json:"time"`     // unix time of the change
+	Old      senttype.Registervpn `json:"old"`      // dVPN node before the change
+	New      senttype.Registervpn `json:"new"`      // dVPN node after the change
+}
+
+// check if the change updated the price of the node
+func (change VpnChange) IsPriceChange() bool {
+	return !change.Old.PricePerGb.IsEqual(change.New.PricePerGb)
+}
+
+// get the changes of a dVPN node, oldest first
+func (keeper Keeper) GetVpnChanges(ctx sdk.Context, addr sdk.AccAddress) (changes []VpnChange) {
+	keeper.iterateVpnChanges(ctx, GetVpnChangesKey(addr), func(change VpnChange) (stop bool) {
+		changes = append(changes, change)
+		return false
+	})
+	return changes
+}
+
+// set a change of a dVPN node
+func (keeper Keeper) SetVpnChange(ctx sdk.Context, change VpnChange) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(change)
+	store.Set(GetVpnChangeKey(change.Node, change.Sequence), bz)
+}
+
+// iterate through the changes of all the dVPN nodes, execute func for each
+func (keeper Keeper) IterateVpnChanges(ctx sdk.Context, fn func(change VpnChange) (stop bool)) {
+	keeper.iterateVpnChanges(ctx, VpnChangeKey, fn)
+}
+
+func (keeper Keeper) iterateVpnChanges(ctx sdk.Context, prefix []byte, fn func(change VpnChange) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var change VpnChange
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &change)
+		if fn(change) {
+			break
+		}
+	}
+}
+
+// record a change of a dVPN node at the end of its history
+func (keeper Keeper) appendVpnChange(ctx sdk.Context, addr sdk.AccAddress, oldVpn senttype.Registervpn, newVpn senttype.Registervpn) VpnChange {
+	change := VpnChange{
+		Node:   addr,
+		Height: ctx.BlockHeight(),
+		Time:   ctx.BlockHeader().Time,
+		Old:    oldVpn,
+		New:    newVpn,
+	}
+
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, GetVpnChangesKey(addr))
+	if iterator.Valid() {
+		change.Sequence = GetSequenceFromVpnChangeKey(iterator.Key()) + 1
+	}
+	iterator.Close()
+
+	keeper.SetVpnChange(ctx, change)
+	return change
+}
+
+// get the time of the last price change of a dVPN node
+func (keeper Keeper) lastPriceChangeTime(ctx sdk.Context, addr sdk.AccAddress) (time int64, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, GetVpnChangesKey(addr))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var change VpnChange
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &change)
+		if change.IsPriceChange() {
+			return change.Time, true
+		}
+	}
+	return 0, false
+}
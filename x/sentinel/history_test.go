@@ -0,0 +1,95 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestUpdateVpnService(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	interval := keeper.GetParams(ctx).PriceChangeInterval
+	price := func(amount int64) sdk.Coins { return sdk.Coins{{"sut", sdk.NewInt(amount)}} }
+
+	// only registered nodes may be updated
+	_, err := keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", newTestEndpoints("8.8.4.4"), 0, 0, nil, "", "", ""))
+	require.Equal(t, CodeAccountAddressNotExist, err.Code())
+	_, err = keeper.RegisterVpnService(ctx, newTestRegisterMsg(addrs[0], 100))
+	require.Nil(t, err)
+	registered, _ := keeper.GetVpnService(ctx, addrs[0])
+
+	// unset fields are left unchanged
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	change, err := keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", newTestEndpoints("8.8.4.4"), 0, 2000, nil, "", "", "0.0.2"))
+	require.Nil(t, err)
+	require.False(t, change.IsPriceChange())
+	vpn, _ := keeper.GetVpnService(ctx, addrs[0])
+	require.Equal(t, newTestEndpoints("8.8.4.4"), vpn.Endpoints)
+	require.Equal(t, registered.NetSpeed.UploadSpeed, vpn.NetSpeed.UploadSpeed)
+	require.Equal(t, int64(2000), vpn.NetSpeed.DownloadSpeed)
+	require.Equal(t, "0.0.2", vpn.Version)
+	require.Equal(t, registered.Moniker, vpn.Moniker)
+	require.Equal(t, registered.PricePerGb, vpn.PricePerGb)
+
+	// prices must be in the allowed denoms
+	_, err = keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, sdk.Coins{{"btc", sdk.NewInt(1)}}, "", "", ""))
+	require.Equal(t, CodeInvalidDenom, err.Code())
+
+	// the price may change once per interval
+	_, err = keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, price(20), "", "", ""))
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + interval - 1})
+	_, err = keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, price(30), "", "", ""))
+	require.Equal(t, CodePriceChangeInterval, err.Code())
+	_, err = keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, price(20), "AES-128-CBC", "", ""))
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + interval})
+	_, err = keeper.UpdateVpnService(ctx, NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, price(30), "", "", ""))
+	require.Nil(t, err)
+
+	changes := keeper.GetVpnChanges(ctx, addrs[0])
+	require.Len(t, changes, 4)
+	for i, change := range changes {
+		require.Equal(t, int64(i), change.Sequence)
+	}
+	require.Equal(t, registered, changes[0].Old)
+	require.Equal(t, price(10), changes[1].Old.PricePerGb)
+	require.Equal(t, price(20), changes[1].New.PricePerGb)
+	require.Equal(t, "AES-128-CBC", changes[2].New.EncMethod)
+	require.Equal(t, price(30), changes[3].New.PricePerGb)
+	require.Equal(t, 1000+interval, changes[3].Time)
+
+	// the history outlives the node
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	require.Len(t, keeper.GetVpnChanges(ctx, addrs[0]), 4)
+	require.Empty(t, keeper.GetVpnChanges(ctx, addrs[1]))
+}
+
+func TestMsgUpdateVpnServiceValidateBasic(t *testing.T) {
+	price := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	cases := []struct {
+		msg        MsgUpdateVpnService
+		expectPass bool
+	}{
+		{NewMsgUpdateVpnService(addrs[0], "node", nil, 0, 0, nil, "", "", ""), true},
+		{NewMsgUpdateVpnService(addrs[0], "", newTestEndpoints("8.8.8.8"), 1000, 0, price, "", "", ""), true},
+		{NewMsgUpdateVpnService(nil, "node", nil, 0, 0, nil, "", "", ""), false},
+		{NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, nil, "", "", ""), false},
+		{NewMsgUpdateVpnService(addrs[0], "", newTestEndpoints("127.0.0.1"), 0, 0, nil, "", "", ""), false},
+		{NewMsgUpdateVpnService(addrs[0], "", nil, -1, 0, nil, "", "", ""), false},
+		{NewMsgUpdateVpnService(addrs[0], "", nil, 0, 0, sdk.Coins{{"sut", sdk.NewInt(0)}}, "", "", ""), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
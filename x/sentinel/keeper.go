@@ -1,15 +1,15 @@
 package sentinel
 
 import (
-	"crypto/md5"
-	"encoding/hex"
-	"math"
-	"strconv"
+	"bytes"
+	"encoding/binary"
+	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
 	"github.com/tendermint/tendermint/crypto"
 )
@@ -20,6 +20,7 @@ type Keeper struct {
 	sentStoreKey sdk.StoreKey
 	coinKeeper   bank.Keeper
 	cdc          *wire.Codec
+	paramSpace   params.Subspace
 
 	codespace sdk.CodespaceType
 	account   auth.AccountMapper
@@ -33,10 +34,11 @@ type Sign struct {
 	sign    crypto.PubKey
 }
 
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, am auth.AccountMapper, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, am auth.AccountMapper, paramSpace params.Subspace, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
 		sentStoreKey: key,
 		cdc:          cdc,
+		paramSpace:   paramSpace,
 		coinKeeper:   ck,
 		codespace:    codespace,
 		account:      am,
@@ -45,192 +47,470 @@ func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, am auth.Accoun
 }
 
 func (keeper Keeper) RegisterVpnService(ctx sdk.Context, msg MsgRegisterVpnService) (sdk.AccAddress, sdk.Error) {
-	sentKey := msg.From
-	store := ctx.KVStore(keeper.sentStoreKey)
-	address := store.Get([]byte(sentKey))
-	if address == nil {
+	if _, found := keeper.GetVpnService(ctx, msg.From); !found {
 
 		if len(msg.Moniker) == 0 {
 			return nil, sdk.ErrInternal("Moniker for dVPN Node is required")
 		}
-		if len(msg.Moniker) > 128 {
-			return nil, sdk.ErrInternal("Node moniker length should not be greater than 128")
+		params := keeper.GetParams(ctx)
+		if maxLength := params.MaxMonikerLength; int64(len(msg.Moniker)) > maxLength {
+			return nil, sdk.ErrInternal(fmt.Sprintf("Node moniker length should not be greater than %d", maxLength))
+		}
+		for _, price := range msg.PricePerGb {
+			if !params.IsAllowedDenom(price.Denom) {
+				return nil, ErrInvalidDenom(fmt.Sprintf("Payments in %s are not allowed", price.Denom))
+			}
+		}
+		err := keeper.bondDeposit(ctx, msg.From, msg.Deposit)
+		if err != nil {
+			return nil, err
 		}
-		vpnreg := senttype.NewVpnRegister(msg.Moniker, msg.Ip, msg.NetSpeed.UploadSpeed, msg.NetSpeed.DownloadSpeed, msg.PricePerGb, msg.EncMethod, msg.Location.Latitude, msg.Location.Longitude, msg.Location.City, msg.Location.Country, msg.NodeType, msg.Version)
-		bz, _ := keeper.cdc.MarshalBinary(vpnreg)
-		store.Set([]byte(sentKey), bz)
+		vpnreg := senttype.NewVpnRegister(msg.Moniker, msg.Endpoints, msg.NetSpeed.UploadSpeed, msg.NetSpeed.DownloadSpeed, msg.PricePerGb, msg.EncMethod, msg.Location.Latitude, msg.Location.Longitude, msg.Location.City, msg.Location.Country, msg.NodeType, msg.Version)
+		keeper.SetVpnService(ctx, msg.From, vpnreg)
+		keeper.recordRegistrationHeartbeat(ctx, msg.From)
 		return msg.From, nil
 	}
 	return nil, ErrAccountAddressExist("Address already Registered as VPN node")
 }
 
+// UpdateVpnService edits the set fields of a registered dVPN node and records
+// the change in the history of the node. The price may only change once per
+// price change interval.
+func (keeper Keeper) UpdateVpnService(ctx sdk.Context, msg MsgUpdateVpnService) (VpnChange, sdk.Error) {
+	vpn, found := keeper.GetVpnService(ctx, msg.From)
+	if !found {
+		return VpnChange{}, ErrAccountAddressNotExist("Address is not registered as VPN node")
+	}
+
+	updated := vpn
+	if msg.Moniker != "" {
+		updated.Moniker = msg.Moniker
+	}
+	if len(msg.Endpoints) != 0 {
+		updated.Endpoints = msg.Endpoints
+	}
+	if msg.NetSpeed.UploadSpeed != 0 {
+		updated.NetSpeed.UploadSpeed = msg.NetSpeed.UploadSpeed
+	}
+	if msg.NetSpeed.DownloadSpeed != 0 {
+		updated.NetSpeed.DownloadSpeed = msg.NetSpeed.DownloadSpeed
+	}
+	if len(msg.PricePerGb) != 0 {
+		updated.PricePerGb = msg.PricePerGb
+	}
+	if msg.EncMethod != "" {
+		updated.EncMethod = msg.EncMethod
+	}
+	if msg.NodeType != "" {
+		updated.NodeType = msg.NodeType
+	}
+	if msg.Version != "" {
+		updated.Version = msg.Version
+	}
+
+	params := keeper.GetParams(ctx)
+	if maxLength := params.MaxMonikerLength; int64(len(updated.Moniker)) > maxLength {
+		return VpnChange{}, sdk.ErrInternal(fmt.Sprintf("Node moniker length should not be greater than %d", maxLength))
+	}
+	for _, price := range updated.PricePerGb {
+		if !params.IsAllowedDenom(price.Denom) {
+			return VpnChange{}, ErrInvalidDenom(fmt.Sprintf("Payments in %s are not allowed", price.Denom))
+		}
+	}
+	if !updated.PricePerGb.IsEqual(vpn.PricePerGb) {
+		last, found := keeper.lastPriceChangeTime(ctx, msg.From)
+		if found && ctx.BlockHeader().Time-last < params.PriceChangeInterval {
+			return VpnChange{}, ErrPriceChangeInterval(fmt.Sprintf("Price may only change once every %d seconds", params.PriceChangeInterval))
+		}
+	}
+
+	keeper.SetVpnService(ctx, msg.From, updated)
+	return keeper.appendVpnChange(ctx, msg.From, vpn, updated), nil
+}
+
+// RegisterMasterNode registers the sender as a master node, locking its bond,
+// which weights its votes on sentinel proposals
 func (keeper Keeper) RegisterMasterNode(ctx sdk.Context, msg MsgRegisterMasterNode) (sdk.AccAddress, sdk.Error) {
-	sentkey := msg.Address
-	store := ctx.KVStore(keeper.sentStoreKey)
-	address := store.Get([]byte(sentkey))
-	if address == nil {
-		address := msg.Address
-		bz, _ := keeper.cdc.MarshalBinary(address)
-		store.Set([]byte(msg.Address), bz)
-		return msg.Address, nil
+	if keeper.IsMasterNode(ctx, msg.Address) {
+		return nil, ErrAccountAddressExist("Address already registered as MasterNode")
+	}
+	err := keeper.bondMasterNode(ctx, msg.Address, msg.Bond)
+	if err != nil {
+		return nil, err
 	}
-	return nil, ErrAccountAddressExist("Address already registered as MasterNode")
+	keeper.SetMasterNode(ctx, msg.Address)
+	return msg.Address, nil
 }
 
 func (keeper Keeper) StoreKey() sdk.StoreKey {
 	return keeper.sentStoreKey
 }
 
-func (keeper Keeper) DeleteVpnService(ctx sdk.Context, msg MsgDeleteVpnUser) (sdk.AccAddress, sdk.Error) {
+// get a registered dVPN node
+func (keeper Keeper) GetVpnService(ctx sdk.Context, addr sdk.AccAddress) (vpn senttype.Registervpn, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetVpnServiceKey(addr))
+	if bz == nil {
+		return vpn, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &vpn)
+	return vpn, true
+}
 
+// set a registered dVPN node
+func (keeper Keeper) SetVpnService(ctx sdk.Context, addr sdk.AccAddress, vpn senttype.Registervpn) {
 	store := ctx.KVStore(keeper.sentStoreKey)
-	db := store.Get([]byte(msg.Vaddr))
-	if db == nil {
-		return nil, ErrAccountAddressNotExist("Account is not exist")
+	bz := keeper.cdc.MustMarshalBinary(vpn)
+	store.Set(GetVpnServiceKey(addr), bz)
+}
+
+// iterate through the registered dVPN nodes, execute func for each
+func (keeper Keeper) IterateVpnServices(ctx sdk.Context, fn func(addr sdk.AccAddress, vpn senttype.Registervpn) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, VpnServiceKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var vpn senttype.Registervpn
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &vpn)
+		if fn(GetAddressFromKey(iterator.Key()), vpn) {
+			break
+		}
 	}
-	store.Delete([]byte(msg.Vaddr))
-	return msg.Vaddr, nil
 }
-func (keeper Keeper) DeleteMasterNode(ctx sdk.Context, msg MsgDeleteMasterNode) (sdk.AccAddress, sdk.Error) {
+
+// check if an address is registered as a master node
+func (keeper Keeper) IsMasterNode(ctx sdk.Context, addr sdk.AccAddress) bool {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	return store.Has(GetMasterNodeKey(addr))
+}
+
+// register an address as a master node
+func (keeper Keeper) SetMasterNode(ctx sdk.Context, addr sdk.AccAddress) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(addr)
+	store.Set(GetMasterNodeKey(addr), bz)
+}
+
+// iterate through the master nodes, execute func for each
+func (keeper Keeper) IterateMasterNodes(ctx sdk.Context, fn func(addr sdk.AccAddress) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, MasterNodeKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		if fn(GetAddressFromKey(iterator.Key())) {
+			break
+		}
+	}
+}
+
+// get a session by id
+func (keeper Keeper) GetSession(ctx sdk.Context, sessionId []byte) (session senttype.Session, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetSessionKey(sessionId))
+	if bz == nil {
+		return session, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &session)
+	return session, true
+}
+
+// set a session along with its dVPN node and client indexes, and its entries
+// in the session queue and, once closing, the closing session queue
+func (keeper Keeper) SetSession(ctx sdk.Context, sessionId []byte, session senttype.Session) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(session)
+	store.Set(GetSessionKey(sessionId), bz)
+	store.Set(GetSessionsByNodeIndexKey(session.VpnAddress(), sessionId), []byte{})
+	store.Set(GetSessionsByClientIndexKey(session.CAddress, sessionId), []byte{})
+	store.Set(GetSessionQueueKey(session.Timestamp, sessionId), []byte{})
+	if session.Status == senttype.StatusClosing {
+		store.Set(GetSessionClosingQueueKey(session.ClosingTime, sessionId), []byte{})
+	}
+}
+
+// remove a session along with its indexes, queue entries and usage receipt
+func (keeper Keeper) DeleteSession(ctx sdk.Context, sessionId []byte) {
+	session, found := keeper.GetSession(ctx, sessionId)
+	if !found {
+		return
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetSessionKey(sessionId))
+	store.Delete(GetSessionsByNodeIndexKey(session.VpnAddress(), sessionId))
+	store.Delete(GetSessionsByClientIndexKey(session.CAddress, sessionId))
+	store.Delete(GetSessionQueueKey(session.Timestamp, sessionId))
+	store.Delete(GetSessionClosingQueueKey(session.ClosingTime, sessionId))
+	store.Delete(GetUsageReceiptKey(sessionId))
+}
+
+// iterate through the sessions, execute func for each
+func (keeper Keeper) IterateSessions(ctx sdk.Context, fn func(sessionId []byte, session senttype.Session) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, SessionKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var session senttype.Session
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &session)
+		if fn(GetSessionIdFromKey(iterator.Key()), session) {
+			break
+		}
+	}
+}
+
+// iterate through the sessions of a dVPN node, execute func for each
+func (keeper Keeper) IterateSessionsByNode(ctx sdk.Context, nodeAddr sdk.AccAddress, fn func(sessionId []byte, session senttype.Session) (stop bool)) {
+	keeper.iterateSessionIndex(ctx, GetSessionsByNodeKey(nodeAddr), fn)
+}
+
+// iterate through the sessions of a client, execute func for each
+func (keeper Keeper) IterateSessionsByClient(ctx sdk.Context, clientAddr sdk.AccAddress, fn func(sessionId []byte, session senttype.Session) (stop bool)) {
+	keeper.iterateSessionIndex(ctx, GetSessionsByClientKey(clientAddr), fn)
+}
+
+func (keeper Keeper) iterateSessionIndex(ctx sdk.Context, prefix []byte, fn func(sessionId []byte, session senttype.Session) (stop bool)) {
 	store := ctx.KVStore(keeper.sentStoreKey)
-	db := store.Get([]byte(msg.Maddr))
-	if db == nil {
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		sessionId := GetSessionIdFromIndexKey(iterator.Key())
+		session, found := keeper.GetSession(ctx, sessionId)
+		if !found {
+			panic("session index points to a missing session")
+		}
+		if fn(sessionId, session) {
+			break
+		}
+	}
+}
+
+// DeleteVpnService removes a dVPN node on the request of its owner. Master
+// nodes remove other dVPN nodes through node removal proposals, voted on with
+// the weight of their bonds.
+func (keeper Keeper) DeleteVpnService(ctx sdk.Context, msg MsgDeleteVpnUser) sdk.Error {
+
+	if _, found := keeper.GetVpnService(ctx, msg.Vaddr); !found {
+		return ErrAccountAddressNotExist("Account is not exist")
+	}
+	if !bytes.Equal(msg.From, msg.Vaddr) {
+		return ErrUnauthorizedNodeRemoval("Only the VPN node itself may delete it, master nodes remove VPN nodes through proposals")
+	}
+	keeper.removeVpnService(ctx, msg.Vaddr)
+	return nil
+}
+
+// remove a dVPN node along with its liveness status, and start unbonding its
+// deposit
+func (keeper Keeper) removeVpnService(ctx sdk.Context, addr sdk.AccAddress) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetVpnServiceKey(addr))
+	keeper.deleteNodeStatus(ctx, addr)
+	keeper.unbondDeposit(ctx, addr)
+}
+
+// DeleteMasterNode removes a master node on its own request, other master
+// nodes are removed through governance
+func (keeper Keeper) DeleteMasterNode(ctx sdk.Context, msg MsgDeleteMasterNode) (sdk.AccAddress, sdk.Error) {
+	if !keeper.IsMasterNode(ctx, msg.Maddr) {
 		return nil, ErrAccountAddressNotExist("Account is not exist")
 	}
-	store.Delete([]byte(msg.Maddr))
+	if !bytes.Equal(msg.Address, msg.Maddr) {
+		return nil, ErrUnauthorizedMasterRemoval("Only the master node itself or governance may remove a master node")
+	}
+	keeper.RemoveMasterNode(ctx, msg.Maddr)
 	return msg.Maddr, nil
 }
 
-func (keeper Keeper) PayVpnService(ctx sdk.Context, msg MsgPayVpnService) (string, sdk.Int, sdk.Error) {
+// RemoveMasterNode removes a master node without any authorization check, as
+// decided by governance, and starts unbonding its bond
+func (keeper Keeper) RemoveMasterNode(ctx sdk.Context, addr sdk.AccAddress) sdk.Error {
+	if !keeper.IsMasterNode(ctx, addr) {
+		return ErrAccountAddressNotExist("Account is not exist")
+	}
+	store := ctx.KVStore(keeper.sentStoreKey)
+	store.Delete(GetMasterNodeKey(addr))
+	keeper.unbondMasterNode(ctx, addr)
+	return nil
+}
 
-	var err error
-	hash := md5.New()
-	sequence, err := keeper.account.GetSequence(ctx, msg.From)
-	if err != nil {
-		return "", sdk.NewInt(0), sdk.ErrInvalidSequence("Invalid sequence")
+// get the count stored under key, zero if none
+func (keeper Keeper) getCount(ctx sdk.Context, key []byte) int64 {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(key)
+	if bz == nil {
+		return 0
 	}
-	addressbytes := []byte(msg.From.String() + "" + strconv.Itoa(int(sequence)))
-	hash.Write(addressbytes)
-	if err != nil {
-		return "", sdk.NewInt(0), ErrBech32Decode("address hash is failed")
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+func (keeper Keeper) setCount(ctx sdk.Context, key []byte, count int64) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(count))
+	store.Set(key, bz)
+}
+
+// get the number of sessions opened on the chain
+func (keeper Keeper) GetSessionCount(ctx sdk.Context) int64 {
+	return keeper.getCount(ctx, SessionCountKey)
+}
+
+// set the number of sessions opened on the chain
+func (keeper Keeper) SetSessionCount(ctx sdk.Context, count int64) {
+	keeper.setCount(ctx, SessionCountKey, count)
+}
+
+// get the id of a new session from the session count, skipping the ids of
+// sessions which are still open under an id of the legacy format
+func (keeper Keeper) newSessionId(ctx sdk.Context) []byte {
+	count := keeper.GetSessionCount(ctx)
+	for {
+		count++
+		sessionId := GetSessionId(count)
+		if _, found := keeper.GetSession(ctx, sessionId); !found {
+			keeper.SetSessionCount(ctx, count)
+			return sessionId
+		}
 	}
-	sentKey := hex.EncodeToString(hash.Sum(nil))[:20]
+}
+
+// PayVpnService locks the coins of a client for a new session on a dVPN node.
+// The session id is derived from the session count, so that each message of a
+// transaction opens a distinct session.
+func (keeper Keeper) PayVpnService(ctx sdk.Context, msg MsgPayVpnService) (string, sdk.Coins, sdk.Error) {
+
+	var err error
 	vpnpub, err := keeper.account.GetPubKey(ctx, msg.Vpnaddr)
 	if err != nil {
-		return "", sdk.NewInt(0), ErrInvalidPubKey("Vpn pubkey failed")
+		return "", nil, ErrInvalidPubKey("Vpn pubkey failed")
 	}
 	time := ctx.BlockHeader().Time
 	session := senttype.GetNewSessionMap(msg.Coins, vpnpub, msg.Pubkey, msg.From, time)
-	store := ctx.KVStore(keeper.sentStoreKey)
-	data := store.Get([]byte(msg.Vpnaddr))
-	if data == nil {
-		return "", sdk.NewInt(0), sdk.ErrUnknownAddress("VPN address is not registered")
+	vpn, found := keeper.GetVpnService(ctx, msg.Vpnaddr)
+	if !found {
+		return "", nil, sdk.ErrUnknownAddress("VPN address is not registered")
 	}
-	bz, err := keeper.cdc.MarshalBinary(session)
-	if err != nil {
-		return "", sdk.NewInt(0), ErrMarshal("Marshal of session struct is failed")
+	session.PricePerGb = vpn.PricePerGb
+
+	// every denom paid must be allowed and priced by the node
+	params := keeper.GetParams(ctx)
+	for _, coin := range msg.Coins {
+		if !params.IsAllowedDenom(coin.Denom) {
+			return "", nil, ErrInvalidDenom(fmt.Sprintf("Payments in %s are not allowed", coin.Denom))
+		}
+		if vpn.PricePerGb.AmountOf(coin.Denom).Sign() <= 0 {
+			return "", nil, ErrInvalidDenom(fmt.Sprintf("VPN node does not accept payments in %s", coin.Denom))
+		}
 	}
+
 	_, _, err = keeper.coinKeeper.SubtractCoins(ctx, msg.From, msg.Coins)
 	if err != nil {
-		return "", sdk.NewInt(0), sdk.ErrInsufficientCoins("Coins Parse failed or insufficient funds")
+		return "", nil, sdk.ErrInsufficientCoins("Coins Parse failed or insufficient funds")
 	}
-	store.Set([]byte(sentKey), bz)
-	return string(sentKey[:]), msg.Coins.AmountOf("sut"), nil
+	sessionId := keeper.newSessionId(ctx)
+	keeper.SetSession(ctx, sessionId, session)
+	return string(sessionId), msg.Coins, nil
 }
-func (keeper Keeper) RefundBal(ctx sdk.Context, msg MsgRefund) (sdk.AccAddress, sdk.Int, sdk.Error) {
 
-	var err error
-	var clientSession senttype.Session
-	store := ctx.KVStore(keeper.sentStoreKey)
-	x := store.Get([]byte(msg.Sessionid))
-	if x == nil {
-		return nil, sdk.NewInt(0), ErrInvalidSessionid("Invalid SessionId")
+// RefundBal starts closing a session on the request of its client, once the
+// refund timeout has passed since it was opened. The coins which were not
+// released are refunded when the session is settled, after the challenge
+// period in which the dVPN node may still claim its latest client signature.
+func (keeper Keeper) RefundBal(ctx sdk.Context, msg MsgRefund) (sdk.AccAddress, sdk.Coins, sdk.Error) {
+
+	clientSession, found := keeper.GetSession(ctx, msg.Sessionid)
+	if !found {
+		return nil, nil, ErrInvalidSessionid("Invalid SessionId")
+	}
+	if !bytes.Equal(msg.From, clientSession.CAddress) {
+		return nil, nil, sdk.ErrUnknownAddress("Address is not associated with this Session")
+	}
+	if clientSession.Status != senttype.StatusActive {
+		return nil, nil, ErrInvalidSessionid("Session is already closing or disputed")
+	}
+	if ctx.BlockHeader().Time-clientSession.Timestamp < keeper.GetParams(ctx).RefundTimeout {
+		return nil, nil, ErrTimeInterval("time is less than the refund timeout")
 	}
-	err = keeper.cdc.UnmarshalBinary(x, &clientSession)
+	_, err := keeper.closeSession(ctx, msg.Sessionid, clientSession, senttype.CloseReasonRefund)
 	if err != nil {
-		return nil, sdk.NewInt(0), ErrUnMarshal("UnMarshal of bytes failed")
+		return nil, nil, err
+	}
+	return msg.From, clientSession.RemainingCoins(), nil
+}
+
+// GetVpnPayment pays the dVPN node of a session the coins of a client
+// signature with a higher counter than the previous claim, less the coins
+// already released. The node may claim any number of times, a final signature
+// closes the session. Closing sessions still accept higher-counter claims
+// until they are settled at the end of the challenge period.
+func (keeper Keeper) GetVpnPayment(ctx sdk.Context, msg MsgGetVpnPayment) (paid sdk.Coins, session senttype.Session, err sdk.Error) {
 
+	session, found := keeper.GetSession(ctx, msg.Sessionid)
+	if !found {
+		return nil, session, ErrInvalidSessionid("Invalid session Id")
 	}
-	caddr := sdk.AccAddress(clientSession.CAddress)
-	if msg.From.String() != caddr.String() {
-		return nil, sdk.NewInt(0), sdk.ErrUnknownAddress("Address is not associated with this Session")
+	signBytes := senttype.ClientStdSignBytes(msg.Coins, []byte(msg.Sessionid), msg.Counter, msg.IsFinal)
+	if !session.CPubKey.VerifyBytes(signBytes, msg.Signature) {
+		return nil, session, sdk.ErrUnauthorized("signature verification failed")
 	}
-	ctime := ctx.BlockHeader().Time
-	if clientSession.Status == 0 {
-		_, _, err = keeper.coinKeeper.AddCoins(ctx, msg.From, clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins))
+	paid, session, err = keeper.releaseSessionCoins(ctx, msg.Sessionid, session, msg.Counter, msg.Coins)
+	if err != nil {
+		return nil, session, err
+	}
+
+	if msg.IsFinal && session.Status == senttype.StatusActive {
+		session, err = keeper.closeSession(ctx, msg.Sessionid, session, senttype.CloseReasonFinal)
 		if err != nil {
-			return nil, sdk.NewInt(0), sdk.ErrInsufficientCoins("Insufficient funds")
-		}
-		store.Delete(msg.Sessionid)
-		return msg.From, clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins).AmountOf("sut"), nil
-	}
-	if clientSession.Status == 1 {
-		time := int64(math.Abs(float64(ctime))) - clientSession.Timestamp
-		if time >= 86400 && clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins).IsPositive() && !clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins).IsZero() {
-			_, _, err = keeper.coinKeeper.AddCoins(ctx, msg.From, clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins))
-			if err != nil {
-				return nil, sdk.NewInt(0), sdk.ErrInsufficientCoins("Insufficient funds")
-			}
-			store.Delete(msg.Sessionid)
-			return msg.From, clientSession.TotalLockedCoins.Minus(clientSession.ReleasedCoins).AmountOf("sut"), nil
+			return nil, session, err
 		}
-		return nil, sdk.NewInt(0), ErrTimeInterval("time is less than 24 hours  or the balance is negative or equal to zero")
 	}
-	return nil, sdk.NewInt(0), ErrInvalidSessionid("Invalid SessionId")
-
+	return paid, session, nil
 }
-func (keeper Keeper) GetVpnPayment(ctx sdk.Context, msg MsgGetVpnPayment) ([]byte, sdk.AccAddress, sdk.Int, sdk.Error) {
-
-	var clientSession senttype.Session
 
-	store := ctx.KVStore(keeper.sentStoreKey)
-	x := store.Get([]byte(msg.Sessionid))
-	if x == nil {
-		return nil, nil, sdk.NewInt(0), ErrInvalidSessionid("Invalid session Id")
+// pay the dVPN node of a session the claimed coins of a higher counter than
+// the previous claim, less the coins already released, returning the paid coins
+func (keeper Keeper) releaseSessionCoins(ctx sdk.Context, sessionId []byte, session senttype.Session, counter int64, coins sdk.Coins) (paid sdk.Coins, updated senttype.Session, err sdk.Error) {
+	if session.Status == senttype.StatusDisputed {
+		return nil, session, ErrInvalidSessionid("Session is disputed")
 	}
-	err := keeper.cdc.UnmarshalBinary(x, &clientSession)
-	if err != nil {
-		return nil, nil, sdk.NewInt(0), ErrUnMarshal("Unmarshal of bytes failed")
+	if counter <= session.Counter {
+		return nil, session, ErrSignMsg("Invalid Counter")
+	}
+	if session.Status == senttype.StatusClosing && ctx.BlockHeader().Time-session.ClosingTime >= keeper.GetParams(ctx).ChallengePeriod {
+		return nil, session, ErrTimeInterval("Challenge period of the session is over")
+	}
+	if !coins.IsGTE(session.ReleasedCoins) || !session.TotalLockedCoins.IsGTE(coins) {
+		return nil, session, sdk.ErrInsufficientCoins("Claimed coins must be between the released and the locked coins")
 	}
-	ClientPubkey := clientSession.CPubKey
-	signBytes := senttype.ClientStdSignBytes(msg.Coins, []byte(msg.Sessionid), msg.Counter, msg.IsFinal)
-	if !ClientPubkey.VerifyBytes(signBytes, msg.Signature) {
-		return nil, nil, sdk.NewInt(0), sdk.ErrUnauthorized("signature verification failed")
-	}
-	clientSessionData := clientSession
-	if msg.Counter > clientSessionData.Counter {
-		clientSessionData.Counter = msg.Counter
-		CoinsToAdd := msg.Coins.Minus(clientSessionData.ReleasedCoins)
-		if !CoinsToAdd.IsZero() && (clientSessionData.TotalLockedCoins.Minus(clientSessionData.ReleasedCoins)).Minus(CoinsToAdd).IsPositive() && !CoinsToAdd.IsZero() {
-			clientSessionData.ReleasedCoins = msg.Coins
-			VpnAddr := sdk.AccAddress(clientSessionData.VpnPubKey.Address())
-			_, _, err = keeper.coinKeeper.AddCoins(ctx, VpnAddr, CoinsToAdd)
-			if err != nil {
-				return nil, nil, sdk.NewInt(0), sdk.ErrInsufficientCoins("Insufficient funds")
-			}
-			sentKey := []byte(msg.Sessionid)
-
-			if clientSessionData.TotalLockedCoins.Minus(clientSessionData.ReleasedCoins).IsZero() && !clientSessionData.TotalLockedCoins.Minus(clientSessionData.ReleasedCoins).IsPositive() || clientSessionData.Status == 0 {
-				store.Delete(sentKey)
-				return nil, nil, sdk.NewInt(0), sdk.ErrInsufficientCoins("Insufficient funds")
-			}
 
-			clientSessionData.Status = 0
-			bz, err := keeper.cdc.MarshalBinary(clientSessionData)
-			if err != nil {
-				return nil, nil, sdk.NewInt(0), ErrUnMarshal("Unmarshal of bytes failed")
-			}
-			store.Set(sentKey, bz)
-			clientAddr, _, err := keeper.RefundBal(ctx, MsgRefund{From: clientSessionData.CAddress, Sessionid: msg.Sessionid})
-			if err != nil {
-				return nil, nil, sdk.NewInt(0), sdk.ErrInternal("Refund failed")
-			}
-			return sentKey, clientAddr, clientSessionData.TotalLockedCoins.AmountOf("sut"), nil
+	paid = coins.Minus(session.ReleasedCoins)
+	if paid.IsPositive() {
+		_, _, err = keeper.coinKeeper.AddCoins(ctx, session.VpnAddress(), paid)
+		if err != nil {
+			return nil, session, err
 		}
-		return nil, nil, sdk.NewInt(0), sdk.ErrInsufficientCoins("Insufficient Coins ")
 	}
-	return nil, nil, sdk.NewInt(0), ErrSignMsg("Invalid Counter")
+	session.Counter = counter
+	session.ReleasedCoins = coins
+	keeper.SetSession(ctx, sessionId, session)
+	return paid, session, nil
 }
+
+// start the challenge period of a session closed for reason, returning the
+// closing session.
+// Sessions without coins left to claim are settled, and closed, right away.
+func (keeper Keeper) closeSession(ctx sdk.Context, sessionId []byte, session senttype.Session, reason uint8) (senttype.Session, sdk.Error) {
+	session.CloseReason = reason
+	if session.RemainingCoins().IsZero() {
+		_, err := keeper.SettleSession(ctx, sessionId, session)
+		session.Status = senttype.StatusClosed
+		return session, err
+	}
+	session.Status = senttype.StatusClosing
+	session.ClosingTime = ctx.BlockHeader().Time
+	keeper.SetSession(ctx, sessionId, session)
+	return session, nil
+}
+
 func (keeper Keeper) NewMsgDecoder(acc []byte) (senttype.Registervpn, sdk.Error) {
 
 	msg := senttype.Registervpn{}
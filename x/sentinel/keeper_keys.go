@@ -0,0 +1,352 @@
+package sentinel
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// Keys for store prefixes, 0x0A was the prefix of the removed master node
+	// votes to remove a dVPN node
+	StoreVersionKey          = []byte{0x00} // key for the version of the store layout
+	VpnServiceKey            = []byte{0x01} // prefix for each key to a registered dVPN node
+	MasterNodeKey            = []byte{0x02} // prefix for each key to a master node
+	SessionKey               = []byte{0x03} // prefix for each key to a session
+	SessionsByNodeIndexKey   = []byte{0x04} // prefix for each key to a session index, by dVPN node address
+	SessionsByClientIndexKey = []byte{0x05} // prefix for each key to a session index, by client address
+	SessionQueueKey          = []byte{0x06} // prefix for each key to a session index, by session timestamp
+	NodeDepositKey           = []byte{0x07} // prefix for each key to the bonded deposit of a dVPN node
+	UnbondingDepositKey      = []byte{0x08} // prefix for each key to the unbonding deposit of a deleted dVPN node
+	DepositQueueKey          = []byte{0x09} // prefix for each key to an unbonding deposit index, by completion time
+	VpnChangeKey             = []byte{0x0B} // prefix for each key to a change of a dVPN node, by sequence
+	SessionClosingQueueKey   = []byte{0x0C} // prefix for each key to a closing session index, by closing time
+	UsageReceiptKey          = []byte{0x0D} // prefix for each key to the latest usage receipt of a session
+	ReputationKey            = []byte{0x0E} // prefix for each key to the reputation of a dVPN node
+	NodeRatingKey            = []byte{0x0F} // prefix for each key to the rating of a dVPN node by a client with a settled session
+	DisputeKey               = []byte{0x10} // prefix for each key to the dispute of a session
+	DisputeQueueKey          = []byte{0x11} // prefix for each key to a dispute index, by deadline
+	SessionCountKey          = []byte{0x12} // key for the number of sessions opened, from which session ids are derived
+	PlanKey                  = []byte{0x13} // prefix for each key to a subscription plan
+	PlanCountKey             = []byte{0x14} // key for the number of plans published, from which plan ids are derived
+	SubscriptionKey          = []byte{0x15} // prefix for each key to a subscription
+	SubscriptionCountKey     = []byte{0x16} // key for the number of subscriptions bought, from which subscription ids are derived
+	SubscriptionQueueKey     = []byte{0x17} // prefix for each key to a subscription index, by end of the period
+	SubscriptionUsageKey     = []byte{0x18} // prefix for each key to the latest usage receipt of a dVPN node for a subscription
+	MasterBondKey            = []byte{0x19} // prefix for each key to the bond of a master node
+	ProposalKey              = []byte{0x1A} // prefix for each key to a sentinel proposal
+	ProposalCountKey         = []byte{0x1B} // key for the number of proposals submitted, from which proposal ids are derived
+	ProposalQueueKey         = []byte{0x1C} // prefix for each key to a proposal index, by end of the voting period
+	NodeStatusKey            = []byte{0x1D} // prefix for each key to the liveness status of a dVPN node
+	HeartbeatQueueKey        = []byte{0x1E} // prefix for each key to a dVPN node index, by time of its latest heartbeat
+	QuarantineKey            = []byte{0x1F} // prefix for each key to a legacy record the store migration could not decode
+)
+
+// current version of the store layout, see MigrateStore
+const StoreVersion int64 = 5
+
+// name of the software upgrade migrating the store to StoreVersion, which the
+// app registers an upgrade handler for
+const StoreUpgradeName = "sentinel-store-v5"
+
+// get the key for the dVPN node registered by address.
+// VALUE: sentinel/types.Registervpn
+func GetVpnServiceKey(addr sdk.AccAddress) []byte {
+	return append(VpnServiceKey, addr.Bytes()...)
+}
+
+// get the key for the master node with address.
+// VALUE: master node address (sdk.AccAddress)
+func GetMasterNodeKey(addr sdk.AccAddress) []byte {
+	return append(MasterNodeKey, addr.Bytes()...)
+}
+
+// get the id of the session opened as the count-th session of the chain: the
+// count in 20 decimal digits, so that ids are printable, ordered by opening and
+// of fixed length
+func GetSessionId(count int64) []byte {
+	return []byte(fmt.Sprintf("%020d", count))
+}
+
+// get the id of the count-th plan published on the chain, of the format of
+// session ids
+func GetPlanId(count int64) []byte {
+	return GetSessionId(count)
+}
+
+// get the id of the count-th subscription bought on the chain, of the format
+// of session ids
+func GetSubscriptionId(count int64) []byte {
+	return GetSessionId(count)
+}
+
+// get the id of the count-th proposal submitted on the chain, of the format of
+// session ids
+func GetProposalId(count int64) []byte {
+	return GetSessionId(count)
+}
+
+// get the key for the session with id.
+// VALUE: sentinel/types.Session
+func GetSessionKey(sessionId []byte) []byte {
+	return append(SessionKey, sessionId...)
+}
+
+// get the key for the session index of a dVPN node.
+// VALUE: none (key rearrangement with GetSessionIdFromIndexKey)
+func GetSessionsByNodeIndexKey(nodeAddr sdk.AccAddress, sessionId []byte) []byte {
+	return append(GetSessionsByNodeKey(nodeAddr), sessionId...)
+}
+
+// get the prefix for all the sessions of a dVPN node
+func GetSessionsByNodeKey(nodeAddr sdk.AccAddress) []byte {
+	return append(SessionsByNodeIndexKey, nodeAddr.Bytes()...)
+}
+
+// get the key for the session index of a client.
+// VALUE: none (key rearrangement with GetSessionIdFromIndexKey)
+func GetSessionsByClientIndexKey(clientAddr sdk.AccAddress, sessionId []byte) []byte {
+	return append(GetSessionsByClientKey(clientAddr), sessionId...)
+}
+
+// get the prefix for all the sessions of a client
+func GetSessionsByClientKey(clientAddr sdk.AccAddress) []byte {
+	return append(SessionsByClientIndexKey, clientAddr.Bytes()...)
+}
+
+// get the key for the session queue, ordered by the timestamp of the sessions.
+// VALUE: none (key rearrangement with GetSessionIdFromQueueKey)
+func GetSessionQueueKey(timestamp int64, sessionId []byte) []byte {
+	return append(GetSessionQueueTimeKey(timestamp), sessionId...)
+}
+
+// get the prefix for the sessions of the queue with timestamp
+func GetSessionQueueTimeKey(timestamp int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(timestamp))
+	return append(SessionQueueKey, bz...)
+}
+
+// get the key for the closing session queue, ordered by the closing time of
+// the sessions.
+// VALUE: none (key rearrangement with GetSessionFromQueueKey)
+func GetSessionClosingQueueKey(closingTime int64, sessionId []byte) []byte {
+	return append(GetSessionClosingQueueTimeKey(closingTime), sessionId...)
+}
+
+// get the prefix for the sessions of the closing queue with closing time
+func GetSessionClosingQueueTimeKey(closingTime int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(closingTime))
+	return append(SessionClosingQueueKey, bz...)
+}
+
+// get the session time and id from a SessionQueueKey, SessionClosingQueueKey
+// or DisputeQueueKey, the end and id of a subscription from a
+// SubscriptionQueueKey, or the end of the voting period and id of a proposal
+// from a ProposalQueueKey
+func GetSessionFromQueueKey(queueKey []byte) (timestamp int64, sessionId []byte) {
+	timestamp = int64(binary.BigEndian.Uint64(queueKey[1:9]))
+	return timestamp, queueKey[9:] // remove prefix and timestamp bytes
+}
+
+// get the key for the latest usage receipt of the session with id.
+// VALUE: sentinel/types.UsageReceipt
+func GetUsageReceiptKey(sessionId []byte) []byte {
+	return append(UsageReceiptKey, sessionId...)
+}
+
+// get the key for the reputation of the dVPN node with address.
+// VALUE: sentinel.Reputation
+func GetReputationKey(addr sdk.AccAddress) []byte {
+	return append(ReputationKey, addr.Bytes()...)
+}
+
+// get the key for the rating of a dVPN node by a client.
+// VALUE: sentinel.NodeRating
+func GetNodeRatingKey(nodeAddr sdk.AccAddress, clientAddr sdk.AccAddress) []byte {
+	return append(GetNodeRatingsKey(nodeAddr), clientAddr.Bytes()...)
+}
+
+// get the prefix for all the ratings of a dVPN node
+func GetNodeRatingsKey(nodeAddr sdk.AccAddress) []byte {
+	return append(NodeRatingKey, nodeAddr.Bytes()...)
+}
+
+// get the key for the dispute of the session with id.
+// VALUE: sentinel.Dispute
+func GetDisputeKey(sessionId []byte) []byte {
+	return append(DisputeKey, sessionId...)
+}
+
+// get the key for the dispute queue, ordered by the deadline of the disputes.
+// VALUE: none (key rearrangement with GetSessionFromQueueKey)
+func GetDisputeQueueKey(deadline int64, sessionId []byte) []byte {
+	return append(GetDisputeQueueTimeKey(deadline), sessionId...)
+}
+
+// get the prefix for the disputes of the queue with deadline
+func GetDisputeQueueTimeKey(deadline int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(deadline))
+	return append(DisputeQueueKey, bz...)
+}
+
+// get the key for the plan with id.
+// VALUE: sentinel.Plan
+func GetPlanKey(planId []byte) []byte {
+	return append(PlanKey, planId...)
+}
+
+// get the key for the subscription with id.
+// VALUE: sentinel.Subscription
+func GetSubscriptionKey(subscriptionId []byte) []byte {
+	return append(SubscriptionKey, subscriptionId...)
+}
+
+// get the key for the subscription queue, ordered by the end of the period of
+// the subscriptions.
+// VALUE: none (key rearrangement with GetSessionFromQueueKey)
+func GetSubscriptionQueueKey(end int64, subscriptionId []byte) []byte {
+	return append(GetSubscriptionQueueTimeKey(end), subscriptionId...)
+}
+
+// get the prefix for the subscriptions of the queue ending at end
+func GetSubscriptionQueueTimeKey(end int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(end))
+	return append(SubscriptionQueueKey, bz...)
+}
+
+// get the key for the latest usage receipt of a dVPN node for a subscription.
+// VALUE: sentinel/types.UsageReceipt
+func GetSubscriptionUsageKey(subscriptionId []byte, nodeAddr sdk.AccAddress) []byte {
+	return append(GetSubscriptionUsagesKey(subscriptionId), nodeAddr.Bytes()...)
+}
+
+// get the prefix for the usage receipts of all the dVPN nodes for a
+// subscription
+func GetSubscriptionUsagesKey(subscriptionId []byte) []byte {
+	return append(SubscriptionUsageKey, subscriptionId...)
+}
+
+// get the key for the bonded deposit of the dVPN node with address.
+// VALUE: sdk.Coins
+func GetNodeDepositKey(addr sdk.AccAddress) []byte {
+	return append(NodeDepositKey, addr.Bytes()...)
+}
+
+// get the key for the unbonding deposit of the deleted dVPN node with address.
+// VALUE: sentinel.UnbondingDeposit
+func GetUnbondingDepositKey(addr sdk.AccAddress) []byte {
+	return append(UnbondingDepositKey, addr.Bytes()...)
+}
+
+// get the key for the deposit queue, ordered by the completion time of the
+// unbonding deposits.
+// VALUE: none (key rearrangement with GetAddressFromDepositQueueKey)
+func GetDepositQueueKey(completionTime int64, addr sdk.AccAddress) []byte {
+	return append(GetDepositQueueTimeKey(completionTime), addr.Bytes()...)
+}
+
+// get the prefix for the unbonding deposits of the queue with completion time
+func GetDepositQueueTimeKey(completionTime int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(completionTime))
+	return append(DepositQueueKey, bz...)
+}
+
+// get the dVPN node address from a DepositQueueKey
+func GetAddressFromDepositQueueKey(queueKey []byte) sdk.AccAddress {
+	return sdk.AccAddress(queueKey[9:]) // remove prefix and completion time bytes
+}
+
+// get the key for a change of the dVPN node with address, ordered by sequence.
+// VALUE: sentinel.VpnChange
+func GetVpnChangeKey(addr sdk.AccAddress, sequence int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(sequence))
+	return append(GetVpnChangesKey(addr), bz...)
+}
+
+// get the prefix for all the changes of a dVPN node
+func GetVpnChangesKey(addr sdk.AccAddress) []byte {
+	return append(VpnChangeKey, addr.Bytes()...)
+}
+
+// get the sequence from a VpnChangeKey
+func GetSequenceFromVpnChangeKey(changeKey []byte) int64 {
+	return int64(binary.BigEndian.Uint64(changeKey[1+sdk.AddrLen:])) // remove prefix and address bytes
+}
+
+// get the session id from a SessionsByNodeIndexKey or SessionsByClientIndexKey
+func GetSessionIdFromIndexKey(indexKey []byte) []byte {
+	return indexKey[1+sdk.AddrLen:] // remove prefix and address bytes
+}
+
+// get the address from a dVPN node, master node, master bond or deposit key
+func GetAddressFromKey(key []byte) sdk.AccAddress {
+	return sdk.AccAddress(key[1:]) // remove prefix bytes
+}
+
+// get the session id from a session key
+func GetSessionIdFromKey(key []byte) []byte {
+	return key[1:] // remove prefix bytes
+}
+
+// get the key for the bond of the master node with address.
+// VALUE: sdk.Coin
+func GetMasterBondKey(addr sdk.AccAddress) []byte {
+	return append(MasterBondKey, addr.Bytes()...)
+}
+
+// get the key for the proposal with id.
+// VALUE: sentinel.Proposal
+func GetProposalKey(proposalId []byte) []byte {
+	return append(ProposalKey, proposalId...)
+}
+
+// get the key for the proposal queue, ordered by the end of the voting period.
+// VALUE: none (key rearrangement with GetSessionFromQueueKey)
+func GetProposalQueueKey(votingEnd int64, proposalId []byte) []byte {
+	return append(GetProposalQueueTimeKey(votingEnd), proposalId...)
+}
+
+// get the prefix for the proposals of the queue with end of the voting period
+func GetProposalQueueTimeKey(votingEnd int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(votingEnd))
+	return append(ProposalQueueKey, bz...)
+}
+
+// get the key for the liveness status of the dVPN node with address.
+// VALUE: sentinel.NodeStatus
+func GetNodeStatusKey(addr sdk.AccAddress) []byte {
+	return append(NodeStatusKey, addr.Bytes()...)
+}
+
+// get the key for the heartbeat queue, ordered by the time of the latest
+// heartbeat of the active dVPN nodes.
+// VALUE: none (key rearrangement with GetAddressFromHeartbeatQueueKey)
+func GetHeartbeatQueueKey(heartbeat int64, addr sdk.AccAddress) []byte {
+	return append(GetHeartbeatQueueTimeKey(heartbeat), addr.Bytes()...)
+}
+
+// get the prefix for the dVPN nodes of the queue with latest heartbeat time
+func GetHeartbeatQueueTimeKey(heartbeat int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(heartbeat))
+	return append(HeartbeatQueueKey, bz...)
+}
+
+// get the dVPN node address from a HeartbeatQueueKey
+func GetAddressFromHeartbeatQueueKey(queueKey []byte) sdk.AccAddress {
+	return sdk.AccAddress(queueKey[9:]) // remove prefix and heartbeat time bytes
+}
+
+// get the key for a legacy record the store migration could not decode
+// VALUE: the legacy record
+func GetQuarantineKey(legacyKey []byte) []byte {
+	return append(QuarantineKey, legacyKey...)
+}
@@ -0,0 +1,340 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestMasterNodeAndVpnServiceKeyspaces(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	_, err := keeper.RegisterMasterNode(ctx, NewMsgRegisterMasterNode(addrs[0], sdk.NewCoin("sut", 100)))
+	require.Nil(t, err)
+
+	// a master node may also register as a dVPN node
+	msg := NewMsgRegisterVpnService("node", addrs[0], newTestEndpoints("8.8.8.8"), 1000, 1000, sdk.Coins{{"sut", sdk.NewInt(10)}}, "AES-256-CBC",
+		174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1", sdk.Coins{{"sut", sdk.NewInt(100)}})
+	_, err = keeper.RegisterVpnService(ctx, msg)
+	require.Nil(t, err)
+	require.True(t, keeper.IsMasterNode(ctx, addrs[0]))
+	_, found := keeper.GetVpnService(ctx, addrs[0])
+	require.True(t, found)
+}
+
+func TestPaymentDenoms(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	// nodes may only be priced in the allowed denoms
+	msg := NewMsgRegisterVpnService("node", addrs[0], newTestEndpoints("8.8.8.8"), 1000, 1000, sdk.Coins{{"btc", sdk.NewInt(1)}}, "AES-256-CBC",
+		174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1", sdk.Coins{{"sut", sdk.NewInt(100)}})
+	_, err := keeper.RegisterVpnService(ctx, msg)
+	require.Equal(t, CodeInvalidDenom, err.Code())
+
+	params := keeper.GetParams(ctx)
+	params.AllowedDenoms = []string{"btc", "sut"}
+	keeper.SetParams(ctx, params)
+	_, err = keeper.RegisterVpnService(ctx, msg)
+	require.Nil(t, err)
+
+	// sessions may only be paid in the denoms priced by the node
+	_, _, err = keeper.PayVpnService(ctx, NewMsgPayVpnService(sdk.Coins{{"sut", sdk.NewInt(10)}}, addrs[0], addrs[1], pks[1]))
+	require.Equal(t, CodeInvalidDenom, err.Code())
+	_, _, err = keeper.PayVpnService(ctx, NewMsgPayVpnService(sdk.Coins{{"btc", sdk.NewInt(10)}}, addrs[0], addrs[1], pks[1]))
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+}
+
+func TestSessionIndexes(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	keeper.SetSession(ctx, []byte("session1"), senttype.GetNewSessionMap(coins, pks[0], pks[1], addrs[1], 0))
+	keeper.SetSession(ctx, []byte("session2"), senttype.GetNewSessionMap(coins, pks[0], pks[2], addrs[2], 0))
+	keeper.SetSession(ctx, []byte("session3"), senttype.GetNewSessionMap(coins, pks[1], pks[2], addrs[2], 0))
+
+	collect := func(iterate func(sdk.Context, sdk.AccAddress, func([]byte, senttype.Session) bool), addr sdk.AccAddress) (ids []string) {
+		iterate(ctx, addr, func(sessionId []byte, _ senttype.Session) bool {
+			ids = append(ids, string(sessionId))
+			return false
+		})
+		return
+	}
+	require.Equal(t, []string{"session1", "session2"}, collect(keeper.IterateSessionsByNode, addrs[0]))
+	require.Equal(t, []string{"session3"}, collect(keeper.IterateSessionsByNode, addrs[1]))
+	require.Equal(t, []string{"session2", "session3"}, collect(keeper.IterateSessionsByClient, addrs[2]))
+
+	keeper.DeleteSession(ctx, []byte("session2"))
+	require.Equal(t, []string{"session1"}, collect(keeper.IterateSessionsByNode, addrs[0]))
+	require.Equal(t, []string{"session3"}, collect(keeper.IterateSessionsByClient, addrs[2]))
+}
+
+func TestSessionIds(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	msg := NewMsgPayVpnService(sdk.Coins{{"sut", sdk.NewInt(10)}}, addrs[0], addrs[1], pks[1])
+
+	// the messages of a transaction open distinct sessions, whose ids are
+	// returned in the result data
+	handler := NewHandler(keeper)
+	res := handler(ctx, msg)
+	require.True(t, res.IsOK())
+	require.Equal(t, GetSessionId(1), res.Data)
+	res = handler(ctx, msg)
+	require.True(t, res.IsOK())
+	require.Equal(t, GetSessionId(2), res.Data)
+	require.Equal(t, []byte("00000000000000000002"), res.Data)
+	require.Equal(t, int64(2), keeper.GetSessionCount(ctx))
+
+	// ids of open sessions are not reused
+	keeper.SetSession(ctx, GetSessionId(3), senttype.GetNewSessionMap(msg.Coins, pks[0], pks[1], addrs[1], 0))
+	id, _, err := keeper.PayVpnService(ctx, msg)
+	require.Nil(t, err)
+	require.Equal(t, string(GetSessionId(4)), id)
+	require.Equal(t, int64(4), keeper.GetSessionCount(ctx))
+}
+
+func TestMigrateStore(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	require.Equal(t, int64(0), keeper.GetStoreVersion(ctx))
+
+	// write records the way the legacy layout did
+	store := ctx.KVStore(keeper.StoreKey())
+	vpn := newTestVpnNode()
+	legacyVpn := legacyRegistervpn{vpn.Moniker, "8.8.8.8", vpn.NetSpeed, 10, vpn.EncMethod, vpn.Location, vpn.NodeType, vpn.Version}
+	store.Set(addrs[0], keeper.cdc.MustMarshalBinary(legacyVpn))
+	store.Set(addrs[2], keeper.cdc.MustMarshalBinary(addrs[2]))
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	session := senttype.GetNewSessionMap(coins, pks[0], pks[1], addrs[1], 0)
+	sessionId := []byte("0123456789abcdef0123")
+	store.Set(sessionId, keeper.cdc.MustMarshalBinary(session))
+
+	// records are told apart by their value, not by the length or the bytes of the key
+	hexAddr := sdk.AccAddress([]byte("abcdef0123456789abcd"))
+	store.Set(hexAddr, keeper.cdc.MustMarshalBinary(legacyVpn))
+	longSessionId := []byte("0123456789abcdef0123456789abcdef")
+	store.Set(longSessionId, keeper.cdc.MustMarshalBinary(session))
+
+	// malformed records are quarantined
+	store.Set([]byte("malformed"), []byte{0xff, 0xff})
+
+	MigrateStore(ctx, keeper)
+	require.Equal(t, StoreVersion, keeper.GetStoreVersion(ctx))
+	require.Nil(t, store.Get(addrs[0]))
+	require.Nil(t, store.Get(addrs[2]))
+	require.Nil(t, store.Get(sessionId))
+	require.Nil(t, store.Get(hexAddr))
+	require.Nil(t, store.Get(longSessionId))
+	require.Nil(t, store.Get([]byte("malformed")))
+	require.Equal(t, []byte{0xff, 0xff}, store.Get(GetQuarantineKey([]byte("malformed"))))
+
+	resVpn, found := keeper.GetVpnService(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, vpn, resVpn)
+	_, found = keeper.GetVpnService(ctx, hexAddr)
+	require.True(t, found)
+	_, found = keeper.GetSession(ctx, longSessionId)
+	require.True(t, found)
+	require.True(t, keeper.IsMasterNode(ctx, addrs[2]))
+	require.False(t, keeper.IsMasterNode(ctx, addrs[0]))
+	resSession, found := keeper.GetSession(ctx, sessionId)
+	require.True(t, found)
+	require.Equal(t, session.CAddress, resSession.CAddress)
+
+	var ids [][]byte
+	keeper.IterateSessionsByClient(ctx, addrs[1], func(id []byte, _ senttype.Session) bool {
+		ids = append(ids, id)
+		return false
+	})
+	require.Equal(t, [][]byte{sessionId, longSessionId}, ids)
+	require.Equal(t, [][]byte{sessionId, longSessionId}, keeper.getExpiredSessionIds(ctx, 0))
+}
+
+func TestMigrateVpnEndpoints(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	keeper.setStoreVersion(ctx, 3)
+
+	// write a dVPN node and a change with a single IP address
+	store := ctx.KVStore(keeper.StoreKey())
+	vpn := newTestVpnNode()
+	ipVpn := ipRegistervpn{vpn.Moniker, "8.8.8.8", vpn.NetSpeed, vpn.PricePerGb, vpn.EncMethod, vpn.Location, vpn.NodeType, vpn.Version}
+	store.Set(GetVpnServiceKey(addrs[0]), keeper.cdc.MustMarshalBinary(ipVpn))
+	change := ipVpnChange{addrs[0], 0, 10, 1000, ipVpn, ipVpn}
+	change.Old.Ip = "8.8.4.4"
+	store.Set(GetVpnChangeKey(addrs[0], 0), keeper.cdc.MustMarshalBinary(change))
+
+	MigrateStore(ctx, keeper)
+	require.Equal(t, StoreVersion, keeper.GetStoreVersion(ctx))
+	resVpn, found := keeper.GetVpnService(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, vpn, resVpn)
+	changes := keeper.GetVpnChanges(ctx, addrs[0])
+	require.Len(t, changes, 1)
+	require.Equal(t, newTestEndpoints("8.8.4.4"), changes[0].Old.Endpoints)
+	require.Equal(t, vpn, changes[0].New)
+	require.Equal(t, int64(1000), changes[0].Time)
+
+	// the nodes are given the migration time as their latest heartbeat
+	status, found := keeper.GetNodeStatus(ctx, addrs[0])
+	require.True(t, found)
+	require.Equal(t, NodeStatus{Node: addrs[0], LastHeartbeat: ctx.BlockHeader().Time, Active: true}, status)
+}
+
+func TestDeleteVpnServiceAuthorization(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+
+	// other accounts, master nodes included, may not delete the node
+	err := keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[1], addrs[0]))
+	require.Equal(t, CodeUnauthorizedNodeRemoval, err.Code())
+	keeper.SetMasterNode(ctx, addrs[1])
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[1], addrs[0]))
+	require.Equal(t, CodeUnauthorizedNodeRemoval, err.Code())
+	_, found := keeper.GetVpnService(ctx, addrs[0])
+	require.True(t, found)
+
+	// owners delete their own node
+	require.Nil(t, keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0])))
+	_, found = keeper.GetVpnService(ctx, addrs[0])
+	require.False(t, found)
+	err = keeper.DeleteVpnService(ctx, NewMsgDeleteVpnUser(addrs[0], addrs[0]))
+	require.Equal(t, CodeAccountAddressNotExist, err.Code())
+}
+
+func TestDeleteMasterNodeAuthorization(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	keeper.SetMasterNode(ctx, addrs[0])
+	keeper.SetMasterNode(ctx, addrs[1])
+
+	// master nodes may only remove themselves
+	_, err := keeper.DeleteMasterNode(ctx, NewMsgDeleteMasterNode(addrs[1], addrs[0]))
+	require.Equal(t, CodeUnauthorizedMasterRemoval, err.Code())
+	_, err = keeper.DeleteMasterNode(ctx, NewMsgDeleteMasterNode(addrs[2], addrs[0]))
+	require.Equal(t, CodeUnauthorizedMasterRemoval, err.Code())
+	require.True(t, keeper.IsMasterNode(ctx, addrs[0]))
+
+	_, err = keeper.DeleteMasterNode(ctx, NewMsgDeleteMasterNode(addrs[0], addrs[0]))
+	require.Nil(t, err)
+	require.False(t, keeper.IsMasterNode(ctx, addrs[0]))
+	_, err = keeper.DeleteMasterNode(ctx, NewMsgDeleteMasterNode(addrs[0], addrs[0]))
+	require.Equal(t, CodeAccountAddressNotExist, err.Code())
+}
+
+func TestVpnPaymentChannel(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	challengePeriod := keeper.GetParams(ctx).ChallengePeriod
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	clientKey := ed25519.GenPrivKey()
+	coins := func(amount int64) sdk.Coins { return sdk.Coins{{"sut", sdk.NewInt(amount)}} }
+	balance := func(addr sdk.AccAddress) int64 { return ck.GetCoins(ctx, addr).AmountOf("sut").Int64() }
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins(100), addrs[0], addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sessionId := []byte(id)
+	claim := func(amount int64, counter int64, isFinal bool) (sdk.Coins, sdk.Error) {
+		sig, err := clientKey.Sign(senttype.ClientStdSignBytes(coins(amount), sessionId, counter, isFinal))
+		require.Nil(t, err)
+		paid, _, sdkErr := keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins(amount), sessionId, counter, addrs[0], sig, isFinal))
+		return paid, sdkErr
+	}
+
+	// the node claims incrementally with increasing counters
+	paid, err := claim(30, 1, false)
+	require.Nil(t, err)
+	require.Equal(t, coins(30), paid)
+	paid, err = claim(60, 2, false)
+	require.Nil(t, err)
+	require.Equal(t, coins(30), paid)
+	require.Equal(t, initCoins.Int64()+60, balance(addrs[0]))
+
+	_, err = claim(70, 2, false)
+	require.Equal(t, CodeSignMsg, err.Code())
+	_, err = claim(50, 3, false)
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+	_, err = claim(150, 3, false)
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+	sig, _ := clientKey.Sign(senttype.ClientStdSignBytes(coins(70), sessionId, 3, false))
+	_, _, err = keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins(80), sessionId, 3, addrs[0], sig, false))
+	require.Equal(t, sdk.CodeUnauthorized, err.Code())
+
+	// a final claim closes the session, higher counters are still accepted
+	// during the challenge period
+	paid, err = claim(70, 3, true)
+	require.Nil(t, err)
+	require.Equal(t, coins(10), paid)
+	session, _ := keeper.GetSession(ctx, sessionId)
+	require.Equal(t, senttype.StatusClosing, session.Status)
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.Equal(t, CodeInvalidSessionid, err.Code())
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + challengePeriod - 1})
+	paid, err = claim(80, 4, false)
+	require.Nil(t, err)
+	require.Equal(t, coins(10), paid)
+	require.Empty(t, EndBlocker(ctx, keeper))
+	_, found := keeper.GetSession(ctx, sessionId)
+	require.True(t, found)
+
+	// the remainder is refunded once the challenge period is over
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + challengePeriod})
+	_, err = claim(90, 5, false)
+	require.Equal(t, CodeTimeInterval, err.Code())
+	require.NotEmpty(t, EndBlocker(ctx, keeper))
+	_, found = keeper.GetSession(ctx, sessionId)
+	require.False(t, found)
+	require.Equal(t, initCoins.Int64()+80, balance(addrs[0]))
+	require.Equal(t, initCoins.Int64()-80, balance(addrs[1]))
+	require.Empty(t, keeper.getChallengedSessionIds(ctx, ctx.BlockHeader().Time))
+}
+
+func TestRefundClosesSession(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	params := keeper.GetParams(ctx)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	clientKey := ed25519.GenPrivKey()
+	coins := sdk.Coins{{"sut", sdk.NewInt(100)}}
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sessionId := []byte(id)
+
+	// only the client may close the session, once the refund timeout has passed
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout - 1})
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.Equal(t, CodeTimeInterval, err.Code())
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout})
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[2], sessionId))
+	require.NotNil(t, err)
+	_, refund, err := keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.Nil(t, err)
+	require.Equal(t, coins, refund)
+	require.Equal(t, initCoins.Int64()-100, ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64())
+
+	// the node may still claim its latest signature during the challenge period
+	sig, signErr := clientKey.Sign(senttype.ClientStdSignBytes(sdk.Coins{{"sut", sdk.NewInt(40)}}, sessionId, 1, false))
+	require.Nil(t, signErr)
+	_, _, err = keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(sdk.Coins{{"sut", sdk.NewInt(40)}}, sessionId, 1, addrs[0], sig, false))
+	require.Nil(t, err)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout + params.ChallengePeriod})
+	EndBlocker(ctx, keeper)
+	require.Equal(t, initCoins.Int64()-40, ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64())
+	require.Equal(t, initCoins.Int64()+40, ck.GetCoins(ctx, addrs[0]).AmountOf("sut").Int64())
+
+	// a session without coins left is settled right away
+	id, _, err = keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sig, signErr = clientKey.Sign(senttype.ClientStdSignBytes(coins, []byte(id), 1, true))
+	require.Nil(t, signErr)
+	_, session, err := keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins, []byte(id), 1, addrs[0], sig, true))
+	require.Nil(t, err)
+	require.Equal(t, senttype.StatusClosed, session.Status)
+	_, found := keeper.GetSession(ctx, []byte(id))
+	require.False(t, found)
+}
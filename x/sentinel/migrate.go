@@ -0,0 +1,239 @@
+package sentinel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// denom of the dVPN node prices stored before version 3
+const legacyPriceDenom = "sut"
+
+// port and protocol of the endpoint of the dVPN nodes stored before version
+// 4, the OpenVPN defaults
+const (
+	legacyEndpointPort     = 1194
+	legacyEndpointProtocol = "udp"
+)
+
+// dVPN node as stored before version 3, with a price in legacyPriceDenom
+type legacyRegistervpn struct {
+	Moniker    string
+	Ip         string
+	NetSpeed   senttype.NetSpeed
+	PricePerGb int64
+	EncMethod  string
+	Location   senttype.Location
+	NodeType   string
+	Version    string
+}
+
+// dVPN node as stored before version 4, with a single IP address
+type ipRegistervpn struct {
+	Moniker    string
+	Ip         string
+	NetSpeed   senttype.NetSpeed
+	PricePerGb sdk.Coins
+	EncMethod  string
+	Location   senttype.Location
+	NodeType   string
+	Version    string
+}
+
+// dVPN node change as stored before version 4
+type ipVpnChange struct {
+	Node     sdk.AccAddress
+	Sequence int64
+	Height   int64
+	Time     int64
+	Old      ipRegistervpn
+	New      ipRegistervpn
+}
+
+// get the version of the store layout, 0 if the store predates versioning
+func (keeper Keeper) GetStoreVersion(ctx sdk.Context) int64 {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(StoreVersionKey)
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+func (keeper Keeper) setStoreVersion(ctx sdk.Context, version int64) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(version))
+	store.Set(StoreVersionKey, bz)
+}
+
+// MigrateStore brings the store up to StoreVersion, it is a no-op once the
+// store is at StoreVersion. It is run by the upgrade handler of
+// StoreUpgradeName, registered by the app.
+//
+// Version 1 moves the records written by the legacy layout, where dVPN nodes
+// and master nodes were keyed by the raw address and sessions by their md5
+// based id, all in the same keyspace, under their prefixes and builds the
+// session indexes. Version 2 builds the session queue. Version 3 converts the
+// dVPN node prices to coins. Version 4 converts the IP address of the dVPN
+// nodes, and of their changes, to an endpoint list. Version 5 records a
+// heartbeat of every dVPN node at the time of the migration, so that the nodes
+// which do not send heartbeats are deactivated after the heartbeat window.
+func MigrateStore(ctx sdk.Context, keeper Keeper) {
+	version := keeper.GetStoreVersion(ctx)
+	if version >= StoreVersion {
+		return
+	}
+	if version < 1 {
+		migrateLegacyLayout(ctx, keeper)
+	}
+	if version < 2 {
+		migrateSessionQueue(ctx, keeper)
+	}
+	if version < 3 {
+		migrateVpnPrices(ctx, keeper)
+	}
+	if version < 4 {
+		migrateVpnEndpoints(ctx, keeper)
+	}
+	if version < 5 {
+		migrateNodeHeartbeats(ctx, keeper)
+	}
+	keeper.setStoreVersion(ctx, StoreVersion)
+}
+
+// the store predates versioning, so that it only holds records of the legacy
+// layout, told apart by their value rather than by their key. The records of
+// none of the legacy types are kept under QuarantineKey, by their legacy key.
+func migrateLegacyLayout(ctx sdk.Context, keeper Keeper) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	var legacyKeys, legacyValues [][]byte
+	iterator := store.Iterator(nil, nil)
+	for ; iterator.Valid(); iterator.Next() {
+		legacyKeys = append(legacyKeys, iterator.Key())
+		legacyValues = append(legacyValues, iterator.Value())
+	}
+	iterator.Close()
+
+	for i, key := range legacyKeys {
+		store.Delete(key)
+		value := legacyValues[i]
+
+		// master nodes stored their own address as the value
+		var masterAddr sdk.AccAddress
+		err := keeper.cdc.UnmarshalBinary(value, &masterAddr)
+		if err == nil && bytes.Equal(masterAddr, key) {
+			keeper.SetMasterNode(ctx, masterAddr)
+			continue
+		}
+
+		// sessions do not decode as dVPN nodes, and always have a client
+		var session senttype.Session
+		err = keeper.cdc.UnmarshalBinary(value, &session)
+		if err == nil && len(session.CAddress) == sdk.AddrLen {
+			keeper.SetSession(ctx, key, session)
+			continue
+		}
+
+		// dVPN nodes are converted along with the prefixed ones, see migrateVpnPrices
+		var node legacyRegistervpn
+		err = keeper.cdc.UnmarshalBinary(value, &node)
+		if err != nil {
+			ctx.Logger().With("module", "x/sentinel").Error(
+				fmt.Sprintf("quarantining the undecodable legacy record %X: %s", key, err.Error()))
+			store.Set(GetQuarantineKey(key), value)
+			continue
+		}
+		store.Set(GetVpnServiceKey(sdk.AccAddress(key)), value)
+	}
+}
+
+// setting the sessions again adds their queue entries
+func migrateSessionQueue(ctx sdk.Context, keeper Keeper) {
+	var ids [][]byte
+	var sessions []senttype.Session
+	keeper.IterateSessions(ctx, func(sessionId []byte, session senttype.Session) (stop bool) {
+		ids = append(ids, sessionId)
+		sessions = append(sessions, session)
+		return false
+	})
+	for i, sessionId := range ids {
+		keeper.SetSession(ctx, sessionId, sessions[i])
+	}
+}
+
+func migrateVpnPrices(ctx sdk.Context, keeper Keeper) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	var addrs []sdk.AccAddress
+	var nodes []legacyRegistervpn
+	iterator := sdk.KVStorePrefixIterator(store, VpnServiceKey)
+	for ; iterator.Valid(); iterator.Next() {
+		var node legacyRegistervpn
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &node)
+		addrs = append(addrs, GetAddressFromKey(iterator.Key()))
+		nodes = append(nodes, node)
+	}
+	iterator.Close()
+
+	// the endpoints are converted by migrateVpnEndpoints
+	for i, node := range nodes {
+		var price sdk.Coins
+		if node.PricePerGb > 0 {
+			price = sdk.Coins{sdk.NewCoin(legacyPriceDenom, node.PricePerGb)}
+		}
+		vpn := ipRegistervpn{node.Moniker, node.Ip, node.NetSpeed, price, node.EncMethod, node.Location, node.NodeType, node.Version}
+		store.Set(GetVpnServiceKey(addrs[i]), keeper.cdc.MustMarshalBinary(vpn))
+	}
+}
+
+func migrateVpnEndpoints(ctx sdk.Context, keeper Keeper) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	var addrs []sdk.AccAddress
+	var nodes []ipRegistervpn
+	iterator := sdk.KVStorePrefixIterator(store, VpnServiceKey)
+	for ; iterator.Valid(); iterator.Next() {
+		var node ipRegistervpn
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &node)
+		addrs = append(addrs, GetAddressFromKey(iterator.Key()))
+		nodes = append(nodes, node)
+	}
+	iterator.Close()
+
+	var changes []ipVpnChange
+	iterator = sdk.KVStorePrefixIterator(store, VpnChangeKey)
+	for ; iterator.Valid(); iterator.Next() {
+		var change ipVpnChange
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &change)
+		changes = append(changes, change)
+	}
+	iterator.Close()
+
+	for i, node := range nodes {
+		keeper.SetVpnService(ctx, addrs[i], node.withEndpoints())
+	}
+	for _, change := range changes {
+		keeper.SetVpnChange(ctx, VpnChange{change.Node, change.Sequence, change.Height, change.Time,
+			change.Old.withEndpoints(), change.New.withEndpoints()})
+	}
+}
+
+func migrateNodeHeartbeats(ctx sdk.Context, keeper Keeper) {
+	for _, node := range keeper.GetVpnServices(ctx) {
+		keeper.recordRegistrationHeartbeat(ctx, node.Address)
+	}
+}
+
+// convert the IP address of a dVPN node to an endpoint with the legacy port
+// and protocol
+func (node ipRegistervpn) withEndpoints() senttype.Registervpn {
+	var endpoints []senttype.Endpoint
+	if node.Ip != "" {
+		endpoints = []senttype.Endpoint{senttype.NewEndpoint(node.Ip, legacyEndpointPort, legacyEndpointProtocol)}
+	}
+	return senttype.NewVpnRegister(node.Moniker, endpoints, node.NetSpeed.UploadSpeed, node.NetSpeed.DownloadSpeed,
+		node.PricePerGb, node.EncMethod, node.Location.Latitude, node.Location.Longitude, node.Location.City,
+		node.Location.Country, node.NodeType, node.Version)
+}
@@ -2,6 +2,7 @@ package sentinel
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 	"reflect"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	//log "github.com/logger"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
 	"github.com/tendermint/tendermint/crypto"
 )
 
@@ -25,14 +27,16 @@ import (
 type MsgRegisterVpnService struct {
 	Moniker    string
 	From       sdk.AccAddress
-	Ip         string
+	Endpoints  []senttype.Endpoint
 	NetSpeed   NetSpeed
-	PricePerGb int64
+	PricePerGb sdk.Coins
 	EncMethod  string
 	Location   Location
 
 	NodeType string
 	Version  string
+
+	Deposit sdk.Coins
 }
 type NetSpeed struct {
 	UploadSpeed   int64
@@ -45,11 +49,11 @@ type Location struct {
 	Country   string
 }
 
-func NewMsgRegisterVpnService(moniker string, address sdk.AccAddress, ip string, upload int64, download int64, ppgb int64, method string, latitude int64, long int64, city string, country string, nodetype string, version string) MsgRegisterVpnService {
+func NewMsgRegisterVpnService(moniker string, address sdk.AccAddress, endpoints []senttype.Endpoint, upload int64, download int64, ppgb sdk.Coins, method string, latitude int64, long int64, city string, country string, nodetype string, version string, deposit sdk.Coins) MsgRegisterVpnService {
 	return MsgRegisterVpnService{
-		Moniker: moniker,
-		From:    address,
-		Ip:      ip,
+		Moniker:   moniker,
+		From:      address,
+		Endpoints: endpoints,
 		NetSpeed: NetSpeed{
 			UploadSpeed:   upload,
 			DownloadSpeed: download,
@@ -64,31 +68,98 @@ func NewMsgRegisterVpnService(moniker string, address sdk.AccAddress, ip string,
 		},
 		NodeType: nodetype,
 		Version:  version,
+		Deposit:  deposit,
 	}
 }
 
-func validateIp(host string) bool {
-	parts := strings.Split(host, ".")
+// max number of endpoints a dVPN node may publish
+const MaxEndpoints = 8
+
+// ranges which are not reachable from the public internet, on top of the
+// loopback, multicast, link-local and unspecified addresses
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"), // carrier-grade NAT
+	mustParseCIDR("fc00::/7"),      // unique local addresses
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// ValidateEndpoints checks that a dVPN node publishes at least one and at most
+// MaxEndpoints distinct, publicly reachable endpoints
+func ValidateEndpoints(endpoints []senttype.Endpoint) sdk.Error {
+	if len(endpoints) == 0 {
+		return ErrInvalidIpAdress("At least one endpoint is required")
+	}
+	if len(endpoints) > MaxEndpoints {
+		return ErrInvalidIpAdress(fmt.Sprintf("At most %d endpoints are allowed", MaxEndpoints))
+	}
+	seen := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		if endpoint.Protocol != "tcp" && endpoint.Protocol != "udp" {
+			return ErrInvalidIpAdress(fmt.Sprintf("Invalid protocol %s of endpoint %s, expected tcp or udp", endpoint.Protocol, endpoint))
+		}
+		if endpoint.Port <= 0 || endpoint.Port > 65535 {
+			return ErrInvalidIpAdress(fmt.Sprintf("Invalid port of endpoint %s", endpoint))
+		}
+		if !validateHost(endpoint.Host) {
+			return ErrInvalidIpAdress(fmt.Sprintf("Host of endpoint %s is not a public IP address or DNS name", endpoint))
+		}
+		if seen[endpoint.String()] {
+			return ErrInvalidIpAdress(fmt.Sprintf("Duplicate endpoint %s", endpoint))
+		}
+		seen[endpoint.String()] = true
+	}
+	return nil
+}
 
-	if len(parts) < 4 || len(parts) > 4 {
+// check if the host is a public IPv4 or IPv6 address, or a DNS name
+func validateHost(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return validateDNSName(host)
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() {
 		return false
 	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
 
-	for _, x := range parts {
-		if i, err := strconv.Atoi(x); err == nil {
-			if i < 0 || i > 255 {
+// check if the name is a fully qualified DNS name as of RFC 1123, without
+// the trailing dot
+func validateDNSName(name string) bool {
+	labels := strings.Split(strings.ToLower(name), ".")
+	if len(name) > 253 || len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
 				return false
 			}
-		} else {
-			return false
 		}
-
 	}
-	ip := net.ParseIP(host)
-	if ip.IsLoopback() || ip.IsMulticast() {
+	// a numeric top level domain is a malformed IPv4 address
+	if _, err := strconv.Atoi(labels[len(labels)-1]); err == nil {
 		return false
 	}
-	return true
+	return labels[len(labels)-1] != "localhost" && labels[len(labels)-1] != "local"
 }
 
 func (msc MsgRegisterVpnService) Type() string {
@@ -105,21 +176,22 @@ func (msc MsgRegisterVpnService) GetSignBytes() []byte {
 }
 func (msc MsgRegisterVpnService) ValidateBasic() sdk.Error {
 	var a int64
-	var s string
 	if msc.From == nil {
 		return sdk.ErrInvalidAddress("Invalid Address")
 	}
-	if reflect.TypeOf(msc.PricePerGb) != reflect.TypeOf(a) || msc.PricePerGb < 0 {
+	if len(msc.PricePerGb) == 0 || !msc.PricePerGb.IsValid() || !msc.PricePerGb.IsPositive() {
 
 		return ErrInvalidPricePerGb("Price per GB is not Valid")
 	}
-	if msc.Ip == "" || !validateIp(msc.Ip) || reflect.TypeOf(msc.Ip) != reflect.TypeOf(s) {
-
-		return ErrInvalidIpAdress("Invalid IP address")
+	if err := ValidateEndpoints(msc.Endpoints); err != nil {
+		return err
 	}
 	if reflect.TypeOf(msc.NetSpeed.UploadSpeed) != reflect.TypeOf(a) || reflect.TypeOf(msc.NetSpeed.DownloadSpeed) != reflect.TypeOf(a) || msc.NetSpeed.UploadSpeed <= 0 || msc.NetSpeed.DownloadSpeed <= 0 {
 		return ErrInvalidNetspeed("NetSpeed is not Valid")
 	}
+	if !msc.Deposit.IsValid() || !msc.Deposit.IsNotNegative() {
+		return ErrInvalidDeposit("Deposit is not Valid")
+	}
 	return nil
 }
 
@@ -127,13 +199,92 @@ func (msc MsgRegisterVpnService) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msc.From}
 }
 
+//
+//
+//
+//
+//
+// MsgUpdateVpnService edits a registered dVPN node in place. Empty or zero
+// fields are left unchanged, set endpoints replace all the endpoints.
+type MsgUpdateVpnService struct {
+	From       sdk.AccAddress
+	Moniker    string
+	Endpoints  []senttype.Endpoint
+	NetSpeed   NetSpeed
+	PricePerGb sdk.Coins
+	EncMethod  string
+	NodeType   string
+	Version    string
+}
+
+func NewMsgUpdateVpnService(address sdk.AccAddress, moniker string, endpoints []senttype.Endpoint, upload int64, download int64, ppgb sdk.Coins, method string, nodetype string, version string) MsgUpdateVpnService {
+	return MsgUpdateVpnService{
+		From:      address,
+		Moniker:   moniker,
+		Endpoints: endpoints,
+		NetSpeed: NetSpeed{
+			UploadSpeed:   upload,
+			DownloadSpeed: download,
+		},
+		PricePerGb: ppgb,
+		EncMethod:  method,
+		NodeType:   nodetype,
+		Version:    version,
+	}
+}
+
+func (msc MsgUpdateVpnService) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgUpdateVpnService) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+// check if no field of the update is set
+func (msc MsgUpdateVpnService) IsEmpty() bool {
+	return msc.Moniker == "" && len(msc.Endpoints) == 0 && msc.NetSpeed.UploadSpeed == 0 && msc.NetSpeed.DownloadSpeed == 0 &&
+		len(msc.PricePerGb) == 0 && msc.EncMethod == "" && msc.NodeType == "" && msc.Version == ""
+}
+
+func (msc MsgUpdateVpnService) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Invalid Address")
+	}
+	if msc.IsEmpty() {
+		return sdk.ErrUnknownRequest("No VPN node field to update")
+	}
+	if len(msc.PricePerGb) != 0 && (!msc.PricePerGb.IsValid() || !msc.PricePerGb.IsPositive()) {
+		return ErrInvalidPricePerGb("Price per GB is not Valid")
+	}
+	if len(msc.Endpoints) != 0 {
+		if err := ValidateEndpoints(msc.Endpoints); err != nil {
+			return err
+		}
+	}
+	if msc.NetSpeed.UploadSpeed < 0 || msc.NetSpeed.DownloadSpeed < 0 {
+		return ErrInvalidNetspeed("NetSpeed is not Valid")
+	}
+	return nil
+}
+
+func (msc MsgUpdateVpnService) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
 type MsgRegisterMasterNode struct {
 	Address sdk.AccAddress
+	Bond    sdk.Coin
 }
 
-func NewMsgRegisterMasterNode(addr sdk.AccAddress) MsgRegisterMasterNode {
+func NewMsgRegisterMasterNode(addr sdk.AccAddress, bond sdk.Coin) MsgRegisterMasterNode {
 	return MsgRegisterMasterNode{
 		Address: addr,
+		Bond:    bond,
 	}
 
 }
@@ -153,17 +304,15 @@ func (msc MsgRegisterMasterNode) ValidateBasic() sdk.Error {
 	if msc.Address == nil {
 		return sdk.ErrInvalidAddress("Address type is Invalid")
 	}
+	if msc.Bond.Denom == "" || msc.Bond.Amount == (sdk.Int{}) || !msc.Bond.IsNotNegative() {
+		return ErrInvalidBond("Bond is Invalid")
+	}
 	return nil
 }
 func (msc MsgRegisterMasterNode) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msc.Address}
 }
 
-func (msg MsgRegisterMasterNode) Tags() sdk.Tags {
-	return sdk.NewTags("Master node address ", []byte(msg.Address.String()))
-	// AppendTag("receiver", []byte(msg.Receiver.String()))
-}
-
 //
 //
 //
@@ -281,7 +430,7 @@ func (msc MsgPayVpnService) GetSignBytes() []byte {
 }
 
 func (msc MsgPayVpnService) ValidateBasic() sdk.Error {
-	if msc.Coins.IsZero() || !(msc.Coins.IsNotNegative()) {
+	if msc.Coins.IsZero() || !(msc.Coins.IsNotNegative()) || !msc.Coins.IsValid() {
 		return sdk.ErrInsufficientFunds("Error insufficient coins")
 	}
 	if msc.From == nil || msc.Vpnaddr == nil {
@@ -392,7 +541,7 @@ func (msc MsgGetVpnPayment) GetSignBytes() []byte {
 }
 
 func (msc MsgGetVpnPayment) ValidateBasic() sdk.Error {
-	if msc.Coins.IsZero() || !(msc.Coins.IsNotNegative()) {
+	if msc.Coins.IsZero() || !(msc.Coins.IsNotNegative()) || !msc.Coins.IsValid() {
 		return sdk.ErrInsufficientFunds("Error insufficient coins")
 	}
 	return nil
@@ -401,6 +550,512 @@ func (msc MsgGetVpnPayment) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msc.From}
 }
 
+//
+//
+//
+//
+//
+type MsgSubmitUsageReceipt struct {
+	From    sdk.AccAddress
+	Receipt senttype.UsageReceipt
+}
+
+func NewMsgSubmitUsageReceipt(from sdk.AccAddress, receipt senttype.UsageReceipt) MsgSubmitUsageReceipt {
+	return MsgSubmitUsageReceipt{
+		From:    from,
+		Receipt: receipt,
+	}
+}
+func (msc MsgSubmitUsageReceipt) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgSubmitUsageReceipt) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgSubmitUsageReceipt) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	receipt := msc.Receipt
+	if len(receipt.SessionId) == 0 {
+		return ErrInvalidSessionid("SessionId is Invalid")
+	}
+	if receipt.Counter <= 0 {
+		return ErrSignMsg("Invalid Counter")
+	}
+	if !receipt.IsValidUsage() {
+		return ErrInvalidUsage(fmt.Sprintf("Usage must not be negative nor greater than %d", senttype.MaxUsage))
+	}
+	if receipt.ClientSignature == nil || receipt.NodeSignature == nil {
+		return ErrSignMsg("Usage receipt must be signed by both the client and the VPN node")
+	}
+	return nil
+}
+func (msc MsgSubmitUsageReceipt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgRateNode struct {
+	From   sdk.AccAddress
+	Node   sdk.AccAddress
+	Rating int64
+}
+
+func NewMsgRateNode(from sdk.AccAddress, node sdk.AccAddress, rating int64) MsgRateNode {
+	return MsgRateNode{
+		From:   from,
+		Node:   node,
+		Rating: rating,
+	}
+}
+func (msc MsgRateNode) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgRateNode) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgRateNode) ValidateBasic() sdk.Error {
+	if msc.From == nil || msc.Node == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if msc.Rating < 1 || msc.Rating > MaxRating {
+		return ErrInvalidRating(fmt.Sprintf("Rating must be between 1 and %d", MaxRating))
+	}
+	return nil
+}
+func (msc MsgRateNode) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgOpenDispute struct {
+	From      sdk.AccAddress
+	Sessionid []byte
+}
+
+func NewMsgOpenDispute(from sdk.AccAddress, sid []byte) MsgOpenDispute {
+	return MsgOpenDispute{
+		From:      from,
+		Sessionid: sid,
+	}
+}
+func (msc MsgOpenDispute) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgOpenDispute) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgOpenDispute) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if len(msc.Sessionid) == 0 {
+		return ErrInvalidSessionid("SessionId is Invalid")
+	}
+	return nil
+}
+func (msc MsgOpenDispute) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgDisputeVerdict struct {
+	From      sdk.AccAddress
+	Sessionid []byte
+	Verdict   uint8
+}
+
+func NewMsgDisputeVerdict(from sdk.AccAddress, sid []byte, verdict uint8) MsgDisputeVerdict {
+	return MsgDisputeVerdict{
+		From:      from,
+		Sessionid: sid,
+		Verdict:   verdict,
+	}
+}
+func (msc MsgDisputeVerdict) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgDisputeVerdict) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgDisputeVerdict) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if len(msc.Sessionid) == 0 {
+		return ErrInvalidSessionid("SessionId is Invalid")
+	}
+	if msc.Verdict != VerdictForClient && msc.Verdict != VerdictForNode {
+		return ErrInvalidDispute("Verdict must be in favor of the client or the VPN node")
+	}
+	return nil
+}
+func (msc MsgDisputeVerdict) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgAddPlan struct {
+	From     sdk.AccAddress
+	Nodes    []sdk.AccAddress
+	Price    sdk.Coins
+	Duration int64
+	DataCap  int64
+}
+
+func NewMsgAddPlan(from sdk.AccAddress, nodes []sdk.AccAddress, price sdk.Coins, duration int64, dataCap int64) MsgAddPlan {
+	return MsgAddPlan{
+		From:     from,
+		Nodes:    nodes,
+		Price:    price,
+		Duration: duration,
+		DataCap:  dataCap,
+	}
+}
+func (msc MsgAddPlan) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgAddPlan) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgAddPlan) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	for _, node := range msc.Nodes {
+		if node == nil {
+			return sdk.ErrInvalidAddress("VPN node address is Invalid")
+		}
+	}
+	if !msc.Price.IsValid() || !msc.Price.IsPositive() {
+		return ErrInvalidPlan("Price of the plan is Invalid")
+	}
+	if msc.Duration <= 0 {
+		return ErrInvalidPlan("Duration of the plan must be positive")
+	}
+	if msc.DataCap < 0 {
+		return ErrInvalidPlan("Data cap of the plan must not be negative")
+	}
+	return nil
+}
+func (msc MsgAddPlan) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgSubscribe struct {
+	From   sdk.AccAddress
+	PlanId []byte
+	Pubkey crypto.PubKey
+}
+
+func NewMsgSubscribe(from sdk.AccAddress, planId []byte, pubkey crypto.PubKey) MsgSubscribe {
+	return MsgSubscribe{
+		From:   from,
+		PlanId: planId,
+		Pubkey: pubkey,
+	}
+}
+func (msc MsgSubscribe) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgSubscribe) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgSubscribe) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if len(msc.PlanId) == 0 {
+		return ErrInvalidPlan("PlanId is Invalid")
+	}
+	if msc.Pubkey == nil {
+		return ErrInvalidPubKey("Public key of the client is required to sign usage receipts")
+	}
+	return nil
+}
+func (msc MsgSubscribe) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgSubmitSubscriptionUsage struct {
+	From    sdk.AccAddress
+	Receipt senttype.UsageReceipt
+}
+
+func NewMsgSubmitSubscriptionUsage(from sdk.AccAddress, receipt senttype.UsageReceipt) MsgSubmitSubscriptionUsage {
+	return MsgSubmitSubscriptionUsage{
+		From:    from,
+		Receipt: receipt,
+	}
+}
+func (msc MsgSubmitSubscriptionUsage) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgSubmitSubscriptionUsage) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgSubmitSubscriptionUsage) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	receipt := msc.Receipt
+	if len(receipt.SessionId) == 0 {
+		return ErrInvalidSubscription("SubscriptionId is Invalid")
+	}
+	if receipt.Counter <= 0 {
+		return ErrSignMsg("Invalid Counter")
+	}
+	if !receipt.IsValidUsage() {
+		return ErrInvalidUsage(fmt.Sprintf("Usage must not be negative nor greater than %d", senttype.MaxUsage))
+	}
+	if receipt.ClientSignature == nil {
+		return ErrSignMsg("Usage receipt must be signed by the client")
+	}
+	return nil
+}
+func (msc MsgSubmitSubscriptionUsage) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgSubmitProposal struct {
+	From     sdk.AccAddress
+	Kind     uint8
+	Node     sdk.AccAddress
+	Params   *Params
+	Fraction sdk.Rat
+}
+
+func NewMsgSubmitProposal(from sdk.AccAddress, kind uint8, node sdk.AccAddress, params *Params) MsgSubmitProposal {
+	return MsgSubmitProposal{
+		From:   from,
+		Kind:   kind,
+		Node:   node,
+		Params: params,
+	}
+}
+
+// NewMsgSubmitSlashProposal creates a proposal to slash a fraction of the
+// deposit of a dVPN node
+func NewMsgSubmitSlashProposal(from sdk.AccAddress, node sdk.AccAddress, fraction sdk.Rat) MsgSubmitProposal {
+	return MsgSubmitProposal{
+		From:     from,
+		Kind:     ProposalKindSlashDeposit,
+		Node:     node,
+		Fraction: fraction,
+	}
+}
+func (msc MsgSubmitProposal) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgSubmitProposal) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgSubmitProposal) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	hasFraction := msc.Fraction.Rat != nil && !msc.Fraction.IsZero()
+	switch msc.Kind {
+	case ProposalKindRemoveNode:
+		if msc.Node == nil || msc.Params != nil || hasFraction {
+			return ErrInvalidProposal("Node removal proposals must only carry the VPN node address")
+		}
+	case ProposalKindChangeParams:
+		if msc.Params == nil || msc.Node != nil || hasFraction {
+			return ErrInvalidProposal("Params change proposals must only carry the params")
+		}
+		return msc.Params.Validate()
+	case ProposalKindSlashDeposit:
+		if msc.Params != nil {
+			return ErrInvalidProposal("Deposit slash proposals must only carry the VPN node address and the fraction")
+		}
+		return NewDepositSlash(msc.Node, msc.Fraction).ValidateBasic()
+	default:
+		return ErrInvalidProposal("Kind of the proposal is Invalid")
+	}
+	return nil
+}
+func (msc MsgSubmitProposal) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgVote struct {
+	From       sdk.AccAddress
+	ProposalId []byte
+	Option     uint8
+}
+
+func NewMsgVote(from sdk.AccAddress, proposalId []byte, option uint8) MsgVote {
+	return MsgVote{
+		From:       from,
+		ProposalId: proposalId,
+		Option:     option,
+	}
+}
+func (msc MsgVote) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgVote) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgVote) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if len(msc.ProposalId) == 0 {
+		return ErrInvalidProposal("ProposalId is Invalid")
+	}
+	if msc.Option != VoteOptionYes && msc.Option != VoteOptionNo {
+		return ErrInvalidProposal("Vote option must be yes or no")
+	}
+	return nil
+}
+func (msc MsgVote) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
+//
+//
+//
+//
+//
+type MsgNodeHeartbeat struct {
+	From           sdk.AccAddress
+	Status         string
+	ActiveSessions int64
+	Load           int64
+}
+
+func NewMsgNodeHeartbeat(from sdk.AccAddress, status string, activeSessions int64, load int64) MsgNodeHeartbeat {
+	return MsgNodeHeartbeat{
+		From:           from,
+		Status:         status,
+		ActiveSessions: activeSessions,
+		Load:           load,
+	}
+}
+func (msc MsgNodeHeartbeat) Type() string {
+	return "sentinel"
+}
+
+func (msc MsgNodeHeartbeat) GetSignBytes() []byte {
+	byte_format, err := json.Marshal(msc)
+	if err != nil {
+		return nil
+	}
+	return byte_format
+}
+
+func (msc MsgNodeHeartbeat) ValidateBasic() sdk.Error {
+	if msc.From == nil {
+		return sdk.ErrInvalidAddress("Address type is Invalid")
+	}
+	if !isValidNodeStatus(msc.Status) {
+		return ErrInvalidHeartbeat("Status must be online, busy or maintenance")
+	}
+	if msc.ActiveSessions < 0 {
+		return ErrInvalidHeartbeat("Active sessions must not be negative")
+	}
+	if msc.Load < 0 || msc.Load > MaxNodeLoad {
+		return ErrInvalidHeartbeat(fmt.Sprintf("Load must be between 0 and %d", MaxNodeLoad))
+	}
+	return nil
+}
+func (msc MsgNodeHeartbeat) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msc.From}
+}
+
 //
 //
 //
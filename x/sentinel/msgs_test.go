@@ -0,0 +1,118 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestValidateEndpoints(t *testing.T) {
+	endpoint := func(host string, port int64, protocol string) []senttype.Endpoint {
+		return []senttype.Endpoint{senttype.NewEndpoint(host, port, protocol)}
+	}
+	cases := []struct {
+		endpoints  []senttype.Endpoint
+		expectPass bool
+	}{
+		{endpoint("8.8.8.8", 1194, "udp"), true},
+		{endpoint("2001:4860:4860::8888", 443, "tcp"), true},
+		{endpoint("vpn.example.com", 51820, "udp"), true},
+		{append(endpoint("8.8.8.8", 1194, "udp"), endpoint("2001:4860:4860::8888", 1194, "udp")...), true},
+		{nil, false},
+		{append(endpoint("8.8.8.8", 1194, "udp"), endpoint("8.8.8.8", 1194, "udp")...), false},
+		{endpoint("8.8.8.8", 0, "udp"), false},
+		{endpoint("8.8.8.8", 65536, "udp"), false},
+		{endpoint("8.8.8.8", 1194, "icmp"), false},
+		{endpoint("0.0.0.0", 1194, "udp"), false},
+		{endpoint("127.0.0.1", 1194, "udp"), false},
+		{endpoint("::1", 1194, "udp"), false},
+		{endpoint("10.1.2.3", 1194, "udp"), false},
+		{endpoint("172.20.0.1", 1194, "udp"), false},
+		{endpoint("192.168.1.1", 1194, "udp"), false},
+		{endpoint("100.64.0.1", 1194, "udp"), false},
+		{endpoint("fd00::1", 1194, "udp"), false},
+		{endpoint("169.254.0.1", 1194, "udp"), false},
+		{endpoint("fe80::1", 1194, "udp"), false},
+		{endpoint("224.0.0.1", 1194, "udp"), false},
+		{endpoint("ff02::1", 1194, "udp"), false},
+		{endpoint("::ffff:127.0.0.1", 1194, "udp"), false},
+		{endpoint("1.2.3", 1194, "udp"), false},
+		{endpoint("localhost", 1194, "udp"), false},
+		{endpoint("vpn.localhost", 1194, "udp"), false},
+		{endpoint("printer.local", 1194, "udp"), false},
+		{endpoint("-vpn.example.com", 1194, "udp"), false},
+		{endpoint("vpn..example.com", 1194, "udp"), false},
+		{endpoint("vpn_1.example.com", 1194, "udp"), false},
+	}
+
+	for i, tc := range cases {
+		err := ValidateEndpoints(tc.endpoints)
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+
+	var endpoints []senttype.Endpoint
+	for i := 0; i <= MaxEndpoints; i++ {
+		endpoints = append(endpoints, senttype.NewEndpoint("8.8.8.8", int64(1000+i), "udp"))
+	}
+	require.NotNil(t, ValidateEndpoints(endpoints))
+	require.Nil(t, ValidateEndpoints(endpoints[:MaxEndpoints]))
+}
+
+func TestParseEndpoint(t *testing.T) {
+	cases := []struct {
+		str        string
+		endpoint   senttype.Endpoint
+		expectPass bool
+	}{
+		{"8.8.8.8:1194/udp", senttype.NewEndpoint("8.8.8.8", 1194, "udp"), true},
+		{"[2001:db8::1]:443/TCP", senttype.NewEndpoint("2001:db8::1", 443, "tcp"), true},
+		{"vpn.example.com:51820/udp", senttype.NewEndpoint("vpn.example.com", 51820, "udp"), true},
+		{"8.8.8.8:1194", senttype.Endpoint{}, false},
+		{"8.8.8.8/udp", senttype.Endpoint{}, false},
+		{"2001:db8::1:443/tcp", senttype.Endpoint{}, false},
+		{"8.8.8.8:port/udp", senttype.Endpoint{}, false},
+	}
+
+	for i, tc := range cases {
+		endpoint, err := senttype.ParseEndpoint(tc.str)
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+			require.Equal(t, tc.endpoint, endpoint, "test: %v", i)
+			require.Equal(t, tc.endpoint.String(), endpoint.String(), "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+	require.Equal(t, "[2001:db8::1]:443/tcp", senttype.NewEndpoint("2001:db8::1", 443, "tcp").String())
+}
+
+func TestMsgGetVpnPaymentValidateBasic(t *testing.T) {
+	cases := []struct {
+		coins      sdk.Coins
+		expectPass bool
+	}{
+		{sdk.Coins{{"sut", sdk.NewInt(10)}}, true},
+		{sdk.Coins{{"foo", sdk.NewInt(1)}, {"sut", sdk.NewInt(10)}}, true},
+		{nil, false},
+		{sdk.Coins{{"sut", sdk.NewInt(0)}}, false},
+		{sdk.Coins{{"sut", sdk.NewInt(-10)}}, false},
+		{sdk.Coins{{"sut", sdk.NewInt(10)}, {"foo", sdk.NewInt(1)}}, false},
+		{sdk.Coins{{"sut", sdk.NewInt(10)}, {"sut", sdk.NewInt(1)}}, false},
+	}
+
+	for i, tc := range cases {
+		err := NewMsgGetVpnPayment(tc.coins, []byte("00000000000000000000"), 1, addrs[0], nil, false).ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
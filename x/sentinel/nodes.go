@@ -0,0 +1,167 @@
+package sentinel
+
+import (
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// default and maximum number of dVPN nodes returned per page
+const (
+	DefaultNodesPageLimit = 30
+	MaxNodesPageLimit     = 100
+)
+
+// orders of the dVPN node listing, by descending reputation
+const (
+	SortByRating     = "rating"     // average client rating
+	SortByCompletion = "completion" // completion ratio of the settled sessions
+	SortByBandwidth  = "bandwidth"  // bandwidth served
+	SortBySessions   = "sessions"   // number of settled sessions
+)
+
+// VpnNode - a registered dVPN node along with its owner address, its
+// liveness status, and its reputation in the node queries
+type VpnNode struct {
+	Address    sdk.AccAddress       `json:"address"`
+	Node       senttype.Registervpn `json:"node"`
+	Status     *NodeStatus          `json:"status,omitempty"`
+	Reputation *Reputation          `json:"reputation,omitempty"`
+}
+
+// QueryNodesParams - filters and pagination for listing dVPN nodes, zero
+// values match every node
+type QueryNodesParams struct {
+	Country          string    `json:"country"`
+	City             string    `json:"city"`
+	MaxPricePerGb    sdk.Coins `json:"max_price_per_gb"` // the node must accept one of the denoms at no more than its amount
+	MinUploadSpeed   int64     `json:"min_upload_speed"`
+	MinDownloadSpeed int64     `json:"min_download_speed"`
+	EncMethod        string    `json:"enc_method"`
+	NodeType         string    `json:"node_type"`
+	Version          string    `json:"version"`
+	SortBy           string    `json:"sort_by"` // one of the SortBy orders, by address if empty
+	Page             int       `json:"page"`    // 1-based
+	Limit            int       `json:"limit"`   // nodes per page
+}
+
+// check if a dVPN node satisfies every filter of the params
+func (params QueryNodesParams) Matches(node senttype.Registervpn) bool {
+	switch {
+	case params.Country != "" && !strings.EqualFold(params.Country, node.Location.Country):
+		return false
+	case params.City != "" && !strings.EqualFold(params.City, node.Location.City):
+		return false
+	case len(params.MaxPricePerGb) > 0 && !acceptsPriceWithin(node.PricePerGb, params.MaxPricePerGb):
+		return false
+	case node.NetSpeed.UploadSpeed < params.MinUploadSpeed:
+		return false
+	case node.NetSpeed.DownloadSpeed < params.MinDownloadSpeed:
+		return false
+	case params.EncMethod != "" && params.EncMethod != node.EncMethod:
+		return false
+	case params.NodeType != "" && params.NodeType != node.NodeType:
+		return false
+	case params.Version != "" && params.Version != node.Version:
+		return false
+	}
+	return true
+}
+
+// check if the order of the params is known
+func (params QueryNodesParams) IsValidSortBy() bool {
+	switch params.SortBy {
+	case "", SortByRating, SortByCompletion, SortByBandwidth, SortBySessions:
+		return true
+	}
+	return false
+}
+
+// check if one of the prices is within the max price of its denom
+func acceptsPriceWithin(prices sdk.Coins, maxPrices sdk.Coins) bool {
+	for _, price := range prices {
+		max := maxPrices.AmountOf(price.Denom)
+		if max.Sign() > 0 && !price.Amount.GT(max) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterVpnNodes returns the requested page of the dVPN nodes matching params,
+// in the order of params
+func FilterVpnNodes(nodes []VpnNode, params QueryNodesParams) []VpnNode {
+	filtered := []VpnNode{}
+	for _, node := range nodes {
+		if params.Matches(node.Node) {
+			filtered = append(filtered, node)
+		}
+	}
+	sortVpnNodes(filtered, params.SortBy)
+
+	start, end := pageBounds(params.Page, params.Limit, DefaultNodesPageLimit, MaxNodesPageLimit, len(filtered))
+	return filtered[start:end]
+}
+
+// get the bounds of a 1-based page of a listing of total entries, with the
+// default limit if none is given, and at most max entries
+func pageBounds(page int, limit int, defaultLimit int, max int, total int) (start int, end int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > max {
+		limit = max
+	}
+
+	start = (page - 1) * limit
+	if start >= total {
+		return total, total
+	}
+	end = start + limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// sort dVPN nodes by descending reputation, nodes of an equal reputation
+// keep their order
+func sortVpnNodes(nodes []VpnNode, sortBy string) {
+	if sortBy == "" {
+		return
+	}
+	reputation := func(i int) Reputation {
+		if nodes[i].Reputation == nil {
+			return NewReputation(nodes[i].Address)
+		}
+		return *nodes[i].Reputation
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		repI, repJ := reputation(i), reputation(j)
+		switch sortBy {
+		case SortByRating:
+			return repI.AverageRating().GT(repJ.AverageRating())
+		case SortByCompletion:
+			return repI.CompletionRatio().GT(repJ.CompletionRatio())
+		case SortByBandwidth:
+			return repI.BandwidthServed.GT(repJ.BandwidthServed)
+		case SortBySessions:
+			return repI.Sessions > repJ.Sessions
+		}
+		return false
+	})
+}
+
+// get all the registered dVPN nodes, ordered by address
+func (keeper Keeper) GetVpnServices(ctx sdk.Context) (nodes []VpnNode) {
+	keeper.IterateVpnServices(ctx, func(addr sdk.AccAddress, vpn senttype.Registervpn) (stop bool) {
+		nodes = append(nodes, VpnNode{Address: addr, Node: vpn})
+		return false
+	})
+	return nodes
+}
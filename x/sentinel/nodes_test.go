@@ -0,0 +1,41 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestFilterVpnNodes(t *testing.T) {
+	cheap := senttype.NewVpnRegister("cheap", newTestEndpoints("8.8.8.8"), 100, 100, sdk.Coins{{"sut", sdk.NewInt(5)}}, "AES-256-CBC",
+		174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1")
+	fast := senttype.NewVpnRegister("fast", newTestEndpoints("8.8.4.4"), 5000, 5000, sdk.Coins{{"eth", sdk.NewInt(1)}, {"sut", sdk.NewInt(50)}}, "AES-256-CBC",
+		407128, -740060, "New York", "USA", "OpenVPN", "0.0.2")
+	nodes := []VpnNode{{addrs[0], cheap, nil, nil}, {addrs[1], fast, nil, nil}, {addrs[2], cheap, nil, nil}}
+
+	tests := []struct {
+		params   QueryNodesParams
+		expected []VpnNode
+	}{
+		{QueryNodesParams{}, nodes},
+		{QueryNodesParams{Country: "india"}, []VpnNode{nodes[0], nodes[2]}},
+		{QueryNodesParams{City: "New York"}, []VpnNode{nodes[1]}},
+		{QueryNodesParams{MaxPricePerGb: sdk.Coins{{"sut", sdk.NewInt(10)}}}, []VpnNode{nodes[0], nodes[2]}},
+		{QueryNodesParams{MaxPricePerGb: sdk.Coins{{"eth", sdk.NewInt(1)}}}, []VpnNode{nodes[1]}},
+		{QueryNodesParams{MaxPricePerGb: sdk.Coins{{"btc", sdk.NewInt(100)}}}, []VpnNode{}},
+		{QueryNodesParams{MinUploadSpeed: 1000}, []VpnNode{nodes[1]}},
+		{QueryNodesParams{MinDownloadSpeed: 100000}, []VpnNode{}},
+		{QueryNodesParams{Version: "0.0.1", EncMethod: "AES-256-CBC", NodeType: "OpenVPN"}, []VpnNode{nodes[0], nodes[2]}},
+		{QueryNodesParams{Limit: 2}, []VpnNode{nodes[0], nodes[1]}},
+		{QueryNodesParams{Page: 2, Limit: 2}, []VpnNode{nodes[2]}},
+		{QueryNodesParams{Page: 2, Limit: 1, Country: "India"}, []VpnNode{nodes[2]}},
+		{QueryNodesParams{Page: 3, Limit: 2}, []VpnNode{}},
+	}
+
+	for i, tc := range tests {
+		require.Equal(t, tc.expected, FilterVpnNodes(nodes, tc.params), "test case %d", i)
+	}
+}
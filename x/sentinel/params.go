@@ -0,0 +1,119 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// name of the sentinel subspace of the params store, and the key of the
+// sentinel params within it
+const (
+	DefaultParamspace   = "sentinel"
+	ParamStoreKeyParams = "params"
+)
+
+// ParamTypeTable - the parameters of the sentinel subspace
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterType(ParamStoreKeyParams, Params{}, validateParams)
+}
+
+// Params - the governable parameters of sentinel
+type Params struct {
+	RefundTimeout       int64     `json:"refund_timeout"`        // seconds after which a client may reclaim unreleased session coins
+	MaxMonikerLength    int64     `json:"max_moniker_length"`    // max length of a dVPN node moniker
+	SessionTimeout      int64     `json:"session_timeout"`       // seconds after which an open session is settled by the EndBlocker
+	AllowedDenoms       []string  `json:"allowed_denoms"`        // denoms in which dVPN nodes may be priced and sessions paid
+	MinDeposit          sdk.Coins `json:"min_deposit"`           // minimum deposit locked by a dVPN node on registration
+	UnbondingTime       int64     `json:"unbonding_time"`        // seconds after the deletion of a dVPN node until its deposit is returned
+	PriceChangeInterval int64     `json:"price_change_interval"` // min seconds between two price changes of a dVPN node
+	ChallengePeriod     int64     `json:"challenge_period"`      // seconds after the closing of a session in which higher-counter claims are accepted
+	DisputePeriod       int64     `json:"dispute_period"`        // seconds after the opening of a dispute in which master nodes may submit verdicts
+	DisputeSlash        sdk.Rat   `json:"dispute_slash"`         // fraction of the deposit of a dVPN node slashed when it loses a dispute
+	MinMasterBond       sdk.Coin  `json:"min_master_bond"`       // minimum bond locked by a registering master node, in the denom of all bonds
+	VotingPeriod        int64     `json:"voting_period"`         // seconds after the submission of a sentinel proposal in which master nodes may vote
+	ProposalQuorum      sdk.Rat   `json:"proposal_quorum"`       // fraction of the bonds of the master nodes which must vote on a proposal
+	ProposalThreshold   sdk.Rat   `json:"proposal_threshold"`    // fraction of the voting bonds above which a proposal passes
+	HeartbeatWindow     int64     `json:"heartbeat_window"`      // seconds without a heartbeat after which a dVPN node is inactive, zero never deactivates nodes
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		RefundTimeout:       86400,
+		MaxMonikerLength:    128,
+		SessionTimeout:      2 * 86400,
+		AllowedDenoms:       []string{"sut"},
+		MinDeposit:          sdk.Coins{{"sut", sdk.NewInt(100)}},
+		UnbondingTime:       3 * 7 * 86400,
+		PriceChangeInterval: 86400,
+		ChallengePeriod:     3600,
+		DisputePeriod:       6 * 3600,
+		DisputeSlash:        sdk.NewRat(1, 10),
+		MinMasterBond:       sdk.NewCoin("sut", 100),
+		VotingPeriod:        3 * 86400,
+		ProposalQuorum:      sdk.NewRat(1, 3),
+		ProposalThreshold:   sdk.NewRat(1, 2),
+		HeartbeatWindow:     3600,
+	}
+}
+
+// Validate checks the params set by a params change proposal
+func (params Params) Validate() sdk.Error {
+	if params.RefundTimeout < 0 || params.SessionTimeout <= 0 || params.UnbondingTime < 0 || params.PriceChangeInterval < 0 ||
+		params.ChallengePeriod < 0 || params.DisputePeriod <= 0 || params.VotingPeriod <= 0 || params.HeartbeatWindow < 0 {
+		return ErrInvalidProposal("Periods of the params must not be negative, the session timeout, dispute and voting periods must be positive")
+	}
+	if params.MaxMonikerLength <= 0 || len(params.AllowedDenoms) == 0 {
+		return ErrInvalidProposal("Params must allow monikers and payment denoms")
+	}
+	if !params.MinDeposit.IsValid() && len(params.MinDeposit) != 0 {
+		return ErrInvalidProposal("Minimum deposit of the params is Invalid")
+	}
+	if params.MinMasterBond.Denom == "" || params.MinMasterBond.Amount == (sdk.Int{}) || !params.MinMasterBond.IsNotNegative() {
+		return ErrInvalidProposal("Minimum master node bond of the params is Invalid")
+	}
+	for _, fraction := range []sdk.Rat{params.DisputeSlash, params.ProposalQuorum, params.ProposalThreshold} {
+		if fraction.Rat == nil || fraction.LT(sdk.ZeroRat()) || fraction.GT(sdk.OneRat()) {
+			return ErrInvalidProposal("Fractions of the params must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+// validate the sentinel params set by a params change
+func validateParams(value interface{}) error {
+	if err := value.(Params).Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// check if payments are accepted in the denom
+func (params Params) IsAllowedDenom(denom string) bool {
+	for _, allowed := range params.AllowedDenoms {
+		if allowed == denom {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateParams sets the default sentinel params, which sentinel used before
+// they became governable, unless they have been set. It is run by the upgrade
+// handlers registered by the app.
+func (keeper Keeper) MigrateParams(ctx sdk.Context) {
+	if !keeper.paramSpace.Has(ctx, ParamStoreKeyParams) {
+		keeper.SetParams(ctx, DefaultParams())
+	}
+}
+
+// load/save the sentinel params, kept in the sentinel subspace of the params store
+func (keeper Keeper) GetParams(ctx sdk.Context) (params Params) {
+	keeper.paramSpace.Get(ctx, ParamStoreKeyParams, &params)
+	return
+}
+
+// set the params
+func (keeper Keeper) SetParams(ctx sdk.Context, params Params) {
+	keeper.paramSpace.Set(ctx, ParamStoreKeyParams, params)
+}
@@ -0,0 +1,267 @@
+package sentinel
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// query endpoints supported by the sentinel Querier
+const (
+	QueryNode         = "node"
+	QueryNodes        = "nodes"
+	QueryNodeHistory  = "node_history"
+	QuerySession      = "session"
+	QuerySessions     = "sessions"
+	QueryUsageReceipt = "usage_receipt"
+	QueryDispute      = "dispute"
+	QueryPlans        = "plans"
+	QuerySubscription = "subscription"
+	QueryMasterNode   = "master_node"
+	QueryProposal     = "proposal"
+	QueryProposals    = "proposals"
+)
+
+// NewQuerier returns the sdk.Querier answering "custom/sentinel/<endpoint>" queries
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("no sentinel query endpoint specified")
+		}
+		switch path[0] {
+		case QueryNode:
+			return queryNode(ctx, req, keeper)
+		case QueryNodes:
+			return queryNodes(ctx, req, keeper)
+		case QueryNodeHistory:
+			return queryNodeHistory(ctx, req, keeper)
+		case QuerySession:
+			return querySession(ctx, req, keeper)
+		case QuerySessions:
+			return querySessions(ctx, req, keeper)
+		case QueryUsageReceipt:
+			return queryUsageReceipt(ctx, req, keeper)
+		case QueryDispute:
+			return queryDispute(ctx, req, keeper)
+		case QueryPlans:
+			return queryPlans(ctx, req, keeper)
+		case QuerySubscription:
+			return querySubscription(ctx, req, keeper)
+		case QueryMasterNode:
+			return queryMasterNode(ctx, req, keeper)
+		case QueryProposal:
+			return queryProposal(ctx, req, keeper)
+		case QueryProposals:
+			return marshalQueryResult(keeper.cdc, keeper.GetProposals(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown sentinel query endpoint %s", path[0]))
+		}
+	}
+}
+
+// Params for queries:
+// - 'custom/sentinel/node'
+// - 'custom/sentinel/node_history'
+// - 'custom/sentinel/master_node'
+type QueryNodeParams struct {
+	Address sdk.AccAddress `json:"address"`
+}
+
+// Params for queries:
+// - 'custom/sentinel/session'
+// - 'custom/sentinel/usage_receipt'
+// - 'custom/sentinel/dispute'
+type QuerySessionParams struct {
+	SessionId string `json:"session_id"`
+}
+
+// Params for queries:
+// - 'custom/sentinel/subscription'
+type QuerySubscriptionParams struct {
+	SubscriptionId string `json:"subscription_id"`
+}
+
+// Params for queries:
+// - 'custom/sentinel/proposal'
+type QueryProposalParams struct {
+	ProposalId string `json:"proposal_id"`
+}
+
+func queryNode(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryNodeParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	vpn, found := keeper.GetVpnService(ctx, params.Address)
+	if !found {
+		return nil, ErrAccountAddressNotExist(fmt.Sprintf("no dVPN node found with address %s", params.Address))
+	}
+	node := keeper.withStatuses(ctx, []VpnNode{{Address: params.Address, Node: vpn}})[0]
+	rep := keeper.GetReputation(ctx, params.Address)
+	node.Reputation = &rep
+	return marshalQueryResult(keeper.cdc, node)
+}
+
+func queryNodes(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryNodesParams
+	if len(req.Data) != 0 {
+		errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+		if errRes != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+		}
+	}
+
+	if !params.IsValidSortBy() {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown dVPN node order %s", params.SortBy))
+	}
+	nodes := keeper.withReputations(ctx, keeper.GetActiveVpnServices(ctx))
+	return marshalQueryResult(keeper.cdc, FilterVpnNodes(nodes, params))
+}
+
+func queryNodeHistory(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryNodeParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	changes := keeper.GetVpnChanges(ctx, params.Address)
+	if changes == nil {
+		changes = []VpnChange{}
+	}
+	return marshalQueryResult(keeper.cdc, changes)
+}
+
+func querySession(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QuerySessionParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	session, found := keeper.GetSession(ctx, []byte(params.SessionId))
+	if !found {
+		return nil, ErrUnknownSessionid(fmt.Sprintf("no session found with id %s", params.SessionId))
+	}
+	return marshalQueryResult(keeper.cdc, session)
+}
+
+func querySessions(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QuerySessionsParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	if len(params.Client) == 0 && len(params.Node) == 0 {
+		return nil, sdk.ErrUnknownRequest("the client or the dVPN node of the sessions must be specified")
+	}
+	if !params.IsValidStatus() {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown session status %s", params.Status))
+	}
+	return marshalQueryResult(keeper.cdc, keeper.GetSessions(ctx, params))
+}
+
+func queryUsageReceipt(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QuerySessionParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	receipt, found := keeper.GetUsageReceipt(ctx, []byte(params.SessionId))
+	if !found {
+		return nil, ErrUnknownSessionid(fmt.Sprintf("no usage receipt found for session %s", params.SessionId))
+	}
+	return marshalQueryResult(keeper.cdc, receipt)
+}
+
+func queryDispute(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QuerySessionParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	dispute, found := keeper.GetDispute(ctx, []byte(params.SessionId))
+	if !found {
+		return nil, ErrInvalidDispute(fmt.Sprintf("no dispute found for session %s", params.SessionId))
+	}
+	return marshalQueryResult(keeper.cdc, dispute)
+}
+
+func queryPlans(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryPlansParams
+	if len(req.Data) != 0 {
+		errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+		if errRes != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+		}
+	}
+
+	return marshalQueryResult(keeper.cdc, keeper.GetPlans(ctx, params))
+}
+
+func querySubscription(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QuerySubscriptionParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	sub, found := keeper.GetSubscription(ctx, []byte(params.SubscriptionId))
+	if !found {
+		return nil, ErrInvalidSubscription(fmt.Sprintf("no subscription found with id %s", params.SubscriptionId))
+	}
+	usages := keeper.GetSubscriptionUsages(ctx, []byte(params.SubscriptionId))
+	if usages == nil {
+		usages = []SubscriptionUsage{}
+	}
+	return marshalQueryResult(keeper.cdc, SubscriptionInfo{
+		Subscription: sub,
+		Active:       sub.IsActive(ctx.BlockHeader().Time),
+		Usages:       usages,
+	})
+}
+
+func queryMasterNode(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryNodeParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	if !keeper.IsMasterNode(ctx, params.Address) {
+		return nil, ErrAccountAddressNotExist(fmt.Sprintf("no master node found with address %s", params.Address))
+	}
+	info := MasterNodeInfo{Address: params.Address}
+	info.Bond, _ = keeper.GetMasterBond(ctx, params.Address)
+	return marshalQueryResult(keeper.cdc, info)
+}
+
+func queryProposal(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var params QueryProposalParams
+	errRes := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	proposal, found := keeper.GetProposal(ctx, []byte(params.ProposalId))
+	if !found {
+		return nil, ErrInvalidProposal(fmt.Sprintf("no proposal found with id %s", params.ProposalId))
+	}
+	return marshalQueryResult(keeper.cdc, ProposalInfo{proposal, keeper.tally(ctx, proposal)})
+}
+
+func marshalQueryResult(cdc *wire.Codec, result interface{}) (res []byte, err sdk.Error) {
+	bz, errRes := wire.MarshalJSONIndent(cdc, result)
+	if errRes != nil {
+		return nil, ErrMarshal(fmt.Sprintf("could not marshal result to JSON - %s", errRes.Error()))
+	}
+	return bz, nil
+}
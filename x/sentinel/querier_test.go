@@ -0,0 +1,96 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestQuerier(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	querier := NewQuerier(keeper)
+
+	vpn := newTestVpnNode()
+	keeper.SetVpnService(ctx, addrs[0], vpn)
+	session := senttype.GetNewSessionMap(sdk.Coins{{"sut", sdk.NewInt(10)}}, pks[0], pks[1], addrs[1], 0)
+	keeper.SetSession(ctx, []byte("session1"), session)
+
+	query := func(endpoint string, params interface{}) ([]byte, sdk.Error) {
+		bz, err := keeper.cdc.MarshalJSON(params)
+		require.Nil(t, err)
+		return querier(ctx, []string{endpoint}, abci.RequestQuery{Data: bz})
+	}
+
+	// node
+	res, err := query(QueryNode, QueryNodeParams{Address: addrs[0]})
+	require.Nil(t, err)
+	var node VpnNode
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &node))
+	rep := NewReputation(addrs[0])
+	require.Equal(t, VpnNode{Address: addrs[0], Node: vpn, Reputation: &rep}, node)
+	_, err = query(QueryNode, QueryNodeParams{Address: addrs[1]})
+	require.NotNil(t, err)
+
+	// nodes
+	res, err = query(QueryNodes, QueryNodesParams{Country: "india"})
+	require.Nil(t, err)
+	var nodes []VpnNode
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &nodes))
+	require.Equal(t, []VpnNode{node}, nodes)
+	res, err = query(QueryNodes, QueryNodesParams{Country: "USA"})
+	require.Nil(t, err)
+	nodes = nil
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &nodes))
+	require.Len(t, nodes, 0)
+	_, err = query(QueryNodes, QueryNodesParams{SortBy: "price"})
+	require.NotNil(t, err)
+
+	// session
+	res, err = query(QuerySession, QuerySessionParams{SessionId: "session1"})
+	require.Nil(t, err)
+	var stored senttype.Session
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &stored))
+	require.Equal(t, session, stored)
+	_, err = query(QuerySession, QuerySessionParams{SessionId: "unknown"})
+	require.NotNil(t, err)
+
+	// sessions
+	res, err = query(QuerySessions, QuerySessionsParams{Client: addrs[1]})
+	require.Nil(t, err)
+	var sessions []SessionInfo
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &sessions))
+	require.Equal(t, []SessionInfo{NewSessionInfo([]byte("session1"), session)}, sessions)
+	_, err = query(QuerySessions, QuerySessionsParams{})
+	require.NotNil(t, err)
+	_, err = query(QuerySessions, QuerySessionsParams{Node: addrs[0], Status: "settled"})
+	require.NotNil(t, err)
+
+	// plans
+	plan, err := keeper.AddPlan(ctx, NewMsgAddPlan(addrs[0], nil, sdk.Coins{{"sut", sdk.NewInt(100)}}, 3600, 0))
+	require.Nil(t, err)
+	res, err = query(QueryPlans, QueryPlansParams{Node: addrs[0]})
+	require.Nil(t, err)
+	var plans []Plan
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &plans))
+	require.Equal(t, []Plan{plan}, plans)
+
+	// subscription
+	sub, err := keeper.Subscribe(ctx, NewMsgSubscribe(addrs[1], []byte(plan.Id), pks[1]))
+	require.Nil(t, err)
+	res, err = query(QuerySubscription, QuerySubscriptionParams{SubscriptionId: sub.Id})
+	require.Nil(t, err)
+	var info SubscriptionInfo
+	require.Nil(t, keeper.cdc.UnmarshalJSON(res, &info))
+	require.Equal(t, sub, info.Subscription)
+	require.True(t, info.Active)
+	_, err = query(QuerySubscription, QuerySubscriptionParams{SubscriptionId: "unknown"})
+	require.NotNil(t, err)
+
+	// unknown endpoint
+	_, err = querier(ctx, []string{"foo"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,169 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// highest rating of a dVPN node, ratings range from 1 to MaxRating
+const MaxRating int64 = 5
+
+// Reputation - the quality record of a dVPN node, updated as its sessions are
+// settled and rated. It outlives the node, so that it is kept on re-registration.
+type Reputation struct {
+	Node              sdk.AccAddress `json:"node"`               // address of the dVPN node
+	Sessions          int64          `json:"sessions"`           // settled sessions
+	CompletedSessions int64          `json:"completed_sessions"` // sessions closed by a final client signature
+	DisputedSessions  int64          `json:"disputed_sessions"`  // sessions settled by a dispute verdict against the node
+	BandwidthServed   sdk.Int        `json:"bandwidth_served"`   // bytes of the usage receipts of the settled sessions
+	Ratings           int64          `json:"ratings"`            // number of client ratings
+	RatingTotal       int64          `json:"rating_total"`       // sum of the client ratings
+}
+
+// NewReputation creates the empty reputation of a dVPN node
+func NewReputation(node sdk.AccAddress) Reputation {
+	return Reputation{Node: node, BandwidthServed: sdk.ZeroInt()}
+}
+
+// fraction of the settled sessions which were completed, zero without sessions
+func (rep Reputation) CompletionRatio() sdk.Rat {
+	if rep.Sessions == 0 {
+		return sdk.ZeroRat()
+	}
+	return sdk.NewRat(rep.CompletedSessions, rep.Sessions)
+}
+
+// average client rating, zero without ratings
+func (rep Reputation) AverageRating() sdk.Rat {
+	if rep.Ratings == 0 {
+		return sdk.ZeroRat()
+	}
+	return sdk.NewRat(rep.RatingTotal, rep.Ratings)
+}
+
+// NodeRating - the rating of a dVPN node by a client with a settled session on
+// the node, zero until the client rates it
+type NodeRating struct {
+	Node   sdk.AccAddress `json:"node"`
+	Client sdk.AccAddress `json:"client"`
+	Rating int64          `json:"rating"`
+}
+
+// get the reputation of a dVPN node, empty if none of its sessions was settled
+func (keeper Keeper) GetReputation(ctx sdk.Context, addr sdk.AccAddress) Reputation {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetReputationKey(addr))
+	if bz == nil {
+		return NewReputation(addr)
+	}
+	var rep Reputation
+	keeper.cdc.MustUnmarshalBinary(bz, &rep)
+	return rep
+}
+
+// set the reputation of a dVPN node
+func (keeper Keeper) SetReputation(ctx sdk.Context, rep Reputation) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(rep)
+	store.Set(GetReputationKey(rep.Node), bz)
+}
+
+// iterate through the reputations, execute func for each
+func (keeper Keeper) IterateReputations(ctx sdk.Context, fn func(rep Reputation) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, ReputationKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var rep Reputation
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &rep)
+		if fn(rep) {
+			break
+		}
+	}
+}
+
+// get the rating of a dVPN node by a client, found once a session of the
+// client on the node was settled
+func (keeper Keeper) GetNodeRating(ctx sdk.Context, nodeAddr sdk.AccAddress, clientAddr sdk.AccAddress) (rating NodeRating, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetNodeRatingKey(nodeAddr, clientAddr))
+	if bz == nil {
+		return rating, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &rating)
+	return rating, true
+}
+
+// set the rating of a dVPN node by a client
+func (keeper Keeper) SetNodeRating(ctx sdk.Context, rating NodeRating) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(rating)
+	store.Set(GetNodeRatingKey(rating.Node, rating.Client), bz)
+}
+
+// iterate through the ratings of the dVPN nodes, execute func for each
+func (keeper Keeper) IterateNodeRatings(ctx sdk.Context, fn func(rating NodeRating) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, NodeRatingKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var rating NodeRating
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &rating)
+		if fn(rating) {
+			break
+		}
+	}
+}
+
+// count a settled session in the reputation of its dVPN node, along with the
+// bandwidth of its latest usage receipt, and allow its client to rate the node.
+// Only the sessions the node lost a dispute on count against it, clients may
+// refund any session.
+func (keeper Keeper) recordSettledSession(ctx sdk.Context, sessionId []byte, session senttype.Session) {
+	node := session.VpnAddress()
+	rep := keeper.GetReputation(ctx, node)
+	rep.Sessions++
+	switch session.CloseReason {
+	case senttype.CloseReasonFinal:
+		rep.CompletedSessions++
+	case senttype.CloseReasonDisputeForClient:
+		rep.DisputedSessions++
+	}
+	if receipt, found := keeper.GetUsageReceipt(ctx, sessionId); found {
+		rep.BandwidthServed = rep.BandwidthServed.Add(receipt.TotalBytes())
+	}
+	keeper.SetReputation(ctx, rep)
+
+	if _, found := keeper.GetNodeRating(ctx, node, session.CAddress); !found {
+		keeper.SetNodeRating(ctx, NodeRating{Node: node, Client: session.CAddress})
+	}
+}
+
+// RateNode sets the rating of a dVPN node by a client with a settled session
+// on the node, replacing the previous rating of the client
+func (keeper Keeper) RateNode(ctx sdk.Context, msg MsgRateNode) sdk.Error {
+	rating, found := keeper.GetNodeRating(ctx, msg.Node, msg.From)
+	if !found {
+		return ErrUnauthorizedRating("Only clients with a settled session on the VPN node may rate it")
+	}
+
+	rep := keeper.GetReputation(ctx, msg.Node)
+	if rating.Rating == 0 {
+		rep.Ratings++
+	}
+	rep.RatingTotal += msg.Rating - rating.Rating
+	keeper.SetReputation(ctx, rep)
+
+	rating.Rating = msg.Rating
+	keeper.SetNodeRating(ctx, rating)
+	return nil
+}
+
+// attach the reputations to a listing of dVPN nodes
+func (keeper Keeper) withReputations(ctx sdk.Context, nodes []VpnNode) []VpnNode {
+	for i := range nodes {
+		rep := keeper.GetReputation(ctx, nodes[i].Address)
+		nodes[i].Reputation = &rep
+	}
+	return nodes
+}
@@ -0,0 +1,120 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestReputation(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	params := keeper.GetParams(ctx)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	clientKey := ed25519.GenPrivKey()
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	open := func() []byte {
+		id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], clientKey.PubKey()))
+		require.Nil(t, err)
+		return []byte(id)
+	}
+
+	// clients may only rate nodes they had a settled session on
+	err := keeper.RateNode(ctx, NewMsgRateNode(addrs[1], addrs[0], 4))
+	require.Equal(t, CodeUnauthorizedRating, err.Code())
+
+	// a session completed by a final client signature
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	sessionId := open()
+	sig, signErr := clientKey.Sign(senttype.ClientStdSignBytes(coins, sessionId, 1, true))
+	require.Nil(t, signErr)
+	_, _, err = keeper.GetVpnPayment(ctx, NewMsgGetVpnPayment(coins, sessionId, 1, addrs[0], sig, true))
+	require.Nil(t, err)
+
+	// a session refunded by the client, with the largest usage receipt
+	sessionId = open()
+	keeper.SetUsageReceipt(ctx, senttype.NewUsageReceipt(sessionId, 1, senttype.MaxUsage, senttype.MaxUsage, 60, nil, nil))
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout})
+	_, _, err = keeper.RefundBal(ctx, NewMsgRefund(addrs[1], sessionId))
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + params.RefundTimeout + params.ChallengePeriod})
+	EndBlocker(ctx, keeper)
+
+	rep := keeper.GetReputation(ctx, addrs[0])
+	require.Equal(t, int64(2), rep.Sessions)
+	require.Equal(t, int64(1), rep.CompletedSessions)
+	require.Equal(t, int64(0), rep.DisputedSessions)
+	require.Equal(t, sdk.NewInt(senttype.MaxUsage).MulRaw(2), rep.BandwidthServed)
+	require.Equal(t, sdk.NewRat(1, 2), rep.CompletionRatio())
+	require.Equal(t, sdk.ZeroRat(), rep.AverageRating())
+
+	// a rating replaces the previous rating of the client
+	require.Nil(t, keeper.RateNode(ctx, NewMsgRateNode(addrs[1], addrs[0], 4)))
+	require.Nil(t, keeper.RateNode(ctx, NewMsgRateNode(addrs[1], addrs[0], 2)))
+	rep = keeper.GetReputation(ctx, addrs[0])
+	require.Equal(t, int64(1), rep.Ratings)
+	require.Equal(t, sdk.NewRat(2, 1), rep.AverageRating())
+	err = keeper.RateNode(ctx, NewMsgRateNode(addrs[2], addrs[0], 5))
+	require.Equal(t, CodeUnauthorizedRating, err.Code())
+
+	// the reputation is listed along with the node
+	nodes := keeper.withReputations(ctx, keeper.GetVpnServices(ctx))
+	require.Equal(t, &rep, nodes[0].Reputation)
+}
+
+func TestSortVpnNodes(t *testing.T) {
+	reputation := func(addr sdk.AccAddress, sessions int64, completed int64, bandwidth int64, ratings int64, total int64) *Reputation {
+		return &Reputation{addr, sessions, completed, 0, sdk.NewInt(bandwidth), ratings, total}
+	}
+	nodes := []VpnNode{
+		{addrs[0], newTestVpnNode(), reputation(addrs[0], 10, 5, 3000, 2, 6)},
+		{addrs[1], newTestVpnNode(), reputation(addrs[1], 2, 2, 1000, 1, 5)},
+		{addrs[2], newTestVpnNode(), nil},
+	}
+
+	tests := []struct {
+		sortBy   string
+		expected []VpnNode
+	}{
+		{"", nodes},
+		{SortByRating, []VpnNode{nodes[1], nodes[0], nodes[2]}},
+		{SortByCompletion, []VpnNode{nodes[1], nodes[0], nodes[2]}},
+		{SortByBandwidth, []VpnNode{nodes[0], nodes[1], nodes[2]}},
+		{SortBySessions, []VpnNode{nodes[0], nodes[1], nodes[2]}},
+	}
+
+	for i, tc := range tests {
+		params := QueryNodesParams{SortBy: tc.sortBy}
+		require.True(t, params.IsValidSortBy())
+		require.Equal(t, tc.expected, FilterVpnNodes(nodes, params), "test case %d", i)
+	}
+	require.Equal(t, []VpnNode{nodes[0]}, FilterVpnNodes(nodes, QueryNodesParams{SortBy: SortByBandwidth, Limit: 1}))
+	require.False(t, QueryNodesParams{SortBy: "price"}.IsValidSortBy())
+}
+
+func TestMsgRateNodeValidateBasic(t *testing.T) {
+	cases := []struct {
+		msg        MsgRateNode
+		expectPass bool
+	}{
+		{NewMsgRateNode(addrs[1], addrs[0], 1), true},
+		{NewMsgRateNode(addrs[1], addrs[0], MaxRating), true},
+		{NewMsgRateNode(addrs[1], addrs[0], 0), false},
+		{NewMsgRateNode(addrs[1], addrs[0], MaxRating+1), false},
+		{NewMsgRateNode(nil, addrs[0], 3), false},
+		{NewMsgRateNode(addrs[1], nil, 3), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package rest
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	sent "github.com/cosmos/cosmos-sdk/x/sentinel"
-	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
 	"github.com/gorilla/mux"
 
 	"github.com/cosmos/cosmos-sdk/wire"
@@ -49,21 +50,670 @@ func querySessionHandlerFn(cdc *wire.Codec, ctx context.CoreContext, k sent.Keep
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		vars := mux.Vars(r)
-		var clientSession senttype.Session
-		sessionId := vars["sessionId"]
-		res, err := ctx.QueryStore([]byte(sessionId), storeName)
+		params := sent.QuerySessionParams{SessionId: vars["sessionId"]}
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QuerySession), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query session. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /session/{sessionId}/usage To get the latest usage receipt of a session.
+* @apiName getSessionUsage
+* @apiGroup Sentinel-Tendermint
+* @apiSuccessExample Response:
+*{
+*    "session_id": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM=",
+*    "counter": "3",
+*    "upload": "52428800",
+*    "download": "1073741824",
+*    "duration": "3600",
+*    "client_signature": "0FOBGsPAdIGEfhT4LGhyjAFSm2tWcBNvXNiDy5CRqsjpDNYxi3xd+RD6ZJmVQLg61iGJHtPKdbwM+kedJSbNAQ==",
+*    "node_signature": "XgEF4Cp0T7Kru5RYTm5Ld2g02iL5w1T/4pKiVwyPTzzEpTIMvzRs4V6L9MrhKGcfPEivlsqlPpW3iVGNAv8GDA=="
+*}
+ */
+func queryUsageReceiptHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+		params := sent.QuerySessionParams{SessionId: vars["sessionId"]}
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryUsageReceipt), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query usage receipt. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /session/{sessionId}/dispute To get the dispute of a session along with the verdicts of the master nodes.
+* @apiName getSessionDispute
+* @apiGroup Sentinel-Tendermint
+* @apiSuccessExample Response:
+*{
+*    "session_id": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM=",
+*    "opener": "cosmosaccaddr1udkdyy5c4m6gv0tmkmrmw0zdg3t9vmq9mepkyv",
+*    "session_status": 1,
+*    "deadline": "1535980800",
+*    "verdicts": [
+*        {
+*            "master_node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*            "verdict": 2
+*        }
+*    ]
+*}
+ */
+func queryDisputeHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+		params := sent.QuerySessionParams{SessionId: vars["sessionId"]}
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryDispute), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query dispute. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /vpn/nodes To list the registered dVPN nodes.
+* @apiName getVpnNodes
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} [country] Country of the dVPN node.
+* @apiParam {String} [city] City of the dVPN node.
+* @apiParam {String} [max_price_per_gb] Maximum price per GB in any of the given coins, e.g. "10sut".
+* @apiParam {Number} [min_upload_speed] Minimum upload speed.
+* @apiParam {Number} [min_download_speed] Minimum download speed.
+* @apiParam {String} [enc_method] Encryption method.
+* @apiParam {String} [node_type] Type of the dVPN node.
+* @apiParam {String} [version] Software version of the dVPN node.
+* @apiParam {String="rating","completion","bandwidth","sessions"} [sort_by] Order the dVPN nodes by descending reputation instead of by address.
+* @apiParam {Number} [page=1] Page of the results.
+* @apiParam {Number} [limit=30] Number of dVPN nodes per page.
+* @apiSuccessExample Response:
+*[
+*    {
+*        "address": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "node": {
+*            "Moniker": "node",
+*            "Endpoints": [
+*                {
+*                    "Host": "8.8.8.8",
+*                    "Port": "1194",
+*                    "Protocol": "udp"
+*                }
+*            ],
+*            "NetSpeed": {
+*                "UploadSpeed": "1000",
+*                "DownloadSpeed": "1000"
+*            },
+*            "PricePerGb": [
+*                {
+*                    "denom": "sut",
+*                    "amount": "10"
+*                }
+*            ],
+*            "EncMethod": "AES-256-CBC",
+*            "Location": {
+*                "Latitude": "174560",
+*                "Longitude": "784850",
+*                "City": "Hyderabad",
+*                "Country": "India"
+*            },
+*            "NodeType": "OpenVPN",
+*            "Version": "0.0.1"
+*        },
+*        "reputation": {
+*            "node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*            "sessions": "12",
+*            "completed_sessions": "10",
+*            "disputed_sessions": "1",
+*            "bandwidth_served": "48318382080",
+*            "ratings": "4",
+*            "rating_total": "17"
+*        }
+*    }
+*]
+ */
+
+func queryNodesHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		params, err := parseQueryNodesParams(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryNodes), bz)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("couldn't query session."))
+			w.Write([]byte(fmt.Sprintf("couldn't query dVPN nodes. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /vpn/nodes/{address}/history To get the changes of a dVPN node, oldest first.
+* @apiName getVpnNodeHistory
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} address Bech32 address of the dVPN node.
+* @apiSuccessExample Response:
+*[
+*    {
+*        "node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "sequence": "0",
+*        "height": "1250",
+*        "time": "1537361017",
+*        "old": {
+*            "Moniker": "node",
+*            "Endpoints": [...],
+*            "PricePerGb": [
+*                {
+*                    "denom": "sut",
+*                    "amount": "10"
+*                }
+*            ],
+*            ...
+*        },
+*        "new": {
+*            "Moniker": "node",
+*            "Endpoints": [...],
+*            "PricePerGb": [
+*                {
+*                    "denom": "sut",
+*                    "amount": "20"
+*                }
+*            ],
+*            ...
+*        }
+*    }
+*]
+ */
+
+func queryNodeHistoryHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+		addr, err := sdk.AccAddressFromBech32(vars["address"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		bz, err := cdc.MarshalJSON(sent.QueryNodeParams{Address: addr})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
 			return
 		}
-		err = cdc.UnmarshalBinary(res, &clientSession)
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryNodeHistory), bz)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("couldn't marshall query result. Error: "))
+			w.Write([]byte(fmt.Sprintf("couldn't query dVPN node history. Error: %s", err.Error())))
 			return
 		}
-		bz, err := json.Marshal(clientSession)
-		w.Write(bz)
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /vpn/nodes/{address}/sessions To list the open sessions of a dVPN node.
+* @apiName getVpnNodeSessions
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} address Bech32 address of the dVPN node.
+* @apiParam {String="active","closing","disputed"} [status] Status of the sessions.
+* @apiParam {Number} [page=1] Page of the results.
+* @apiParam {Number} [limit=30] Number of sessions per page.
+* @apiSuccessExample Response:
+*[
+*    {
+*        "session_id": "00000000000000000001",
+*        "client": "cosmosaccaddr1udkdyy5c4m6gv0tmkmrmw0zdg3t9vmq9mepkyv",
+*        "node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "status": "active",
+*        "timestamp": "1537361017",
+*        "counter": "2",
+*        "locked_coins": [
+*            {
+*                "denom": "sut",
+*                "amount": "100"
+*            }
+*        ],
+*        "released_coins": [
+*            {
+*                "denom": "sut",
+*                "amount": "40"
+*            }
+*        ],
+*        "remaining_coins": [
+*            {
+*                "denom": "sut",
+*                "amount": "60"
+*            }
+*        ]
+*    }
+*]
+ */
+func queryNodeSessionsHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return querySessionsHandlerFn(cdc, ctx, func(params *sent.QuerySessionsParams, addr sdk.AccAddress) {
+		params.Node = addr
+	})
+}
+
+/**
+* @api {get} /client/{address}/sessions To list the open sessions of a client.
+* @apiName getClientSessions
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} address Bech32 address of the client.
+* @apiParam {String="active","closing","disputed"} [status] Status of the sessions.
+* @apiParam {Number} [page=1] Page of the results.
+* @apiParam {Number} [limit=30] Number of sessions per page.
+* @apiSuccessExample Response:
+*[
+*    {
+*        "session_id": "00000000000000000001",
+*        "client": "cosmosaccaddr1udkdyy5c4m6gv0tmkmrmw0zdg3t9vmq9mepkyv",
+*        "node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "status": "active",
+*        ...
+*    }
+*]
+ */
+func queryClientSessionsHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return querySessionsHandlerFn(cdc, ctx, func(params *sent.QuerySessionsParams, addr sdk.AccAddress) {
+		params.Client = addr
+	})
+}
+
+// list the sessions of the address of the path, set as a party by setParty
+func querySessionsHandlerFn(cdc *wire.Codec, ctx context.CoreContext, setParty func(*sent.QuerySessionsParams, sdk.AccAddress)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		addr, err := sdk.AccAddressFromBech32(mux.Vars(r)["address"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		params, err := parseQuerySessionsParams(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		setParty(&params, addr)
+
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QuerySessions), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query sessions. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+func parseQuerySessionsParams(r *http.Request) (params sent.QuerySessionsParams, err error) {
+	query := r.URL.Query()
+	params.Status = query.Get("status")
+
+	for name, value := range map[string]*int{
+		"page":  &params.Page,
+		"limit": &params.Limit,
+	} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = strconv.Atoi(query.Get(name))
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %s", name, query.Get(name))
+		}
+	}
+	return params, nil
+}
+
+func parseQueryNodesParams(r *http.Request) (params sent.QueryNodesParams, err error) {
+	query := r.URL.Query()
+	params.Country = query.Get("country")
+	params.City = query.Get("city")
+	params.EncMethod = query.Get("enc_method")
+	params.NodeType = query.Get("node_type")
+	params.Version = query.Get("version")
+	params.SortBy = query.Get("sort_by")
+
+	params.MaxPricePerGb, err = sdk.ParseCoins(query.Get("max_price_per_gb"))
+	if err != nil {
+		return params, fmt.Errorf("invalid max_price_per_gb: %s", query.Get("max_price_per_gb"))
+	}
+
+	for name, value := range map[string]*int64{
+		"min_upload_speed":   &params.MinUploadSpeed,
+		"min_download_speed": &params.MinDownloadSpeed,
+	} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = strconv.ParseInt(query.Get(name), 10, 64)
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %s", name, query.Get(name))
+		}
+	}
+
+	for name, value := range map[string]*int{
+		"page":  &params.Page,
+		"limit": &params.Limit,
+	} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = strconv.Atoi(query.Get(name))
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %s", name, query.Get(name))
+		}
+	}
+	return params, nil
+}
+
+/**
+* @api {get} /vpn/plans To list the subscription plans.
+* @apiName getVpnPlans
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} [provider] Bech32 address of the dVPN node or master node publishing the plans.
+* @apiParam {String} [node] Bech32 address of a dVPN node serving the plans.
+* @apiParam {Number} [page=1] Page of the results.
+* @apiParam {Number} [limit=30] Number of plans per page.
+* @apiSuccessExample Response:
+*[
+*    {
+*        "id": "00000000000000000001",
+*        "provider": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "nodes": [
+*            "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd"
+*        ],
+*        "price": [
+*            {
+*                "denom": "sut",
+*                "amount": "100"
+*            }
+*        ],
+*        "duration": "2592000",
+*        "data_cap": "107374182400"
+*    }
+*]
+ */
+func queryPlansHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		params, err := parseQueryPlansParams(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryPlans), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query plans. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /subscription/{subscriptionId} To get a subscription, whether the client may be served and the usage receipts of its dVPN nodes.
+* @apiName getSubscription
+* @apiGroup Sentinel-Tendermint
+* @apiSuccessExample Response:
+*{
+*    "subscription": {
+*        "id": "00000000000000000001",
+*        "plan_id": "00000000000000000001",
+*        "client": "cosmosaccaddr1udkdyy5c4m6gv0tmkmrmw0zdg3t9vmq9mepkyv",
+*        "provider": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*        "start": "1537361017",
+*        "end": "1539953017",
+*        "data_cap": "107374182400",
+*        "used": "48318382080",
+*        ...
+*    },
+*    "active": true,
+*    "usages": [
+*        {
+*            "node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*            "receipt": {...}
+*        }
+*    ]
+*}
+ */
+func querySubscriptionHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+		params := sent.QuerySubscriptionParams{SubscriptionId: vars["subscriptionId"]}
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QuerySubscription), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query subscription. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /master/{address} To get a master node along with its bond.
+* @apiName getMasterNode
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} address Bech32 address of the master node.
+* @apiSuccessExample Response:
+*{
+*    "address": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*    "bond": {
+*        "denom": "sut",
+*        "amount": "1000"
+*    }
+*}
+ */
+func queryMasterNodeHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		addr, err := sdk.AccAddressFromBech32(mux.Vars(r)["address"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		bz, err := cdc.MarshalJSON(sent.QueryNodeParams{Address: addr})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryMasterNode), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query master node. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /proposals To list the sentinel proposals in their voting period along with their current tallies.
+* @apiName getProposals
+* @apiGroup Sentinel-Tendermint
+* @apiSuccessExample Response:
+*[
+*    {
+*        "proposal": {
+*            "id": "00000000000000000001",
+*            "kind": 1,
+*            "proposer": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*            "node": "cosmosaccaddr1udkdyy5c4m6gv0tmkmrmw0zdg3t9vmq9mepkyv",
+*            "params": null,
+*            "fraction": "0/1",
+*            "voting_end": "1537620217",
+*            "votes": [
+*                {
+*                    "master_node": "cosmosaccaddr130q3n8kkpa9flav0sa5lefjunmruhchg5z6pzd",
+*                    "option": 1
+*                }
+*            ]
+*        },
+*        "tally": {
+*            "yes": "1000",
+*            "no": "0",
+*            "total": "3000"
+*        }
+*    }
+*]
+ */
+func queryProposalsHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryProposals), nil)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query proposals. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+/**
+* @api {get} /proposals/{proposalId} To get a sentinel proposal in its voting period along with its current tally.
+* @apiName getProposal
+* @apiGroup Sentinel-Tendermint
+* @apiSuccessExample Response:
+*{
+*    "proposal": {
+*        "id": "00000000000000000001",
+*        "kind": 1,
+*        ...
+*    },
+*    "tally": {
+*        "yes": "1000",
+*        "no": "0",
+*        "total": "3000"
+*    }
+*}
+ */
+func queryProposalHandlerFn(cdc *wire.Codec, ctx context.CoreContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+		params := sent.QueryProposalParams{ProposalId: vars["proposalId"]}
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		res, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, sent.QueryProposal), bz)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("couldn't query proposal. Error: %s", err.Error())))
+			return
+		}
+		w.Write(res)
+	}
+}
+
+func parseQueryPlansParams(r *http.Request) (params sent.QueryPlansParams, err error) {
+	query := r.URL.Query()
+	for name, value := range map[string]*sdk.AccAddress{
+		"provider": &params.Provider,
+		"node":     &params.Node,
+	} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = sdk.AccAddressFromBech32(query.Get(name))
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %s", name, query.Get(name))
+		}
+	}
+
+	for name, value := range map[string]*int{
+		"page":  &params.Page,
+		"limit": &params.Limit,
+	} {
+		if query.Get(name) == "" {
+			continue
+		}
+		*value, err = strconv.Atoi(query.Get(name))
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %s", name, query.Get(name))
+		}
 	}
+	return params, nil
 }
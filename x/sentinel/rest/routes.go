@@ -7,6 +7,10 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// FlagVerifyKeybase - the flag of the REST server allowing /verify to check
+// accounts by the name and password of their key in the keybase of the server
+const FlagVerifyKeybase = "verify-keybase"
+
 func ServiceRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
 
 	r.HandleFunc(
@@ -35,7 +39,7 @@ func ServiceRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
 	).Methods("DELETE")
 
 	r.HandleFunc(
-		"/vpn", // master node deletes service provider
+		"/vpn", // owner
 		deleteVpnHandlerFn(ctx, cdc),
 	).Methods("DELETE")
 	r.HandleFunc(
@@ -50,10 +54,26 @@ func ServiceRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec) {
 		"/vpn/getpayment", // service provider to chain (from kv store)
 		GetVpnPaymentHandlerFn(ctx, cdc),
 	).Methods("POST")
+	r.HandleFunc(
+		"/send-usage-sign", // Off-chain  Tx (client and service provider)
+		SendUsageSignHandlerFn(),
+	).Methods("POST")
+	r.HandleFunc(
+		"/vpn/usage", // service provider or client to chain
+		SubmitUsageReceiptHandlerFn(ctx, cdc),
+	).Methods("POST")
+	r.HandleFunc(
+		"/vpn/heartbeat", // service provider to chain
+		NodeHeartbeatHandlerFn(ctx, cdc),
+	).Methods("POST")
 	r.HandleFunc(
 		"/verify",
 		verifyKeyHandlerFn(ctx, cdc),
 	).Methods("POST")
+	r.HandleFunc(
+		"/broadcast", // txs signed offline
+		BroadcastTxHandlerFn(ctx, cdc),
+	).Methods("POST")
 
 }
 
@@ -63,6 +83,61 @@ func QueryRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, keeper
 		"/session/{sessionId}",
 		querySessionHandlerFn(cdc, ctx, keeper),
 	).Methods("GET")
+
+	r.HandleFunc(
+		"/session/{sessionId}/usage",
+		queryUsageReceiptHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/session/{sessionId}/dispute",
+		queryDisputeHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/vpn/nodes",
+		queryNodesHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/vpn/nodes/{address}/history",
+		queryNodeHistoryHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/vpn/nodes/{address}/sessions",
+		queryNodeSessionsHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/client/{address}/sessions",
+		queryClientSessionsHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/vpn/plans",
+		queryPlansHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/subscription/{subscriptionId}",
+		querySubscriptionHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/master/{address}",
+		queryMasterNodeHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/proposals",
+		queryProposalsHandlerFn(cdc, ctx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/proposals/{proposalId}",
+		queryProposalHandlerFn(cdc, ctx),
+	).Methods("GET")
 }
 
 func RegisterRoutes(ctx context.CoreContext, r *mux.Router, cdc *wire.Codec, keeper sentinel.Keeper) {
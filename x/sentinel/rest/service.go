@@ -1,24 +1,25 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"reflect"
-	"strconv"
-	"strings"
+	"sync"
 
 	ioutill "io/ioutil"
 
+	"github.com/spf13/viper"
+
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/common"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/client/rpc"
 	"github.com/cosmos/cosmos-sdk/crypto/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
-	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
 	"github.com/cosmos/cosmos-sdk/x/sentinel"
 	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
 )
@@ -63,23 +64,26 @@ import (
 * @apiName registerVPN
 * @apiGroup Sentinel-Tendermint
 * @apiParam {String} moniker Node name of VPN service provider.
-* @apiParam {String} ip Ip address of VPN service provider.
+* @apiParam {String[]} endpoints Public endpoints of VPN service provider as host:port/protocol, e.g. ["8.8.8.8:1194/udp", "[2001:db8::1]:443/tcp", "vpn.example.com:1194/udp"].
 * @apiParam {Number} upload_speed Upload Net speed of VPN service.
 * @apiParam {Number} download_speed Download Net speed of VPN service.
-* @apiParam {Number} price_per_gb Price per GB.
+* @apiParam {String} price_per_gb Price per GB, e.g. "10sut".
 * @apiParam {String} enc_method Encryption method.
 * @apiParam {Number} location_latitude  Latitude Location of service provider.
 * @apiParam {Number} location_longitude  Longiude Location of service provider.
 * @apiParam {String} location_city  City Location of service provider.
 * @apiParam {String} location_country  Country Location of service provider.
 * @apiParam {String} node_type  Node type.
+* @apiParam {String} deposit  Deposit locked while the node is registered, e.g. "100sut".
 * @apiParam {String} version version.
 * @apiParam {String} name Account name of service provider.
 * @apiParam {string} password password of account.
 * @apiParam {Number} gas Gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError AccountAlreadyExists VPN service provider already exists
 * @apiError NetSpeedInvalidError Netspeed is Invalid
-* @apiError IpAddressInvalidError IP address is Invalid
+* @apiError IpAddressInvalidError Endpoints are Invalid
 * @apiError Price_per_GBInvalidError Price per GB is Invalid
 * @apiErrorExample AccountAlreadyExists-Response:
 *{
@@ -109,7 +113,7 @@ import (
 *}
 * @apiErrorExample IpAddressInvalidError-Response:
 *{
- * "  invalid Ip address."
+ * "Host of endpoint 192.168.1.1:1194/udp is not a public IP address or DNS name"
 *}
 * @apiErrorExample Price_per_GBInvalidError-Response:
 *{
@@ -132,10 +136,18 @@ import (
 *   "Data": "eyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19",
 *    "Tags": [
 *        {
-*            "key": "dnBuIHJlZ2lzdGVyZWQgYWRkcmVzcw==",
+*            "key": "YWN0aW9u",
+*            "value": "cmVnaXN0ZXItbm9kZQ=="
+*        },
+*        {
+*            "key": "bm9kZQ==",
 *            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        },
+*        {
+*            "key": "YW1vdW50",
+*            "value": "MTAwc3V0"
 *        }
-*		    ]
+*    ]
 *}
 */
 
@@ -153,7 +165,6 @@ func NewResponse(success bool, hash string, height int64, data []byte, tags []co
 func registervpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		var a int64
 		msg := MsgRegisterVpnService{}
 		body, err := ioutill.ReadAll(r.Body)
 		if err != nil {
@@ -180,18 +191,31 @@ func registervpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handler
 			return
 		}
 
-		if !validateIp(msg.Ip) {
+		endpoints, err := senttype.ParseEndpoints(msg.Endpoints)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		if err := sentinel.ValidateEndpoints(endpoints); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("invalid Ip address."))
+			w.Write([]byte(err.Error()))
 			return
-
 		}
-		if reflect.TypeOf(msg.Ppgb) != reflect.TypeOf(a) || msg.Ppgb < 0 {
+		ppgb, err := sdk.ParseCoins(msg.Ppgb)
+		if err != nil || len(ppgb) == 0 || !ppgb.IsPositive() {
 
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(" entered invalid amount of price per Gb"))
 			return
 		}
+		deposit, err := sdk.ParseCoins(msg.Deposit)
+		if err != nil {
+
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" entered invalid deposit"))
+			return
+		}
 		if msg.UploadSpeed <= 0 || msg.DownloadSpeed <= 0 {
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(" entered invalid net speed details"))
@@ -212,42 +236,19 @@ func registervpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handler
 			w.Write([]byte(" Version is required"))
 			return
 		}
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithFromAddressName(msg.Localaccount)
-		addr, err := ctx.GetFromAddress()
-		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
-		}
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
+		addr, err := getSignerAddress(ctx, msg.Localaccount, msg.UnsignedTx)
 		if err != nil {
-			w.Write([]byte("account number error"))
-			w.Write([]byte(string(acc)))
-
-		}
-
-		msg1 := sentinel.NewMsgRegisterVpnService(msg.Moniker, addr, msg.Ip, msg.Ppgb, msg.UploadSpeed, msg.DownloadSpeed, msg.EncMethod, msg.Latitude, msg.Longitude, msg.City, msg.Country, msg.NodeType, msg.Version)
-
-		txBytes, err := ctx.SignAndBuild(msg.Localaccount, msg.Password, []sdk.Msg{msg1}, cdc)
-
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
-
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		msg1 := sentinel.NewMsgRegisterVpnService(msg.Moniker, addr, endpoints, msg.UploadSpeed, msg.DownloadSpeed, ppgb, msg.EncMethod, msg.Latitude, msg.Longitude, msg.City, msg.Country, msg.NodeType, msg.Version, deposit)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Localaccount, msg.Password, msg.Gas, msg1)
 	}
 	return nil
 }
@@ -259,6 +260,9 @@ func registervpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handler
 * @apiParam {String} name  Account name of Master Node.
 * @apiParam {Number} gas Gas value.
 * @apiParam {string} password password of account.
+* @apiParam {String} bond  Bond locked while the account is a master node, weighting its votes on proposals, e.g. "100sut".
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError AccountAlreadyExists Master Node already exists
 * @apiErrorExample AccountAlreadyExists-Response:
 *{
@@ -282,10 +286,18 @@ func registervpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handler
 *    "Data": "eyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19==",
 *    "Tags": [
 *        {
-*             "key": "dnBuIHJlZ2lzdGVyZWQgYWRkcmVzcw==",
-*             "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
-*         }
-*             ]
+*            "key": "YWN0aW9u",
+*            "value": "cmVnaXN0ZXItbWFzdGVyLW5vZGU="
+*        },
+*        {
+*            "key": "bWFzdGVyLW5vZGU=",
+*            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        },
+*        {
+*            "key": "YW1vdW50",
+*            "value": "MTAwc3V0"
+*        }
+*    ]
 * }
 */
 func registermasterdHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
@@ -298,45 +310,31 @@ func registermasterdHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Han
 			return
 		}
 
-		json.Unmarshal(body, &msg)
-		ctx = ctx.WithFromAddressName(msg.Name)
-		ctx = ctx.WithGas(msg.Gas)
-
-		addr, err := ctx.GetFromAddress()
+		err = json.Unmarshal(body, &msg)
 		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
+			return
 		}
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
+		addr, err := getSignerAddress(ctx, msg.Name, msg.UnsignedTx)
 		if err != nil {
-			w.Write([]byte("account number error"))
-			w.Write([]byte(string(acc)))
-
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
+			return
 		}
-
-		msg1 := sentinel.NewMsgRegisterMasterNode(addr)
-
-		txBytes, err := ctx.SignAndBuild(msg.Name, msg.Password, []sdk.Msg{msg1}, cdc)
+		bond, err := sdk.ParseCoin(msg.Bond)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" entered invalid bond"))
 			return
 		}
-
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		msg1 := sentinel.NewMsgRegisterMasterNode(addr, bond)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Name, msg.Password, msg.Gas, msg1)
 	}
 	return nil
 }
@@ -349,6 +347,8 @@ func registermasterdHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Han
 * @apiParam {String} name AccountName of the person who is deleting the VPN node.
 * @apiParam {string} password password of account.
 * @apiParam {Number} gas Gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError AccountNotExists VPN Node not exists
 * @apiErrorExample AccountNotExists-Response:
 *{
@@ -369,12 +369,16 @@ func registermasterdHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Han
  *   "Hash": "32EF9DFB6BC24D3159A8310F1AE438BED479466E",
  *   "Height": 3698,
  *   "Data": "FRTjZrQKAswn4UTeyJ0eXBlIjoic2VudGluZWWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19b1W/Usl/KB3iflg==",
- *   "Tags": [
- *       {
- *           "key": "ZGVsZXRlZCBWcG4gYWRkcmVzcw==",
- *           "value": "42a0CgLMJ+FE29Vv1LJfygd4n5Y="
- *      }
- *  ]
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "ZGVsZXRlLW5vZGU="
+*        },
+*        {
+*            "key": "bm9kZQ==",
+*            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        }
+*    ]
 }
 */
 func deleteVpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
@@ -386,7 +390,12 @@ func deleteVpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 		if err != nil {
 			return
 		}
-		json.Unmarshal(body, &msg)
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
+			return
+		}
 		if msg.Address == "" {
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(" entered invalid address."))
@@ -398,39 +407,19 @@ func deleteVpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 			w.Write([]byte(err.Error()))
 			return
 		}
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithFromAddressName(msg.Name)
-		addr, err := ctx.GetFromAddress()
-		if err != nil {
-			panic(err)
-		}
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
+		addr, err := getSignerAddress(ctx, msg.Name, msg.UnsignedTx)
 		if err != nil {
-			w.Write([]byte("account number error"))
-			w.Write([]byte(string(acc)))
-
-		}
-		msg1 := sentinel.NewMsgDeleteVpnUser(addr, Vaddr)
-		txBytes, err := ctx.SignAndBuild(msg.Name, msg.Password, []sdk.Msg{msg1}, cdc)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
-
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		msg1 := sentinel.NewMsgDeleteVpnUser(addr, Vaddr)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Name, msg.Password, msg.Gas, msg1)
 	}
 	return nil
 }
@@ -443,6 +432,8 @@ func deleteVpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 * @apiParam {String} name AccountName of the person who is deleting the Master node.
 * @apiParam {string} password password of account.
 * @apiParam {Number} gas Gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError AccountNotExists Master Node not exists
 * @apiErrorExample AccountNotExists-Response:
 *{
@@ -463,12 +454,16 @@ func deleteVpnHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
  *   "Hash": "32EF9DFB6BC24D3159A8310F1AE438BED479466E",
  *   "Height": 3698,
  *   "Data": "FRTjZrQKAswn4UeyJ0eXBlIwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19Tb1W/Usl/KB3iflg==",
- *   "Tags": [
- *       {
- *           "key": "ZGVsZXRlZCBWcG4gYWRkcmVzcw==",
- *           "value": "42a0CgLMJ+FE29Vv1LJfygd4n5Y="
- *      }
- *  ]
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "ZGVsZXRlLW1hc3Rlci1ub2Rl"
+*        },
+*        {
+*            "key": "bWFzdGVyLW5vZGU=",
+*            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        }
+*    ]
 }
 */
 func deleteMasterHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
@@ -480,7 +475,12 @@ func deleteMasterHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handle
 		if err != nil {
 			return
 		}
-		json.Unmarshal(body, &msg)
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
+			return
+		}
 		if msg.Address == "" {
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(" entered invalid address."))
@@ -492,59 +492,25 @@ func deleteMasterHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handle
 			w.Write([]byte(err.Error()))
 			return
 		}
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithFromAddressName(msg.Name)
-		addr, err := ctx.GetFromAddress()
+		addr, err := getSignerAddress(ctx, msg.Name, msg.UnsignedTx)
 		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
-		}
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
-		msg1 := sentinel.NewMsgDeleteMasterNode(addr, Maddr)
-		txBytes, err := ctx.SignAndBuild(msg.Name, msg.Password, []sdk.Msg{msg1}, cdc)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		msg1 := sentinel.NewMsgDeleteMasterNode(addr, Maddr)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Name, msg.Password, msg.Gas, msg1)
 	}
 	return nil
 }
 
-func validateIp(host string) bool {
-	parts := strings.Split(host, ".")
-
-	if len(parts) < 4 {
-		return false
-	}
-
-	for _, x := range parts {
-		if i, err := strconv.Atoi(x); err == nil {
-			if i < 0 || i > 255 {
-				return false
-			}
-		} else {
-			return false
-		}
-
-	}
-	return true
-}
-
 /**
-* @api {post} /vpn/pay To Pay for VPN service.
+* @api {post} /vpn/pay To Pay for VPN service. The id of the new session is returned in the Data of the response.
 * @apiName  payVPN service
 * @apiGroup Sentinel-Tendermint
 * @apiParam {String} amount  Amount to pay for vpn service.
@@ -554,6 +520,9 @@ func validateIp(host string) bool {
 * @apiParam {Number} gas Gas value.
 * @apiParam {String} sig_name NewAccountName.
 * @apiParam {String} sig_password NewAccountPassword.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
+* @apiParam {String} pubkey Bech32 account pubkey of the session, instead of sig_name and sig_password. Required for an unsigned tx.
 * @apiError AccountNotExists VPN Node not exists
 * @apiError AccountNameAlreadyExists The new account name is already exist
 * @apiError InsufficientFunds  Insufficient Funds
@@ -583,28 +552,34 @@ func validateIp(host string) bool {
 *   "Success": true,
 *   "Hash": "D2C58CAFC580CC39A4CFAB4325991A9378AFE77D",
 *   "Height": 1196,
-*   "Data": "IjNwWGdHazB5MnBGceyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19TdZdWIwak5xIg==",
-*   "Tags": [
-*      {
-*            "key": "c2VuZGVyIGFkZHJlc3M=",
-*           "value": "Y29zbW9zYWNjYWRkcjE2cG1qd2dxZXBoeHJ0ZjUyZXc3eXcwdXdjNXk1MHdwa2h6NTB0OQ=="
+*   "Data": "MDAwMDAwMDAwMDAwMDAwMDAwMDE=",
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "cGF5LXZwbi1zZXJ2aWNl"
+*        },
+*        {
+*            "key": "c2Vzc2lvbi1pZA==",
+*            "value": "MDAwMDAwMDAwMDAwMDAwMDAwMDE="
 *        },
-*       {
-*            "key": "c2Vlc2lvbiBpZA==",
-*            "value": "YmRiZDk3NTU0OTZlZDhhZDVlNDE="
+*        {
+*            "key": "bm9kZQ==",
+*            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        },
+*        {
+*            "key": "Y2xpZW50",
+*            "value": "Y29zbW9zYWNjYWRkcjE2cG1qd2dxZXBoeHJ0ZjUyZXc3eXcwdXdjNXk1MHdwa2h6NTB0OQ=="
 *        },
 *        {
-*            "key": "VG90YWwgTG9ja2VkIGNvaW5z",
-*            "value": "MTAw"
+*            "key": "YW1vdW50",
+*            "value": "MTAwc3V0"
 *        }
-*
-*          ]
+*    ]
 }
 */
 func PayVpnServiceHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		var kb keys.Keybase
 		msg := MsgPayVpnService{}
 		body, err := ioutill.ReadAll(r.Body)
 		if err != nil {
@@ -614,25 +589,6 @@ func PayVpnServiceHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 		}
 		err = json.Unmarshal(body, &msg)
 
-		kb, err = ckeys.GetKeyBase()
-		if err != nil {
-			w.WriteHeader(500)
-			w.Write([]byte(err.Error()))
-			return
-		}
-
-		seed := getSeed(keys.Secp256k1)
-		if msg.SigName == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(" Enter the Name."))
-			return
-		}
-		if msg.SigPassword == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(" Enter the Password."))
-			return
-		}
-
 		if msg.Coins == "" {
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte("Enter the coins "))
@@ -652,57 +608,84 @@ func PayVpnServiceHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 		}
 		coins, err := sdk.ParseCoins(msg.Coins)
 		if err != nil {
-
-			sdk.ErrInternal("Parse Coins Failed")
-		}
-
-		infos, err := kb.List()
-		for _, i := range infos {
-			if i.GetName() == msg.SigName {
-				w.WriteHeader(http.StatusConflict)
-				w.Write([]byte(fmt.Sprintf("Account with name %s already exists.", msg.SigName)))
-				return
-			}
-		}
-		info, err := kb.CreateKey(msg.SigName, seed, msg.SigPassword)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
-			return
-		}
-		ctx = ctx.WithFromAddressName(msg.Localaccount)
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		addr, err := ctx.GetFromAddress()
-		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" entered invalid amount"))
 			return
 		}
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
-		msg1 := sentinel.NewMsgPayVpnService(coins, vaddr, addr, info.GetPubKey())
-		txBytes, err := ctx.SignAndBuild(msg.Localaccount, msg.Password, []sdk.Msg{msg1}, cdc)
+		addr, err := getSignerAddress(ctx, msg.Localaccount, msg.UnsignedTx)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
 
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		// the session key is given by the client, or created in the keybase
+		var pubKey crypto.PubKey
+		if msg.PubKey != "" {
+			pubKey, err = sdk.GetAccPubKeyBech32(msg.PubKey)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(" entered invalid pubkey"))
+				return
+			}
+		} else {
+			if msg.Unsigned {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(" Enter the pubkey of the session."))
+				return
+			}
+			var ok bool
+			if pubKey, ok = createSessionKey(w, msg.SigName, msg.SigPassword); !ok {
+				return
+			}
+		}
+		msg1 := sentinel.NewMsgPayVpnService(coins, vaddr, addr, pubKey)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Localaccount, msg.Password, msg.Gas, msg1)
 	}
 	return nil
 }
 
+// create the key of a session in the keybase of the service, the errors are
+// written to w
+func createSessionKey(w http.ResponseWriter, name string, password string) (pubKey crypto.PubKey, ok bool) {
+	if name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(" Enter the Name."))
+		return nil, false
+	}
+	if password == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(" Enter the Password."))
+		return nil, false
+	}
+	kb, err := ckeys.GetKeyBase()
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+		return nil, false
+	}
+	infos, err := kb.List()
+	for _, i := range infos {
+		if i.GetName() == name {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(fmt.Sprintf("Account with name %s already exists.", name)))
+			return nil, false
+		}
+	}
+	info, err := kb.CreateKey(name, getSeed(keys.Secp256k1), password)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil, false
+	}
+	return info.GetPubKey(), true
+}
+
 //To create client signature....... This is not a transaction......
 
 /**
@@ -715,6 +698,7 @@ func PayVpnServiceHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 * @apiParam {String} amount Amount to create signature.
 * @apiParam {Number} counter Counter value of the sigature.
 *@apiParam {Boolean} isfial boolean value for is this final signature or not.
+* @apiParam {Boolean} unsigned Return the bytes to sign offline instead of the signature.
 * @apiSuccessExample Response:
 * 10lz2f928xpzsyggqhc9mu80qj59vx0rc6sedxmsfhca8ysuhhtgqypar3h4ty0pgftwqygp6vm54drttw5grlz4p5n238cvzxe2vpxmu6hhnqvt0uxstg7et4vdqhm4v
  */
@@ -734,6 +718,18 @@ func SendSignHandlerFn() http.HandlerFunc {
 			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
 			return
 		}
+		coins, err := sdk.ParseCoins(msg.Coins)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" entered invalid amount"))
+			return
+		}
+
+		bz := senttype.ClientStdSignBytes(coins, []byte(msg.Sessionid), msg.Counter, msg.IsFinal)
+		if msg.Unsigned {
+			w.Write(bz)
+			return
+		}
 		kb, err = ckeys.GetKeyBase()
 		if err != nil {
 			w.WriteHeader(500)
@@ -750,12 +746,6 @@ func SendSignHandlerFn() http.HandlerFunc {
 			w.Write([]byte(" invalid Password."))
 			return
 		}
-		coins, err := sdk.ParseCoins(msg.Coins)
-		if err != nil {
-			sdk.ErrInternal("Parse Coins Failed")
-		}
-
-		bz := senttype.ClientStdSignBytes(coins, []byte(msg.Sessionid), msg.Counter, msg.IsFinal)
 		sign, _, err := kb.Sign(msg.Localaccount, msg.Password, bz)
 		if err != nil {
 			w.Write([]byte(" Signature failed"))
@@ -771,13 +761,15 @@ func SendSignHandlerFn() http.HandlerFunc {
 }
 
 /**
-* @api {post} /refund To Refund the balance of client.
+* @api {post} /refund To close a session of the client, refunding its unreleased balance once the challenge period is over.
 * @apiName  Refund
 * @apiGroup Sentinel-Tendermint
 * @apiParam {String} name AccountName of the client.
 * @apiParam {string} password password of account.
 * @apiParam {String} session_id session-id.
 * @apiParam {Number} gas Gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError TimeInvalidError Time is not more than 24 hours
 * @apiError InvalidSessionIdError SessionId is invalid
 * @apiErrorExample TimeInvalidError-Response:
@@ -813,12 +805,24 @@ func SendSignHandlerFn() http.HandlerFunc {
  *   "Hash": "868B602828FA48F1D4A03D9D066EB42DEC483AA0",
  *   "Height": 1092,
  *   "Data": "Qwi/dQ1h0GcdrppVOeyJ0eXBlIjoic2VudGluZWwvcmVnaXN0yJGc3BlZWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn192hhGfJVl3g=",
- *   "Tags": [
- *{
- *           "key": "Y2xpZW50IFJlZnVuZCBBZGRyZXNzOg==",
- *           "value": "Y29zbW9zYWNjYWRkcjFndnl0N2FnZHY4Z3h3OGR3bmYybms2cnByOGU5dDltY3hkeGV3cA=="
- *       }
- *   ]
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "Y2xvc2Utc2Vzc2lvbg=="
+*        },
+*        {
+*            "key": "c2Vzc2lvbi1pZA==",
+*            "value": "MDAwMDAwMDAwMDAwMDAwMDAwMDE="
+*        },
+*        {
+*            "key": "Y2xpZW50",
+*            "value": "Y29zbW9zYWNjYWRkcjE2cG1qd2dxZXBoeHJ0ZjUyZXc3eXcwdXdjNXk1MHdwa2h6NTB0OQ=="
+*        },
+*        {
+*            "key": "YW1vdW50",
+*            "value": "NjBzdXQ="
+*        }
+*    ]
 *}
 * }
 */
@@ -834,45 +838,31 @@ func RefundHandleFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 		}
 		err = json.Unmarshal(body, &msg)
 		if err != nil {
-			sentinel.ErrUnMarshal("Unmarshal of Given Message Type is failed")
-
-		}
-		ctx = ctx.WithFromAddressName(msg.Name)
-		ctx = ctx.WithGas(msg.Gas)
-		addr, err := ctx.GetFromAddress()
-		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
+			return
 		}
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
-		msg1 := sentinel.NewMsgRefund(addr, []byte(msg.Sessionid))
-		txBytes, err := ctx.SignAndBuild(msg.Name, msg.Password, []sdk.Msg{msg1}, cdc)
+		addr, err := getSignerAddress(ctx, msg.Name, msg.UnsignedTx)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
-
-		res, err := ctx.BroadcastTx(txBytes)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		msg1 := sentinel.NewMsgRefund(addr, []byte(msg.Sessionid))
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Name, msg.Password, msg.Gas, msg1)
 	}
 }
 
 /**
-* @api {post} /vpn/getpayment To get payment of vpn service
+* @api {post} /vpn/getpayment To claim the payment of a session, which may be claimed multiple times with increasing counters. A final signature closes the session.
 * @apiName  GetVPNPayment
 * @apiGroup Sentinel-Tendermint
-* @apiParam {String} amount Amount to send VPN node.
+* @apiParam {String} amount Total amount signed by the client, the node is paid the difference to the previous claim.
 * @apiParam {String} session_id session-id.
 * @apiParam {Number} counter Counter value.
 * @apiParam {String} name Account name of client.
@@ -880,6 +870,8 @@ func RefundHandleFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 * @apiParam {Boolean} isfinal is this final signature or not.
 * @apiParam {string} password password of account.
 * @apiParam {string} sign signature of the client.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 * @apiError InvalidSessionId  SessionId is invalid
 * @apiError SignatureVerificationFailed  Invalid signature
 * @apiErrorExample InvalidSessionId-Response:
@@ -916,16 +908,32 @@ func RefundHandleFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
 *    "Data":"eyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YW9==",
 *    "Tags": [
 *        {
-*            "key": "VnBuIFByb3ZpZGVyIEFkZHJlc3M6",
-*            "value": "Y29zbW9zYWNjYWRkcjF1ZG50Z3pzemVzbjd6M3htNjRoYWZ2amxlZ3JoMzh1a3p3OW03Zw=="
+*            "key": "YWN0aW9u",
+*            "value": "c2Vzc2lvbi1wYXltZW50"
+*        },
+*        {
+*            "key": "c2Vzc2lvbi1pZA==",
+*            "value": "MDAwMDAwMDAwMDAwMDAwMDAwMDE="
+*        },
+*        {
+*            "key": "bm9kZQ==",
+*            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        },
+*        {
+*            "key": "Y2xpZW50",
+*            "value": "Y29zbW9zYWNjYWRkcjE2cG1qd2dxZXBoeHJ0ZjUyZXc3eXcwdXdjNXk1MHdwa2h6NTB0OQ=="
+*        },
+*        {
+*            "key": "YW1vdW50",
+*            "value": "NDBzdXQ="
 *        },
 *        {
-*            "key": "c2Vlc2lvbklk",
-*            "value": "WVZJRW81Y0dIczdkb09UVzRDTk4="
+*            "key": "cmVsZWFzZWQ=",
+*            "value": "NDBzdXQ="
 *        },
 *        {
-*            "key": "VG90YWwgTG9ja2VkIGNvaW5z",
-*            "value": "MTAw"
+*            "key": "c3RhdHVz",
+*            "value": "YWN0aXZl"
 *        }
 *    ]
 *}
@@ -943,8 +951,8 @@ func GetVpnPaymentHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 		}
 		err = json.Unmarshal(body, &msg)
 		if err != nil {
-			sentinel.ErrUnMarshal("UnMarshal of MessageType is failed")
 			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
 			return
 		}
 		if msg.Signature == "" {
@@ -971,7 +979,9 @@ func GetVpnPaymentHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 		}
 		coins, err := sdk.ParseCoins(msg.Coins)
 		if err != nil {
-			sdk.ErrInternal("Parse Coins failed")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" entered invalid amount"))
+			return
 		}
 
 		var sig crypto.Signature
@@ -982,36 +992,240 @@ func GetVpnPaymentHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 			w.Write([]byte("Signature from string conversion failed"))
 		}
 
-		ctx = ctx.WithFromAddressName(msg.Localaccount)
-		ctx = ctx.WithGas(msg.Gas)
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		addr, err := ctx.GetFromAddress()
+		addr, err := getSignerAddress(ctx, msg.Localaccount, msg.UnsignedTx)
 		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte("Invalid Address"))
 			return
 		}
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
 		msg1 := sentinel.NewMsgGetVpnPayment(coins, []byte(msg.Sessionid), msg.Counter, addr, sig, msg.IsFinal)
-		txBytes, err := ctx.SignAndBuild(msg.Localaccount, msg.Password, []sdk.Msg{msg1}, cdc)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Localaccount, msg.Password, msg.Gas, msg1)
+	}
+	return nil
+}
+
+/**
+* @api {post} /send-usage-sign To sign a usage receipt of a session, both the client and the dVPN node sign the same receipt.
+* @apiName  CreateUsageSignature
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} name AccountName of the client or the dVPN node.
+* @apiParam {string} password password of account.
+* @apiParam {String} session_id session-id.
+* @apiParam {Number} counter Counter value of the receipt, shared with the client signatures.
+* @apiParam {Number} upload Bytes uploaded by the client during the whole session.
+* @apiParam {Number} download Bytes downloaded by the client during the whole session.
+* @apiParam {Number} duration Duration of the session in seconds.
+* @apiParam {Boolean} unsigned Return the bytes to sign offline instead of the signature.
+* @apiSuccessExample Response:
+* 10lz2f928xpzsyggqhc9mu80qj59vx0rc6sedxmsfhca8ysuhhtgqypar3h4ty0pgftwqygp6vm54drttw5grlz4p5n238cvzxe2vpxmu6hhnqvt0uxstg7et4vdqhm4v
+ */
+
+func SendUsageSignHandlerFn() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		msg := UsageSignature{}
+		body, err := ioutill.ReadAll(r.Body)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		res, err := ctx.BroadcastTx(txBytes)
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
+			return
+		}
+		bz := senttype.UsageStdSignBytes([]byte(msg.Sessionid), msg.Counter, msg.Upload, msg.Download, msg.Duration)
+		if msg.Unsigned {
+			w.Write(bz)
+			return
+		}
+		if msg.Localaccount == "" || msg.Password == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" invalid Account Name or Password."))
+			return
+		}
+		kb, err := ckeys.GetKeyBase()
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
+		sign, _, err := kb.Sign(msg.Localaccount, msg.Password, bz)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(" Signature failed"))
+			return
+		}
+		s, err := senttype.GetBech32Signature(sign)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("signature marshal failed"))
+			return
+		}
+		w.Write([]byte(s))
+	}
+}
+
+/**
+* @api {post} /vpn/usage To claim the payment of a session for a usage receipt signed by both the client and the dVPN node, priced at the price per GB of the session.
+* @apiName  SubmitUsageReceipt
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} session_id session-id.
+* @apiParam {Number} counter Counter value of the receipt.
+* @apiParam {Number} upload Bytes uploaded by the client during the whole session.
+* @apiParam {Number} download Bytes downloaded by the client during the whole session.
+* @apiParam {Number} duration Duration of the session in seconds.
+* @apiParam {String} client_sign signature of the client.
+* @apiParam {String} node_sign signature of the dVPN node.
+* @apiParam {String} name Account name submitting the receipt.
+* @apiParam {string} password password of account.
+* @apiParam {Number} gas gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
+* @apiError InvalidUsage Usage is less than the usage of the previous receipt
+* @apiErrorExample InvalidUsage-Response:
+*{
+ * checkTx failed: (1245197) Msg 0 failed: === ABCI Log ===
+* Codespace: 19
+* Code:      24
+* ABCICode:  1245208
+* Error:     --= Error =--
+* Data: common.FmtError{format:"Usage is less than the usage of the previous receipt", args:[]interface {}(nil)}
+* Msg Traces:
+* --= /Error =--
+*
+*=== /ABCI Log ===
+*}
+* @apiSuccessExample Response:
+*{
+*    "Success": true,
+*    "Hash": "629F4603A5A4DE598B58DC494CCC38DB9FD96604",
+*    "Height": 353,
+*    "Data":"eyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YW9==",
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "c2Vzc2lvbi11c2FnZQ=="
+*        }
+*    ]
+*}
+*/
+
+func SubmitUsageReceiptHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		msg := MsgSubmitUsageReceipt{}
+		body, err := ioutill.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("UnMarshal of MessageType is failed"))
+			return
+		}
+		clientSig, err := senttype.GetBech64Signature(msg.ClientSignature)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" invalid client signature."))
+			return
+		}
+		nodeSig, err := senttype.GetBech64Signature(msg.NodeSignature)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(" invalid node signature."))
+			return
+		}
+
+		addr, err := getSignerAddress(ctx, msg.Localaccount, msg.UnsignedTx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
+			return
+		}
+		receipt := senttype.NewUsageReceipt([]byte(msg.Sessionid), msg.Counter, msg.Upload, msg.Download, msg.Duration, clientSig, nodeSig)
+		msg1 := sentinel.NewMsgSubmitUsageReceipt(addr, receipt)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Localaccount, msg.Password, msg.Gas, msg1)
+	}
+}
+
+/**
+* @api {post} /vpn/heartbeat To report the liveness of a dVPN node, which is hidden from the node listings once it misses heartbeats for the heartbeat window.
+* @apiName  NodeHeartbeat
+* @apiGroup Sentinel-Tendermint
+* @apiParam {String} status Status of the dVPN node, one of online, busy or maintenance.
+* @apiParam {Number} active_sessions Number of sessions served by the dVPN node.
+* @apiParam {Number} load Percent of the capacity of the dVPN node in use, from 0 to 100.
+* @apiParam {String} name Account name of the dVPN node.
+* @apiParam {string} password password of account.
+* @apiParam {Number} gas gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
+* @apiError AccountNotExists VPN node is not registered
+* @apiErrorExample AccountNotExists-Response:
+*{
+* Address is not registered as VPN node
+*}
+* @apiSuccessExample Response:
+*{
+*    "Success": true,
+*    "Hash": "629F4603A5A4DE598B58DC494CCC38DB9FD96604",
+*    "Height": 353,
+*    "Data":"eyJub2RlIjoiY29zbW9zYWNjYWRkcjFuY3hlbGpjcjN2bTZ5dHd4YXJ5czUiLCJzdGF0dXMiOiJvbmxpbmUifQ==",
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "bm9kZS1oZWFydGJlYXQ="
+*        }
+*    ]
+*}
+*/
+
+func NodeHeartbeatHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		msg := MsgNodeHeartbeat{}
+		body, err := ioutill.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("UnMarshal of MessageType is failed"))
+			return
+		}
+
+		addr, err := getSignerAddress(ctx, msg.Localaccount, msg.UnsignedTx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
+			return
+		}
+		msg1 := sentinel.NewMsgNodeHeartbeat(addr, msg.Status, msg.ActiveSessions, msg.Load)
+		if err := msg1.ValidateBasic(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Localaccount, msg.Password, msg.Gas, msg1)
 	}
-	return nil
 }
 
 /**
@@ -1023,6 +1237,8 @@ func GetVpnPaymentHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 * @apiParam {String} to To address.
 * @apiParam {String} amount Amount to send.
 * @apiParam {Number} gas gas value.
+* @apiParam {Boolean} unsigned Return the StdSignMsg of the tx to sign offline and broadcast to /broadcast, instead of signing with the account name and password.
+* @apiParam {String} signer Address of the account signing the unsigned tx.
 *
 * @apiSuccessExample Response:
 *{
@@ -1032,10 +1248,22 @@ func GetVpnPaymentHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.Handl
 *   "Data": "eyJ0eXBlIjoic2VudGluZWwvcmVnaXN0ZXJ2cG4iLCJ2YWx1ZSI6eyJGc3BlZWQiOiIxMiIsIlBwZ2IiOiIyMyIsIkxvY2F0aW9uIjoiaHlkIn19",
 *    "Tags": [
 *        {
-*            "key": "dnBuIHJlZ2lzdGVyZWQgYWRkcmVzcw==",
+*            "key": "YWN0aW9u",
+*            "value": "c2VuZC10b2tlbnM="
+*        },
+*        {
+*            "key": "c2VuZGVy",
+*            "value": "Y29zbW9zYWNjYWRkcjE2cG1qd2dxZXBoeHJ0ZjUyZXc3eXcwdXdjNXk1MHdwa2h6NTB0OQ=="
+*        },
+*        {
+*            "key": "cmVjaXBpZW50",
 *            "value": "Y29zbW9zYWNjYWRkcjFlZ3RydjdxdGU0NnY2cXEzN3p0YzB2dzRuMmhrejZuempycDVhZQ=="
+*        },
+*        {
+*            "key": "YW1vdW50",
+*            "value": "MTBzdXQ="
 *        }
-*		    ]
+*    ]
 *}
  */
 
@@ -1051,8 +1279,8 @@ func SendTokenHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 		}
 		err = json.Unmarshal(body, &msg)
 		if err != nil {
-			sentinel.ErrUnMarshal("UnMarshal of MessageType is failed")
 			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid  Msg Unmarshal function Request"))
 			return
 		}
 		if msg.Coins == "" {
@@ -1061,43 +1289,25 @@ func SendTokenHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 			return
 		}
 		coins, err := sdk.ParseCoins(msg.Coins)
-		if err != nil {
-			sdk.ErrInternal("Parse Coins failed")
-		}
-		ctx = ctx.WithFromAddressName(msg.Name)
-		ctx = ctx.WithGas(msg.Gas)
-		addr, err := ctx.GetFromAddress()
-		if err != nil {
-			sdk.ErrInvalidAddress("The given Address is Invalid")
-		}
-		to, err := sdk.AccAddressFromBech32(msg.ToAddress)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(err.Error()))
+			w.Write([]byte(" entered invalid amount"))
 			return
 		}
-		ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
-		acc, err := ctx.GetAccountNumber(addr)
-		seq, err := ctx.NextSequence(addr)
-		ctx = ctx.WithSequence(seq)
-		ctx = ctx.WithAccountNumber(acc)
-		msg1 := sentinel.NewMsgSendTokens(addr, coins, to)
-		txBytes, err := ctx.SignAndBuild(msg.Name, msg.Password, []sdk.Msg{msg1}, cdc)
+		addr, err := getSignerAddress(ctx, msg.Name, msg.UnsignedTx)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid Address"))
 			return
 		}
-		res, err := ctx.BroadcastTx(txBytes)
+		to, err := sdk.AccAddressFromBech32(msg.ToAddress)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
-		data, err := json.MarshalIndent(respon, "", " ")
-		w.Write(data)
-
+		msg1 := sentinel.NewMsgSendTokens(addr, coins, to)
+		signAndBroadcast(w, ctx, cdc, msg.UnsignedTx, addr, msg.Name, msg.Password, msg.Gas, msg1)
 	}
 }
 
@@ -1105,9 +1315,12 @@ func SendTokenHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 * @api {post} /verify To verify account.
 * @apiName verifyAccount
 * @apiGroup Sentinel-Tendermint
-* @apiParam {String} name Name Account holder name.
-* @apiParam {String} password Password password for account.
-* @apiParam {String} address Address of the account name.
+* @apiParam {String} address Address of the account.
+* @apiParam {String} pubkey Bech32 account pubkey of the account.
+* @apiParam {Number} height Recent height of the chain, at most 100 blocks old, the signature is bound to.
+* @apiParam {String} signature Signature of the key of the pubkey over the VerifyAccountSignBytes of the chain id, address and height.
+* @apiParam {String} name Name Account holder name, only if the server runs with --verify-keybase.
+* @apiParam {String} password Password password for account, only if the server runs with --verify-keybase.
 *
 * @apiSuccessExample Response:
 *{
@@ -1136,6 +1349,15 @@ func SendTokenHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 *    "Tags": "null",
 *    "error" : "No account with this name"
 *}
+* @apiErrorExample ExpiredSignature-Response:
+*{
+*   "Success": false,
+*   "Hash": "",
+*   "Height": 0,
+*   "Data": "null",
+*    "Tags": "null",
+*    "error" : "Expired signature"
+*}
 * @apiErrorExample InvalidAddress-Response:
 *{
 *   "Success": false,
@@ -1157,8 +1379,21 @@ func verifyKeyHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 			return
 		}
 
-		json.Unmarshal(body, &msg)
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			respon := NewResponse(false, "", 0, nil, nil, "Invalid params")
+			data, _ := json.MarshalIndent(respon, "", " ")
+			w.Write(data)
+			return
+		}
 
+		// the password of a key must not be sent to a public service, the
+		// keybase of the server is only used if its operator opted in
+		if msg.PubKey != "" || !viper.GetBool(FlagVerifyKeybase) {
+			verifyAccountSignature(w, ctx, msg)
+			return
+		}
 		if msg.Address == "" || msg.Name == "" || msg.Password == "" {
 			respon := NewResponse(false, "", 0, nil, nil, "Invalid params")
 			data, _ := json.MarshalIndent(respon, "", " ")
@@ -1205,3 +1440,98 @@ func verifyKeyHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFu
 	}
 	return nil
 }
+
+// number of blocks a signature of /verify is accepted for after the height
+// it is bound to
+const verifyHeightWindow = 100
+
+// signatures of /verify accepted within the window, which are never accepted
+// again, by the height they are bound to
+var (
+	verifiedSignatures   = make(map[string]int64)
+	verifiedSignaturesMu sync.Mutex
+)
+
+// VerifyAccountSignBytes returns the bytes an account signs to verify itself
+// with /verify, binding its address to the chain and to a recent height so
+// that the signature cannot be replayed on another chain nor after the window
+func VerifyAccountSignBytes(chainID string, address sdk.AccAddress, height int64) []byte {
+	bz, err := json.Marshal(struct {
+		Action  string         `json:"action"`
+		ChainID string         `json:"chain_id"`
+		Address sdk.AccAddress `json:"address"`
+		Height  int64          `json:"height"`
+	}{"verify_account", chainID, address, height})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+// verify an account by the signature of its key over its address, bound to a
+// recent height, instead of the password of its key in the keybase of the
+// service
+func verifyAccountSignature(w http.ResponseWriter, ctx context.CoreContext, msg MsgVerifyAccount) {
+	address, err := sdk.AccAddressFromBech32(msg.Address)
+	if err != nil {
+		respon := NewResponse(false, "", 0, nil, nil, "Invalid Address")
+		data, _ := json.MarshalIndent(respon, "", " ")
+		w.Write(data)
+		return
+	}
+	pubKey, err := sdk.GetAccPubKeyBech32(msg.PubKey)
+	if err != nil || !bytes.Equal(pubKey.Address(), address) {
+		respon := NewResponse(false, "", 0, nil, nil, "Invalid pubkey")
+		data, _ := json.MarshalIndent(respon, "", " ")
+		w.Write(data)
+		return
+	}
+	height, err := rpc.GetChainHeight(ctx)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if msg.Height <= 0 || msg.Height > height || height-msg.Height > verifyHeightWindow {
+		respon := NewResponse(false, "", 0, nil, nil, "Expired signature")
+		data, _ := json.MarshalIndent(respon, "", " ")
+		w.Write(data)
+		return
+	}
+	sig, err := senttype.GetBech64Signature(msg.Signature)
+	if err != nil || !pubKey.VerifyBytes(VerifyAccountSignBytes(ctx.ChainID, address, msg.Height), sig) {
+		respon := NewResponse(false, "", 0, nil, nil, "Invalid signature")
+		data, _ := json.MarshalIndent(respon, "", " ")
+		w.Write(data)
+		return
+	}
+	if !useVerifiedSignature(msg.Signature, msg.Height, height) {
+		respon := NewResponse(false, "", 0, nil, nil, "Signature already used")
+		data, _ := json.MarshalIndent(respon, "", " ")
+		w.Write(data)
+		return
+	}
+
+	respon := NewResponse(true, "", 0, nil, nil, "")
+	data, _ := json.MarshalIndent(respon, "", " ")
+	w.Write(data)
+}
+
+// record a signature of /verify bound to height, returning false if it has
+// already been accepted. The signatures which expired by the chain height are
+// forgotten.
+func useVerifiedSignature(signature string, height int64, chainHeight int64) bool {
+	verifiedSignaturesMu.Lock()
+	defer verifiedSignaturesMu.Unlock()
+
+	for sig, h := range verifiedSignatures {
+		if chainHeight-h > verifyHeightWindow {
+			delete(verifiedSignatures, sig)
+		}
+	}
+	if _, ok := verifiedSignatures[signature]; ok {
+		return false
+	}
+	verifiedSignatures[signature] = height
+	return true
+}
@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	ioutill "io/ioutil"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authcmd "github.com/cosmos/cosmos-sdk/x/auth/client/cli"
+)
+
+// UnsignedTx - the fields of a tx request asking for the tx unsigned instead
+// of signed with a key of the keybase of the service. The StdSignMsg of the tx
+// of the signer account is returned, to be signed offline and broadcast as a
+// StdTx to /broadcast, so that no name nor password is sent to the service.
+type UnsignedTx struct {
+	Unsigned bool   `json:"unsigned"`
+	Signer   string `json:"signer"` // bech32 address of the account signing the tx
+}
+
+// BroadcastTxBody - a StdTx signed offline
+type BroadcastTxBody struct {
+	Tx auth.StdTx `json:"tx"`
+}
+
+// get the address of the account signing a tx request, the signer of an
+// unsigned tx or the account of the key of name
+func getSignerAddress(ctx context.CoreContext, name string, unsigned UnsignedTx) (sdk.AccAddress, error) {
+	if unsigned.Unsigned {
+		return sdk.AccAddressFromBech32(unsigned.Signer)
+	}
+	return ctx.WithFromAddressName(name).GetFromAddress()
+}
+
+// complete a tx request of the account addr: write the StdSignMsg of the tx
+// if it is unsigned, or sign it with the key of name and broadcast it
+func signAndBroadcast(w http.ResponseWriter, ctx context.CoreContext, cdc *wire.Codec, unsigned UnsignedTx,
+	addr sdk.AccAddress, name string, password string, gas int64, msg sdk.Msg) {
+
+	ctx = ctx.WithGas(gas)
+	ctx = ctx.WithDecoder(authcmd.GetAccountDecoder(cdc))
+	acc, err := ctx.GetAccountNumber(addr)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	seq, err := ctx.NextSequence(addr)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	ctx = ctx.WithSequence(seq)
+	ctx = ctx.WithAccountNumber(acc)
+
+	if unsigned.Unsigned {
+		signMsg, err := ctx.BuildSignMsg([]sdk.Msg{msg})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		data, err := wire.MarshalJSONIndent(cdc, signMsg)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write(data)
+		return
+	}
+
+	txBytes, err := ctx.SignAndBuild(name, password, []sdk.Msg{msg}, cdc)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	broadcast(w, ctx, txBytes)
+}
+
+// broadcast a signed tx and write the response
+func broadcast(w http.ResponseWriter, ctx context.CoreContext, txBytes []byte) {
+	res, err := ctx.BroadcastTx(txBytes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	writeTxResponse(w, res)
+}
+
+func writeTxResponse(w http.ResponseWriter, res *ctypes.ResultBroadcastTxCommit) {
+	respon := NewResponse(true, res.Hash.String(), res.Height, res.DeliverTx.Data, res.DeliverTx.Tags, "")
+	data, _ := json.MarshalIndent(respon, "", " ")
+	w.Write(data)
+}
+
+/**
+* @api {post} /broadcast To broadcast a tx signed offline, from the StdSignMsg returned by a sentinel tx request with unsigned set.
+* @apiName  BroadcastTx
+* @apiGroup Sentinel-Tendermint
+* @apiParam {Object} tx StdTx with the msgs, fee and memo of the StdSignMsg, and the signature of the signer account over the bytes of the StdSignMsg.
+* @apiError InvalidTx The tx is not a valid StdTx
+* @apiErrorExample InvalidTx-Response:
+*{
+* Invalid StdTx
+*}
+* @apiSuccessExample Response:
+*{
+*    "Success": true,
+*    "Hash": "629F4603A5A4DE598B58DC494CCC38DB9FD96604",
+*    "Height": 353,
+*    "Data": "MDAwMDAwMDAwMDAwMDAwMDAwMDE=",
+*    "Tags": [
+*        {
+*            "key": "YWN0aW9u",
+*            "value": "bm9kZUhlYXJ0YmVhdA=="
+*        }
+*    ]
+*}
+ */
+
+func BroadcastTxHandlerFn(ctx context.CoreContext, cdc *wire.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var msg BroadcastTxBody
+		body, err := ioutill.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		err = cdc.UnmarshalJSON(body, &msg)
+		if err != nil || len(msg.Tx.Msgs) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid StdTx"))
+			return
+		}
+		txBytes, err := cdc.MarshalBinary(msg.Tx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		broadcast(w, ctx, txBytes)
+	}
+}
@@ -5,26 +5,30 @@ import (
 )
 
 type MsgRegisterVpnService struct {
-	Moniker       string `json:"moniker"`
-	Ip            string `json:"ip"`
-	UploadSpeed   int64  `json:"upload_speed"`
-	DownloadSpeed int64  `json:"download_speed"`
-	Ppgb          int64  `json:"price_per_gb"`
-	EncMethod     string `json:"enc_method"`
-	Latitude      int64  `json:"location_latitude"`
-	Longitude     int64  `json:"location_longitude"`
-	City          string `json:"location_city"`
-	Country       string `json:"location_country"`
-	NodeType      string `json:"node_type"`
-	Version       string `json:"version"`
-	Localaccount  string `json:"name"`
-	Password      string `json:"password"`
-	Gas           int64  `json:"gas"`
+	Moniker       string   `json:"moniker"`
+	Endpoints     []string `json:"endpoints"`
+	UploadSpeed   int64    `json:"upload_speed"`
+	DownloadSpeed int64    `json:"download_speed"`
+	Ppgb          string   `json:"price_per_gb"`
+	EncMethod     string   `json:"enc_method"`
+	Latitude      int64    `json:"location_latitude"`
+	Longitude     int64    `json:"location_longitude"`
+	City          string   `json:"location_city"`
+	Country       string   `json:"location_country"`
+	NodeType      string   `json:"node_type"`
+	Version       string   `json:"version"`
+	Deposit       string   `json:"deposit"`
+	Localaccount  string   `json:"name"`
+	Password      string   `json:"password"`
+	Gas           int64    `json:"gas"`
+	UnsignedTx
 }
 type MsgRegisterMasterNode struct {
 	Name     string `json:"name"`
 	Gas      int64  `json:"gas"`
 	Password string `json:"password"`
+	Bond     string `json:"bond"`
+	UnsignedTx
 }
 
 type MsgDeleteVpnUser struct {
@@ -32,12 +36,14 @@ type MsgDeleteVpnUser struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
 	Gas      int64  `json:"gas"`
+	UnsignedTx
 }
 type MsgDeleteMasterNode struct {
 	Address  string `json:"address", omitempty`
 	Name     string `json:"name"`
 	Password string `json:"password"`
 	Gas      int64  `json:"gas"`
+	UnsignedTx
 }
 type MsgPayVpnService struct {
 	Coins        string `json:"amount", omitempty`
@@ -47,6 +53,8 @@ type MsgPayVpnService struct {
 	Gas          int64  `json:"gas"`
 	SigName      string `json:"sig_name"`
 	SigPassword  string `json:"sig_password"`
+	PubKey       string `json:"pubkey"` // bech32 account pubkey of the session, instead of sig_name
+	UnsignedTx
 }
 
 type MsgGetVpnPayment struct {
@@ -58,6 +66,7 @@ type MsgGetVpnPayment struct {
 	IsFinal      bool   `json:"isfinal"`
 	Password     string `json:"password"`
 	Signature    string `json:"sign"`
+	UnsignedTx
 }
 
 type MsgRefund struct {
@@ -65,6 +74,7 @@ type MsgRefund struct {
 	Password  string `json:"password"`
 	Sessionid string `json:"session_id", omitempty`
 	Gas       int64  `json:"gas"`
+	UnsignedTx
 }
 
 type ClientSignature struct {
@@ -74,6 +84,42 @@ type ClientSignature struct {
 	IsFinal      bool   `json:"isfinal"`
 	Localaccount string `json:"name"`
 	Password     string `json:"password"`
+	Unsigned     bool   `json:"unsigned"` // return the bytes to sign instead of the signature
+}
+
+type UsageSignature struct {
+	Sessionid    string `json:"session_id"`
+	Counter      int64  `json:"counter"`
+	Upload       int64  `json:"upload"`
+	Download     int64  `json:"download"`
+	Duration     int64  `json:"duration"`
+	Localaccount string `json:"name"`
+	Password     string `json:"password"`
+	Unsigned     bool   `json:"unsigned"` // return the bytes to sign instead of the signature
+}
+
+type MsgSubmitUsageReceipt struct {
+	Sessionid       string `json:"session_id"`
+	Counter         int64  `json:"counter"`
+	Upload          int64  `json:"upload"`
+	Download        int64  `json:"download"`
+	Duration        int64  `json:"duration"`
+	ClientSignature string `json:"client_sign"`
+	NodeSignature   string `json:"node_sign"`
+	Localaccount    string `json:"name"`
+	Password        string `json:"password"`
+	Gas             int64  `json:"gas"`
+	UnsignedTx
+}
+
+type MsgNodeHeartbeat struct {
+	Status         string `json:"status"`
+	ActiveSessions int64  `json:"active_sessions"`
+	Load           int64  `json:"load"`
+	Localaccount   string `json:"name"`
+	Password       string `json:"password"`
+	Gas            int64  `json:"gas"`
+	UnsignedTx
 }
 
 type Response struct {
@@ -91,10 +137,14 @@ type SendTokens struct {
 	ToAddress string `json:"to"`
 	Coins     string `json:"amount"`
 	Gas       int64  `json:"gas"`
+	UnsignedTx
 }
 
 type MsgVerifyAccount struct {
-	Name     string `json:"name"`
-	Password string `json:"password"`
-	Address  string `json:"address"`
+	Name      string `json:"name"`     // only with FlagVerifyKeybase
+	Password  string `json:"password"` // only with FlagVerifyKeybase
+	Address   string `json:"address"`
+	PubKey    string `json:"pubkey"`    // bech32 account pubkey
+	Height    int64  `json:"height"`    // recent height the signature is bound to
+	Signature string `json:"signature"` // signature of the key over the VerifyAccountSignBytes
 }
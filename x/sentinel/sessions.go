@@ -0,0 +1,106 @@
+package sentinel
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// default and maximum number of sessions returned per page
+const (
+	DefaultSessionsPageLimit = 30
+	MaxSessionsPageLimit     = 100
+)
+
+// names of the statuses of an open session, as tagged and queried
+const (
+	SessionStatusActive   = "active"
+	SessionStatusClosing  = "closing"
+	SessionStatusDisputed = "disputed"
+)
+
+// status of a session, as tagged by the payment handlers
+func sessionStatus(session senttype.Session) string {
+	switch session.Status {
+	case senttype.StatusClosing:
+		return SessionStatusClosing
+	case senttype.StatusClosed:
+		return "settled"
+	case senttype.StatusDisputed:
+		return SessionStatusDisputed
+	}
+	return SessionStatusActive
+}
+
+// SessionInfo - an open session along with its id, parties and the amounts
+// locked, released to the dVPN node and remaining for the client
+type SessionInfo struct {
+	SessionId      string         `json:"session_id"`
+	Client         sdk.AccAddress `json:"client"`
+	Node           sdk.AccAddress `json:"node"`
+	Status         string         `json:"status"`
+	Timestamp      int64          `json:"timestamp"`
+	Counter        int64          `json:"counter"`
+	LockedCoins    sdk.Coins      `json:"locked_coins"`
+	ReleasedCoins  sdk.Coins      `json:"released_coins"`
+	RemainingCoins sdk.Coins      `json:"remaining_coins"`
+}
+
+// NewSessionInfo creates the listing of a session
+func NewSessionInfo(sessionId []byte, session senttype.Session) SessionInfo {
+	return SessionInfo{
+		SessionId:      string(sessionId),
+		Client:         session.CAddress,
+		Node:           session.VpnAddress(),
+		Status:         sessionStatus(session),
+		Timestamp:      session.Timestamp,
+		Counter:        session.Counter,
+		LockedCoins:    session.TotalLockedCoins,
+		ReleasedCoins:  session.ReleasedCoins,
+		RemainingCoins: session.RemainingCoins(),
+	}
+}
+
+// QuerySessionsParams - the client or dVPN node, or both, whose sessions are
+// listed, along with a status filter and pagination
+type QuerySessionsParams struct {
+	Client sdk.AccAddress `json:"client"`
+	Node   sdk.AccAddress `json:"node"`
+	Status string         `json:"status"` // one of the session statuses, any if empty
+	Page   int            `json:"page"`   // 1-based
+	Limit  int            `json:"limit"`  // sessions per page
+}
+
+// check if the status filter of the params is known
+func (params QuerySessionsParams) IsValidStatus() bool {
+	switch params.Status {
+	case "", SessionStatusActive, SessionStatusClosing, SessionStatusDisputed:
+		return true
+	}
+	return false
+}
+
+// GetSessions returns the requested page of the sessions of the client or
+// dVPN node of params, ordered by id, through the session indexes
+func (keeper Keeper) GetSessions(ctx sdk.Context, params QuerySessionsParams) []SessionInfo {
+	sessions := []SessionInfo{}
+	collect := func(sessionId []byte, session senttype.Session) (stop bool) {
+		if len(params.Node) != 0 && !bytes.Equal(params.Node, session.VpnAddress()) {
+			return false
+		}
+		if params.Status != "" && params.Status != sessionStatus(session) {
+			return false
+		}
+		sessions = append(sessions, NewSessionInfo(sessionId, session))
+		return false
+	}
+	if len(params.Client) != 0 {
+		keeper.IterateSessionsByClient(ctx, params.Client, collect)
+	} else {
+		keeper.IterateSessionsByNode(ctx, params.Node, collect)
+	}
+
+	start, end := pageBounds(params.Page, params.Limit, DefaultSessionsPageLimit, MaxSessionsPageLimit, len(sessions))
+	return sessions[start:end]
+}
@@ -0,0 +1,62 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestGetSessions(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	newSession := func(node int, client int, status uint8) senttype.Session {
+		session := senttype.GetNewSessionMap(coins, pks[node], pks[client], addrs[client], 0)
+		session.Status = status
+		return session
+	}
+	sessions := []senttype.Session{
+		newSession(0, 1, senttype.StatusActive),
+		newSession(0, 2, senttype.StatusClosing),
+		newSession(1, 2, senttype.StatusActive),
+		newSession(0, 2, senttype.StatusDisputed),
+	}
+	sessions[0].ReleasedCoins = sdk.Coins{{"sut", sdk.NewInt(4)}}
+	var infos []SessionInfo
+	for i, session := range sessions {
+		keeper.SetSession(ctx, GetSessionId(int64(i+1)), session)
+		infos = append(infos, NewSessionInfo(GetSessionId(int64(i+1)), session))
+	}
+	require.Equal(t, SessionInfo{
+		SessionId:      "00000000000000000001",
+		Client:         addrs[1],
+		Node:           addrs[0],
+		Status:         SessionStatusActive,
+		LockedCoins:    coins,
+		ReleasedCoins:  sdk.Coins{{"sut", sdk.NewInt(4)}},
+		RemainingCoins: sdk.Coins{{"sut", sdk.NewInt(6)}},
+	}, infos[0])
+
+	tests := []struct {
+		params   QuerySessionsParams
+		expected []SessionInfo
+	}{
+		{QuerySessionsParams{Node: addrs[0]}, []SessionInfo{infos[0], infos[1], infos[3]}},
+		{QuerySessionsParams{Client: addrs[2]}, []SessionInfo{infos[1], infos[2], infos[3]}},
+		{QuerySessionsParams{Client: addrs[2], Node: addrs[0]}, []SessionInfo{infos[1], infos[3]}},
+		{QuerySessionsParams{Node: addrs[0], Status: SessionStatusActive}, []SessionInfo{infos[0]}},
+		{QuerySessionsParams{Client: addrs[2], Status: SessionStatusDisputed}, []SessionInfo{infos[3]}},
+		{QuerySessionsParams{Node: addrs[2]}, []SessionInfo{}},
+		{QuerySessionsParams{Node: addrs[0], Page: 2, Limit: 2}, []SessionInfo{infos[3]}},
+		{QuerySessionsParams{Node: addrs[0], Page: 3, Limit: 2}, []SessionInfo{}},
+	}
+
+	for i, tc := range tests {
+		require.True(t, tc.params.IsValidStatus())
+		require.Equal(t, tc.expected, keeper.GetSessions(ctx, tc.params), "test case %d", i)
+	}
+	require.False(t, QuerySessionsParams{Status: "settled"}.IsValidStatus())
+}
@@ -0,0 +1,394 @@
+package sentinel
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// default and maximum number of plans returned per page
+const (
+	DefaultPlansPageLimit = 30
+	MaxPlansPageLimit     = 100
+)
+
+// Plan - a subscription plan published by a dVPN node, or by a master node
+// for the fleet of dVPN nodes it operates, as an alternative to paying per GB
+type Plan struct {
+	Id       string           `json:"id"`
+	Provider sdk.AccAddress   `json:"provider"` // dVPN node or master node paid for the subscriptions
+	Nodes    []sdk.AccAddress `json:"nodes"`    // dVPN nodes serving the subscriptions
+	Price    sdk.Coins        `json:"price"`    // price of a subscription period
+	Duration int64            `json:"duration"` // seconds of a subscription period
+	DataCap  int64            `json:"data_cap"` // bytes of a subscription period, unlimited if zero
+}
+
+// check if a dVPN node serves the plan
+func (plan Plan) HasNode(addr sdk.AccAddress) bool {
+	return containsAddress(plan.Nodes, addr)
+}
+
+// Subscription - a period of a plan bought by a client, whose price is locked
+// until the end of the period, when it is paid to the provider
+type Subscription struct {
+	Id           string           `json:"id"`
+	PlanId       string           `json:"plan_id"`
+	Client       sdk.AccAddress   `json:"client"`
+	ClientPubKey crypto.PubKey    `json:"client_pub_key"` // signs the usage receipts of the subscription
+	Provider     sdk.AccAddress   `json:"provider"`
+	Nodes        []sdk.AccAddress `json:"nodes"`
+	Price        sdk.Coins        `json:"price"`
+	Start        int64            `json:"start"`
+	End          int64            `json:"end"`
+	DataCap      int64            `json:"data_cap"` // unlimited if zero
+	Used         sdk.Int          `json:"used"`     // bytes of the latest usage receipts of all the dVPN nodes
+}
+
+// check if a dVPN node serves the subscription
+func (sub Subscription) HasNode(addr sdk.AccAddress) bool {
+	return containsAddress(sub.Nodes, addr)
+}
+
+// check if the client may still be served at time now: the period is not over
+// and the data cap, if any, is not used up
+func (sub Subscription) IsActive(now int64) bool {
+	return now < sub.End && (sub.DataCap == 0 || sub.Used.LT(sdk.NewInt(sub.DataCap)))
+}
+
+// SubscriptionUsage - the latest usage receipt of a dVPN node for a
+// subscription, whose session id is the id of the subscription
+type SubscriptionUsage struct {
+	Node    sdk.AccAddress        `json:"node"`
+	Receipt senttype.UsageReceipt `json:"receipt"`
+}
+
+// SubscriptionInfo - a subscription along with its usage receipts, and
+// whether the client may be served at the time of the query
+type SubscriptionInfo struct {
+	Subscription Subscription        `json:"subscription"`
+	Active       bool                `json:"active"`
+	Usages       []SubscriptionUsage `json:"usages"`
+}
+
+// QueryPlansParams - the provider or dVPN node of the listed plans, along with
+// pagination, zero values match every plan
+type QueryPlansParams struct {
+	Provider sdk.AccAddress `json:"provider"`
+	Node     sdk.AccAddress `json:"node"`
+	Page     int            `json:"page"`  // 1-based
+	Limit    int            `json:"limit"` // plans per page
+}
+
+// check if a plan satisfies every filter of the params
+func (params QueryPlansParams) Matches(plan Plan) bool {
+	switch {
+	case len(params.Provider) != 0 && !bytes.Equal(params.Provider, plan.Provider):
+		return false
+	case len(params.Node) != 0 && !plan.HasNode(params.Node):
+		return false
+	}
+	return true
+}
+
+func containsAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// get a plan by id
+func (keeper Keeper) GetPlan(ctx sdk.Context, planId []byte) (plan Plan, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetPlanKey(planId))
+	if bz == nil {
+		return plan, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &plan)
+	return plan, true
+}
+
+// set a plan
+func (keeper Keeper) SetPlan(ctx sdk.Context, plan Plan) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(plan)
+	store.Set(GetPlanKey([]byte(plan.Id)), bz)
+}
+
+// iterate through the plans, ordered by id, execute func for each
+func (keeper Keeper) IteratePlans(ctx sdk.Context, fn func(plan Plan) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, PlanKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var plan Plan
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &plan)
+		if fn(plan) {
+			break
+		}
+	}
+}
+
+// GetPlans returns the requested page of the plans matching params, ordered by id
+func (keeper Keeper) GetPlans(ctx sdk.Context, params QueryPlansParams) []Plan {
+	plans := []Plan{}
+	keeper.IteratePlans(ctx, func(plan Plan) (stop bool) {
+		if params.Matches(plan) {
+			plans = append(plans, plan)
+		}
+		return false
+	})
+
+	start, end := pageBounds(params.Page, params.Limit, DefaultPlansPageLimit, MaxPlansPageLimit, len(plans))
+	return plans[start:end]
+}
+
+// get the number of plans published on the chain
+func (keeper Keeper) GetPlanCount(ctx sdk.Context) int64 {
+	return keeper.getCount(ctx, PlanCountKey)
+}
+
+// set the number of plans published on the chain
+func (keeper Keeper) SetPlanCount(ctx sdk.Context, count int64) {
+	keeper.setCount(ctx, PlanCountKey, count)
+}
+
+// get a subscription by id
+func (keeper Keeper) GetSubscription(ctx sdk.Context, subscriptionId []byte) (sub Subscription, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetSubscriptionKey(subscriptionId))
+	if bz == nil {
+		return sub, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &sub)
+	return sub, true
+}
+
+// set a subscription along with its entry in the subscription queue
+func (keeper Keeper) SetSubscription(ctx sdk.Context, sub Subscription) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(sub)
+	store.Set(GetSubscriptionKey([]byte(sub.Id)), bz)
+	store.Set(GetSubscriptionQueueKey(sub.End, []byte(sub.Id)), []byte{})
+}
+
+// remove a subscription along with its queue entry and usage receipts
+func (keeper Keeper) RemoveSubscription(ctx sdk.Context, sub Subscription) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	for _, usage := range keeper.GetSubscriptionUsages(ctx, []byte(sub.Id)) {
+		store.Delete(GetSubscriptionUsageKey([]byte(sub.Id), usage.Node))
+	}
+	store.Delete(GetSubscriptionKey([]byte(sub.Id)))
+	store.Delete(GetSubscriptionQueueKey(sub.End, []byte(sub.Id)))
+}
+
+// iterate through the subscriptions, ordered by id, execute func for each
+func (keeper Keeper) IterateSubscriptions(ctx sdk.Context, fn func(sub Subscription) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, SubscriptionKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var sub Subscription
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &sub)
+		if fn(sub) {
+			break
+		}
+	}
+}
+
+// get the number of subscriptions bought on the chain
+func (keeper Keeper) GetSubscriptionCount(ctx sdk.Context) int64 {
+	return keeper.getCount(ctx, SubscriptionCountKey)
+}
+
+// set the number of subscriptions bought on the chain
+func (keeper Keeper) SetSubscriptionCount(ctx sdk.Context, count int64) {
+	keeper.setCount(ctx, SubscriptionCountKey, count)
+}
+
+// get the latest usage receipt of a dVPN node for a subscription
+func (keeper Keeper) GetSubscriptionUsage(ctx sdk.Context, subscriptionId []byte, nodeAddr sdk.AccAddress) (usage SubscriptionUsage, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetSubscriptionUsageKey(subscriptionId, nodeAddr))
+	if bz == nil {
+		return usage, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &usage)
+	return usage, true
+}
+
+// set the latest usage receipt of a dVPN node for the subscription of its receipt
+func (keeper Keeper) SetSubscriptionUsage(ctx sdk.Context, usage SubscriptionUsage) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(usage)
+	store.Set(GetSubscriptionUsageKey(usage.Receipt.SessionId, usage.Node), bz)
+}
+
+// get the latest usage receipts of the dVPN nodes for a subscription
+func (keeper Keeper) GetSubscriptionUsages(ctx sdk.Context, subscriptionId []byte) (usages []SubscriptionUsage) {
+	keeper.iterateSubscriptionUsages(ctx, GetSubscriptionUsagesKey(subscriptionId), func(usage SubscriptionUsage) (stop bool) {
+		usages = append(usages, usage)
+		return false
+	})
+	return usages
+}
+
+// iterate through the usage receipts of all the subscriptions, execute func for each
+func (keeper Keeper) IterateSubscriptionUsages(ctx sdk.Context, fn func(usage SubscriptionUsage) (stop bool)) {
+	keeper.iterateSubscriptionUsages(ctx, SubscriptionUsageKey, fn)
+}
+
+func (keeper Keeper) iterateSubscriptionUsages(ctx sdk.Context, prefix []byte, fn func(usage SubscriptionUsage) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var usage SubscriptionUsage
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &usage)
+		if fn(usage) {
+			break
+		}
+	}
+}
+
+// get the ids of the subscriptions of the queue whose period ended up to now
+func (keeper Keeper) getEndedSubscriptionIds(ctx sdk.Context, now int64) (ids [][]byte) {
+	if now < 0 {
+		return nil
+	}
+	return keeper.getQueuedSessionIds(ctx, SubscriptionQueueKey, GetSubscriptionQueueTimeKey(now+1))
+}
+
+// AddPlan publishes a subscription plan. A dVPN node publishes plans served by
+// itself, a master node plans served by registered dVPN nodes of its fleet.
+func (keeper Keeper) AddPlan(ctx sdk.Context, msg MsgAddPlan) (Plan, sdk.Error) {
+	nodes := msg.Nodes
+	if _, found := keeper.GetVpnService(ctx, msg.From); found {
+		if len(nodes) > 1 || (len(nodes) == 1 && !bytes.Equal(nodes[0], msg.From)) {
+			return Plan{}, ErrInvalidPlan("VPN nodes may only publish plans served by themselves")
+		}
+		nodes = []sdk.AccAddress{msg.From}
+	} else if keeper.IsMasterNode(ctx, msg.From) {
+		if len(nodes) == 0 {
+			return Plan{}, ErrInvalidPlan("Plans of master nodes must be served by VPN nodes")
+		}
+		for _, node := range nodes {
+			if _, found := keeper.GetVpnService(ctx, node); !found {
+				return Plan{}, ErrInvalidPlan(fmt.Sprintf("%s is not registered as VPN node", node))
+			}
+		}
+	} else {
+		return Plan{}, sdk.ErrUnauthorized("Only VPN nodes and master nodes may publish plans")
+	}
+
+	params := keeper.GetParams(ctx)
+	for _, coin := range msg.Price {
+		if !params.IsAllowedDenom(coin.Denom) {
+			return Plan{}, ErrInvalidDenom(fmt.Sprintf("Payments in %s are not allowed", coin.Denom))
+		}
+	}
+
+	count := keeper.GetPlanCount(ctx) + 1
+	plan := Plan{
+		Id:       string(GetPlanId(count)),
+		Provider: msg.From,
+		Nodes:    nodes,
+		Price:    msg.Price,
+		Duration: msg.Duration,
+		DataCap:  msg.DataCap,
+	}
+	keeper.SetPlanCount(ctx, count)
+	keeper.SetPlan(ctx, plan)
+	return plan, nil
+}
+
+// Subscribe locks the price of a plan from a client for a period of the plan,
+// starting now
+func (keeper Keeper) Subscribe(ctx sdk.Context, msg MsgSubscribe) (Subscription, sdk.Error) {
+	plan, found := keeper.GetPlan(ctx, msg.PlanId)
+	if !found {
+		return Subscription{}, ErrInvalidPlan("No plan found with the id")
+	}
+	_, _, err := keeper.coinKeeper.SubtractCoins(ctx, msg.From, plan.Price)
+	if err != nil {
+		return Subscription{}, sdk.ErrInsufficientCoins("Insufficient funds for the price of the plan")
+	}
+
+	now := ctx.BlockHeader().Time
+	count := keeper.GetSubscriptionCount(ctx) + 1
+	sub := Subscription{
+		Id:           string(GetSubscriptionId(count)),
+		PlanId:       plan.Id,
+		Client:       msg.From,
+		ClientPubKey: msg.Pubkey,
+		Provider:     plan.Provider,
+		Nodes:        plan.Nodes,
+		Price:        plan.Price,
+		Start:        now,
+		End:          now + plan.Duration,
+		DataCap:      plan.DataCap,
+		Used:         sdk.ZeroInt(),
+	}
+	keeper.SetSubscriptionCount(ctx, count)
+	keeper.SetSubscription(ctx, sub)
+	return sub, nil
+}
+
+// SubmitSubscriptionUsage records the usage of a subscription on a dVPN node
+// serving it, reported by a receipt signed by the client. The receipts of a
+// node are cumulative over the period and replace its previous receipt, so
+// that the used bytes of the subscription are the sum of the latest receipts.
+func (keeper Keeper) SubmitSubscriptionUsage(ctx sdk.Context, msg MsgSubmitSubscriptionUsage) (Subscription, sdk.Error) {
+	receipt := msg.Receipt
+	sub, found := keeper.GetSubscription(ctx, receipt.SessionId)
+	if !found {
+		return sub, ErrInvalidSubscription("No subscription found with the id")
+	}
+	if !sub.HasNode(msg.From) {
+		return sub, sdk.ErrUnauthorized("VPN node does not serve the subscription")
+	}
+	if ctx.BlockHeader().Time >= sub.End {
+		return sub, ErrTimeInterval("Period of the subscription is over")
+	}
+	if !sub.ClientPubKey.VerifyBytes(receipt.SignBytes(), receipt.ClientSignature) {
+		return sub, sdk.ErrUnauthorized("client signature verification failed")
+	}
+
+	usedBefore := sdk.ZeroInt()
+	if last, found := keeper.GetSubscriptionUsage(ctx, receipt.SessionId, msg.From); found {
+		if receipt.Counter <= last.Receipt.Counter {
+			return sub, ErrSignMsg("Invalid Counter")
+		}
+		if receipt.Upload < last.Receipt.Upload || receipt.Download < last.Receipt.Download || receipt.Duration < last.Receipt.Duration {
+			return sub, ErrInvalidUsage("Usage is less than the usage of the previous receipt")
+		}
+		usedBefore = last.Receipt.TotalBytes()
+	}
+
+	sub.Used = sub.Used.Add(receipt.TotalBytes().Sub(usedBefore))
+	keeper.SetSubscription(ctx, sub)
+	keeper.SetSubscriptionUsage(ctx, SubscriptionUsage{Node: msg.From, Receipt: receipt})
+	return sub, nil
+}
+
+// SettleSubscription pays the locked price of a subscription to its provider
+// and removes it, at the end of its period
+func (keeper Keeper) SettleSubscription(ctx sdk.Context, subscriptionId []byte) (sub Subscription, err sdk.Error) {
+	sub, found := keeper.GetSubscription(ctx, subscriptionId)
+	if !found {
+		return sub, ErrInvalidSubscription("No subscription found with the id")
+	}
+	if sub.Price.IsPositive() {
+		_, _, err = keeper.coinKeeper.AddCoins(ctx, sub.Provider, sub.Price)
+		if err != nil {
+			return sub, err
+		}
+	}
+	keeper.RemoveSubscription(ctx, sub)
+	return sub, nil
+}
@@ -0,0 +1,165 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestAddPlan(t *testing.T) {
+	ctx, _, keeper := createTestInput(t)
+	price := sdk.Coins{{"sut", sdk.NewInt(100)}}
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	keeper.SetMasterNode(ctx, addrs[1])
+
+	cases := []struct {
+		msg        MsgAddPlan
+		expectPass bool
+	}{
+		{NewMsgAddPlan(addrs[0], nil, price, 3600, 0), true},
+		{NewMsgAddPlan(addrs[0], []sdk.AccAddress{addrs[0]}, price, 3600, 0), true},
+		{NewMsgAddPlan(addrs[0], []sdk.AccAddress{addrs[2]}, price, 3600, 0), false},
+		{NewMsgAddPlan(addrs[0], nil, sdk.Coins{{"abc", sdk.NewInt(1)}}, 3600, 0), false},
+		{NewMsgAddPlan(addrs[1], []sdk.AccAddress{addrs[0]}, price, 3600, 1000), true},
+		{NewMsgAddPlan(addrs[1], nil, price, 3600, 0), false},
+		{NewMsgAddPlan(addrs[1], []sdk.AccAddress{addrs[0], addrs[2]}, price, 3600, 0), false},
+		{NewMsgAddPlan(addrs[2], nil, price, 3600, 0), false},
+	}
+
+	for i, tc := range cases {
+		_, err := keeper.AddPlan(ctx, tc.msg)
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+
+	// plans of dVPN nodes are served by themselves only
+	plans := keeper.GetPlans(ctx, QueryPlansParams{Node: addrs[0]})
+	require.Equal(t, 3, len(plans))
+	require.Equal(t, "00000000000000000001", plans[0].Id)
+	require.Equal(t, []sdk.AccAddress{addrs[0]}, plans[0].Nodes)
+	require.Equal(t, addrs[1], plans[2].Provider)
+	require.Equal(t, 1, len(keeper.GetPlans(ctx, QueryPlansParams{Provider: addrs[1]})))
+	require.Equal(t, 2, len(keeper.GetPlans(ctx, QueryPlansParams{Limit: 2})))
+	require.Empty(t, keeper.GetPlans(ctx, QueryPlansParams{Node: addrs[2]}))
+}
+
+func TestSubscription(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	gb := senttype.BytesPerGb
+	price := sdk.Coins{{"sut", sdk.NewInt(100)}}
+	nodeAddr := sdk.AccAddress(ed25519.GenPrivKey().PubKey().Address())
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	keeper.SetVpnService(ctx, nodeAddr, newTestVpnNode())
+	keeper.SetMasterNode(ctx, addrs[2])
+	plan, err := keeper.AddPlan(ctx, NewMsgAddPlan(addrs[2], []sdk.AccAddress{addrs[0], nodeAddr}, price, 3600, 3*gb))
+	require.Nil(t, err)
+
+	// the price is locked from the client until the end of the period
+	clientKey := ed25519.GenPrivKey()
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	_, err = keeper.Subscribe(ctx, NewMsgSubscribe(addrs[1], []byte("00000000000000000009"), clientKey.PubKey()))
+	require.Equal(t, CodeInvalidPlan, err.Code())
+	sub, err := keeper.Subscribe(ctx, NewMsgSubscribe(addrs[1], []byte(plan.Id), clientKey.PubKey()))
+	require.Nil(t, err)
+	require.Equal(t, int64(4600), sub.End)
+	require.Equal(t, initCoins.Int64()-100, ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64())
+	require.True(t, sub.IsActive(ctx.BlockHeader().Time))
+
+	subId := []byte(sub.Id)
+	submit := func(node sdk.AccAddress, counter int64, upload int64, download int64) (Subscription, sdk.Error) {
+		bz := senttype.UsageStdSignBytes(subId, counter, upload, download, 60*counter)
+		clientSig, signErr := clientKey.Sign(bz)
+		require.Nil(t, signErr)
+		receipt := senttype.NewUsageReceipt(subId, counter, upload, download, 60*counter, clientSig, nil)
+		return keeper.SubmitSubscriptionUsage(ctx, NewMsgSubmitSubscriptionUsage(node, receipt))
+	}
+
+	// the used bytes are the sum of the latest receipts of the dVPN nodes
+	sub, err = submit(addrs[0], 1, 0, gb)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(gb), sub.Used)
+	sub, err = submit(nodeAddr, 1, 0, gb/2)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(3*gb/2), sub.Used)
+	sub, err = submit(addrs[0], 2, 0, 2*gb)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(5*gb/2), sub.Used)
+	require.Equal(t, 2, len(keeper.GetSubscriptionUsages(ctx, subId)))
+
+	_, err = submit(addrs[0], 2, 0, 3*gb)
+	require.Equal(t, CodeSignMsg, err.Code())
+	_, err = submit(addrs[0], 3, 0, gb)
+	require.Equal(t, CodeInvalidUsage, err.Code())
+	_, err = submit(addrs[1], 1, 0, gb)
+	require.Equal(t, sdk.CodeUnauthorized, err.Code())
+	otherSig, _ := ed25519.GenPrivKey().Sign(senttype.UsageStdSignBytes(subId, 3, 0, 3*gb, 180))
+	_, err = keeper.SubmitSubscriptionUsage(ctx, NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt(subId, 3, 0, 3*gb, 180, otherSig, nil)))
+	require.Equal(t, sdk.CodeUnauthorized, err.Code())
+
+	// the client is no longer served once the data cap is used up
+	sub, err = submit(addrs[0], 3, 0, 3*gb)
+	require.Nil(t, err)
+	require.False(t, sub.IsActive(ctx.BlockHeader().Time))
+
+	// nothing is settled before the end of the period
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 4599})
+	EndBlocker(ctx, keeper)
+	_, found := keeper.GetSubscription(ctx, subId)
+	require.True(t, found)
+
+	// the price is paid to the provider at the end of the period
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 4600})
+	_, err = submit(addrs[0], 4, 0, 3*gb)
+	require.Equal(t, CodeTimeInterval, err.Code())
+	tags := EndBlocker(ctx, keeper)
+	require.NotEmpty(t, tags)
+	_, found = keeper.GetSubscription(ctx, subId)
+	require.False(t, found)
+	require.Empty(t, keeper.GetSubscriptionUsages(ctx, subId))
+	require.Equal(t, initCoins.Int64()+100, ck.GetCoins(ctx, addrs[2]).AmountOf("sut").Int64())
+}
+
+func TestMsgSubscriptionValidateBasic(t *testing.T) {
+	price := sdk.Coins{{"sut", sdk.NewInt(100)}}
+	sig, err := ed25519.GenPrivKey().Sign([]byte("receipt"))
+	require.Nil(t, err)
+	cases := []struct {
+		msg        sdk.Msg
+		expectPass bool
+	}{
+		{NewMsgAddPlan(addrs[0], nil, price, 3600, 0), true},
+		{NewMsgAddPlan(addrs[0], []sdk.AccAddress{addrs[1]}, price, 3600, 1000), true},
+		{NewMsgAddPlan(nil, nil, price, 3600, 0), false},
+		{NewMsgAddPlan(addrs[0], []sdk.AccAddress{nil}, price, 3600, 0), false},
+		{NewMsgAddPlan(addrs[0], nil, nil, 3600, 0), false},
+		{NewMsgAddPlan(addrs[0], nil, price, 0, 0), false},
+		{NewMsgAddPlan(addrs[0], nil, price, 3600, -1), false},
+		{NewMsgSubscribe(addrs[1], []byte("00000000000000000001"), pks[1]), true},
+		{NewMsgSubscribe(nil, []byte("00000000000000000001"), pks[1]), false},
+		{NewMsgSubscribe(addrs[1], nil, pks[1]), false},
+		{NewMsgSubscribe(addrs[1], []byte("00000000000000000001"), nil), false},
+		{NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt([]byte("subscription"), 1, 100, 1000, 60, sig, nil)), true},
+		{NewMsgSubmitSubscriptionUsage(nil, senttype.NewUsageReceipt([]byte("subscription"), 1, 100, 1000, 60, sig, nil)), false},
+		{NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt(nil, 1, 100, 1000, 60, sig, nil)), false},
+		{NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt([]byte("subscription"), 0, 100, 1000, 60, sig, nil)), false},
+		{NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt([]byte("subscription"), 1, 100, -1, 60, sig, nil)), false},
+		{NewMsgSubmitSubscriptionUsage(addrs[0], senttype.NewUsageReceipt([]byte("subscription"), 1, 100, 1000, 60, nil, nil)), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
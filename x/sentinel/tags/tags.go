@@ -0,0 +1,63 @@
+// nolint
+package tags
+
+import (
+	"github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	ActionRegisterNode       = []byte("register-node")
+	ActionUpdateNode         = []byte("update-node")
+	ActionDeleteNode         = []byte("delete-node")
+	ActionNodeHeartbeat      = []byte("node-heartbeat")
+	ActionRegisterMasterNode = []byte("register-master-node")
+	ActionDeleteMasterNode   = []byte("delete-master-node")
+	ActionPayVpnService      = []byte("pay-vpn-service")
+	ActionSessionPayment     = []byte("session-payment")
+	ActionSessionUsage       = []byte("session-usage")
+	ActionCloseSession       = []byte("close-session")
+	ActionRateNode           = []byte("rate-node")
+	ActionOpenDispute        = []byte("open-dispute")
+	ActionDisputeVerdict     = []byte("dispute-verdict")
+	ActionAddPlan            = []byte("add-plan")
+	ActionSubscribe          = []byte("subscribe")
+	ActionSubscriptionUsage  = []byte("subscription-usage")
+	ActionSubmitProposal     = []byte("submit-proposal")
+	ActionVote               = []byte("vote")
+	ActionSendTokens         = []byte("send-tokens")
+
+	// end block actions
+	ActionSettleSession      = []byte("settle-session")
+	ActionResolveDispute     = []byte("resolve-dispute")
+	ActionSettleSubscription = []byte("settle-subscription")
+	ActionTallyProposal      = []byte("tally-proposal")
+	ActionDeactivateNode     = []byte("deactivate-node")
+	ActionReturnDeposit      = []byte("return-deposit")
+
+	Action         = types.TagAction
+	Node           = "node"
+	Client         = "client"
+	MasterNode     = "master-node"
+	SessionId      = "session-id"
+	Amount         = "amount" // coins moved by the action
+	Sender         = "sender"
+	Recipient      = "recipient"
+	PricePerGb     = "price-per-gb"
+	Status         = "status"
+	Reactivated    = "reactivated"
+	Released       = "released"
+	Bytes          = "bytes"
+	Deadline       = "deadline"
+	Verdict        = "verdict"
+	PlanId         = "plan-id"
+	Provider       = "provider"
+	SubscriptionId = "subscription-id"
+	End            = "end"
+	Used           = "used"
+	ProposalId     = "proposal-id"
+	Kind           = "kind"
+	Proposer       = "proposer"
+	Result         = "result"
+	Yes            = "yes"
+	No             = "no"
+)
@@ -0,0 +1,98 @@
+package sentinel
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+var (
+	pks = []crypto.PubKey{
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB50"),
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB51"),
+		newPubKey("0B485CFC0EECC619440448436F8FC9DF40566F2369E72400281454CB552AFB52"),
+	}
+	addrs = []sdk.AccAddress{
+		sdk.AccAddress(pks[0].Address()),
+		sdk.AccAddress(pks[1].Address()),
+		sdk.AccAddress(pks[2].Address()),
+	}
+	initCoins sdk.Int = sdk.NewInt(200)
+)
+
+func createTestCodec() *wire.Codec {
+	cdc := wire.NewCodec()
+	sdk.RegisterWire(cdc)
+	auth.RegisterWire(cdc)
+	bank.RegisterWire(cdc)
+	RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+	return cdc
+}
+
+func createTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keySentinel := sdk.NewKVStoreKey("sentinel")
+	keyParams := sdk.NewKVStoreKey("params")
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySentinel, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewTMLogger(os.Stdout))
+	cdc := createTestCodec()
+	accountMapper := auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount)
+	ck := bank.NewKeeper(accountMapper)
+
+	for i, addr := range addrs {
+		acc := accountMapper.NewAccountWithAddress(ctx, addr)
+		err = acc.SetPubKey(pks[i])
+		require.Nil(t, err)
+		accountMapper.SetAccount(ctx, acc)
+		_, _, err = ck.AddCoins(ctx, addr, sdk.Coins{
+			{"sut", initCoins},
+		})
+		require.Nil(t, err)
+	}
+	pk := params.NewKeeper(cdc, keyParams)
+	keeper := NewKeeper(cdc, keySentinel, ck, accountMapper, pk.Subspace(DefaultParamspace, ParamTypeTable()), DefaultCodeSpace)
+	keeper.SetParams(ctx, DefaultParams())
+	return ctx, ck, keeper
+}
+
+func newPubKey(pk string) (res crypto.PubKey) {
+	pkBytes, err := hex.DecodeString(pk)
+	if err != nil {
+		panic(err)
+	}
+	var pkEd ed25519.PubKeyEd25519
+	copy(pkEd[:], pkBytes[:])
+	return pkEd
+}
+
+func newTestEndpoints(host string) []senttype.Endpoint {
+	return []senttype.Endpoint{senttype.NewEndpoint(host, 1194, "udp")}
+}
+
+func newTestVpnNode() senttype.Registervpn {
+	return senttype.NewVpnRegister("node", newTestEndpoints("8.8.8.8"), 1000, 1000, sdk.Coins{{"sut", sdk.NewInt(10)}}, "AES-256-CBC",
+		174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1")
+}
@@ -0,0 +1,609 @@
+package sentinel
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// ModuleInvariants runs all invariants of the sentinel module.
+// Currently runs the supply invariant and the session coins invariant
+func ModuleInvariants(keeper Keeper) mock.Invariant {
+	return func(t *testing.T, app *mock.App, log string) {
+		SupplyInvariant(keeper)(t, app, log)
+		SessionCoinsInvariant(keeper)(t, app, log)
+	}
+}
+
+// SupplyInvariant checks that the coins of the accounts plus the coins held by
+// sentinel, the unreleased session coins, the deposits, the master node bonds
+// and the subscription prices, equal the total supply
+func SupplyInvariant(keeper Keeper) mock.Invariant {
+	return func(t *testing.T, app *mock.App, log string) {
+		ctx := app.NewContext(false, abci.Header{})
+		totalCoins := totalCoins(ctx, app, keeper)
+		require.True(t, totalCoins.IsEqual(app.TotalCoinsSupply),
+			fmt.Sprintf("total coins %s differ from the supply %s\n%s", totalCoins, app.TotalCoinsSupply, log))
+	}
+}
+
+// SessionCoinsInvariant checks that no session has released more coins than
+// its client locked
+func SessionCoinsInvariant(keeper Keeper) mock.Invariant {
+	return func(t *testing.T, app *mock.App, log string) {
+		ctx := app.NewContext(false, abci.Header{})
+		keeper.IterateSessions(ctx, func(sessionId []byte, session senttype.Session) bool {
+			require.True(t, session.TotalLockedCoins.IsGTE(session.ReleasedCoins),
+				fmt.Sprintf("session %s released %s of %s locked coins\n%s", sessionId, session.ReleasedCoins, session.TotalLockedCoins, log))
+			return false
+		})
+	}
+}
+
+// the coins of the accounts plus the coins held by sentinel
+func totalCoins(ctx sdk.Context, app *mock.App, keeper Keeper) sdk.Coins {
+	coins := sdk.Coins{}
+	app.AccountMapper.IterateAccounts(ctx, func(acc auth.Account) bool {
+		coins = coins.Plus(acc.GetCoins())
+		return false
+	})
+	return coins.Plus(moduleCoins(ctx, keeper))
+}
+
+// the coins held by sentinel on behalf of the accounts
+func moduleCoins(ctx sdk.Context, keeper Keeper) sdk.Coins {
+	coins := sdk.Coins{}
+	add := func(held sdk.Coins) {
+		for _, coin := range held {
+			if coin.IsPositive() {
+				coins = coins.Plus(sdk.Coins{coin})
+			}
+		}
+	}
+
+	keeper.IterateSessions(ctx, func(_ []byte, session senttype.Session) bool {
+		add(session.RemainingCoins())
+		return false
+	})
+	keeper.IterateNodeDeposits(ctx, func(_ sdk.AccAddress, deposit sdk.Coins) bool {
+		add(deposit)
+		return false
+	})
+	keeper.IterateUnbondingDeposits(ctx, func(ubd UnbondingDeposit) bool {
+		add(ubd.Coins)
+		return false
+	})
+	keeper.IterateMasterBonds(ctx, func(_ sdk.AccAddress, bond sdk.Coin) bool {
+		add(sdk.Coins{bond})
+		return false
+	})
+	keeper.IterateSubscriptions(ctx, func(sub Subscription) bool {
+		add(sub.Price)
+		return false
+	})
+	return coins
+}
+
+// TestAndRunRegisterVpnService tests and runs the registration of a dVPN node
+// by an account which is not registered yet, with a random deposit.
+func TestAndRunRegisterVpnService(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		key := keys[r.Intn(len(keys))]
+		addr := sdk.AccAddress(key.PubKey().Address())
+		if _, found := keeper.GetVpnService(ctx, addr); found {
+			return "skipping the registration of an already registered dVPN node", nil
+		}
+		initCoins := app.AccountMapper.GetAccount(ctx, addr).GetCoins()
+		deposit := randCoins(r, initCoins)
+		if deposit.IsZero() {
+			return "skipping the registration of a dVPN node without coins for the deposit", nil
+		}
+
+		action = fmt.Sprintf("%s is registering as dVPN node with a deposit of %s", addr, deposit)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		endpoints := []senttype.Endpoint{senttype.NewEndpoint("8.8.8.8", r.Int63n(65535)+1, "udp")}
+		msg := NewMsgRegisterVpnService(fmt.Sprintf("node-%d", r.Intn(1000)), addr, endpoints, r.Int63n(1000)+1, r.Int63n(1000)+1,
+			randPrice(r, keeper.GetParams(ctx)), "AES-256-CBC", 174560, 784850, "Hyderabad", "India", "OpenVPN", "0.0.1", deposit)
+		deliverMsg(t, app, ctx, msg, key, log)
+
+		require.True(t, initCoins.Minus(deposit).IsEqual(app.AccountMapper.GetAccount(ctx, addr).GetCoins()),
+			fmt.Sprintf("dVPN node had an incorrect amount of coins\n%s", log))
+		require.True(t, keeper.GetNodeDeposit(ctx, addr).IsEqual(deposit), log)
+		return action, nil
+	}
+}
+
+// TestAndRunUpdateVpnService tests and runs an update of the moniker and price
+// of a random dVPN node.
+func TestAndRunUpdateVpnService(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		nodes := keeper.GetVpnServices(ctx)
+		if len(nodes) == 0 {
+			return "skipping the update of a dVPN node, no dVPN node is registered", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+		params := keeper.GetParams(ctx)
+
+		// the price may only change once per price change interval
+		price := randPrice(r, params)
+		if last, found := keeper.lastPriceChangeTime(ctx, node.Address); found && ctx.BlockHeader().Time-last < params.PriceChangeInterval {
+			price = node.Node.PricePerGb
+		}
+
+		action = fmt.Sprintf("%s is updating its price to %s", node.Address, price)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		msg := NewMsgUpdateVpnService(node.Address, fmt.Sprintf("node-%d", r.Intn(1000)), nil, 0, 0, price, "", "", "")
+		deliverMsg(t, app, ctx, msg, privKeyOf(keys, node.Address), log)
+
+		vpn, _ := keeper.GetVpnService(ctx, node.Address)
+		require.True(t, vpn.PricePerGb.IsEqual(price), log)
+		return action, nil
+	}
+}
+
+// TestAndRunDeleteVpnService tests and runs the deletion of a random dVPN node
+// by itself, which starts unbonding its deposit.
+func TestAndRunDeleteVpnService(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		nodes := keeper.GetVpnServices(ctx)
+		if len(nodes) == 0 {
+			return "skipping the deletion of a dVPN node, no dVPN node is registered", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+
+		action = fmt.Sprintf("%s is deleting its dVPN node", node.Address)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgDeleteVpnUser(node.Address, node.Address), privKeyOf(keys, node.Address), log)
+
+		_, found := keeper.GetVpnService(ctx, node.Address)
+		require.False(t, found, log)
+		require.True(t, keeper.GetNodeDeposit(ctx, node.Address).IsZero(), log)
+		return action, nil
+	}
+}
+
+// TestAndRunPayVpnService tests and runs the payment of a random amount by a
+// random client for a session on a random dVPN node. The key of the session
+// is the key of the client.
+func TestAndRunPayVpnService(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		nodes := keeper.GetVpnServices(ctx)
+		if len(nodes) == 0 {
+			return "skipping a session payment, no dVPN node is registered", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+		key := keys[r.Intn(len(keys))]
+		addr := sdk.AccAddress(key.PubKey().Address())
+		if bytes.Equal(addr, node.Address) {
+			return "skipping a session payment of a dVPN node to itself", nil
+		}
+
+		// only the denoms priced by the node may be paid
+		initCoins := app.AccountMapper.GetAccount(ctx, addr).GetCoins()
+		payable := sdk.Coins{}
+		for _, coin := range initCoins {
+			if node.Node.PricePerGb.AmountOf(coin.Denom).Sign() > 0 {
+				payable = append(payable, coin)
+			}
+		}
+		coins := randCoins(r, payable)
+		if coins.IsZero() {
+			return "skipping a session payment of a client without coins priced by the dVPN node", nil
+		}
+
+		action = fmt.Sprintf("%s is paying %s for a session on %s", addr, coins, node.Address)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		res := deliverMsg(t, app, ctx, NewMsgPayVpnService(coins, node.Address, addr, key.PubKey()), key, log)
+
+		require.True(t, initCoins.Minus(coins).IsEqual(app.AccountMapper.GetAccount(ctx, addr).GetCoins()),
+			fmt.Sprintf("client had an incorrect amount of coins\n%s", log))
+		session, found := keeper.GetSession(ctx, res.Data)
+		require.True(t, found, log)
+		require.True(t, session.TotalLockedCoins.IsEqual(coins), log)
+		return action, nil
+	}
+}
+
+// TestAndRunGetVpnPayment tests and runs a claim of a random dVPN node for a
+// random part of the unreleased coins of one of its sessions, with a client
+// signature which may be final.
+func TestAndRunGetVpnPayment(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		sessionId, session, found := randSession(r, ctx, keeper, func(session senttype.Session) bool {
+			return session.Status != senttype.StatusDisputed && !session.RemainingCoins().IsZero() && !isChallengeOver(ctx, keeper, session)
+		})
+		if !found {
+			return "skipping a session claim, no session has coins left to claim", nil
+		}
+		nodeAddr := session.VpnAddress()
+		released := randCoins(r, session.RemainingCoins())
+		coins := session.ReleasedCoins.Plus(released)
+		counter := session.Counter + r.Int63n(3) + 1
+		isFinal := r.Intn(4) == 0
+
+		action = fmt.Sprintf("%s is claiming %s of session %s, counter %d, final %t", nodeAddr, coins, sessionId, counter, isFinal)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		clientKey := privKeyOf(keys, session.CAddress)
+		sign, goErr := clientKey.Sign(senttype.ClientStdSignBytes(coins, sessionId, counter, isFinal))
+		require.Nil(t, goErr, log)
+		initCoins := app.AccountMapper.GetAccount(ctx, nodeAddr).GetCoins()
+		msg := NewMsgGetVpnPayment(coins, sessionId, counter, nodeAddr, sign, isFinal)
+		deliverMsg(t, app, ctx, msg, privKeyOf(keys, nodeAddr), log)
+
+		require.True(t, initCoins.Plus(released).IsEqual(app.AccountMapper.GetAccount(ctx, nodeAddr).GetCoins()),
+			fmt.Sprintf("dVPN node had an incorrect amount of coins\n%s", log))
+		return action, nil
+	}
+}
+
+// TestAndRunRefund tests and runs the closing of a random active session,
+// opened for at least the refund timeout, by its client, refunding its
+// unreleased coins once it is settled.
+func TestAndRunRefund(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		refundTimeout := keeper.GetParams(ctx).RefundTimeout
+		sessionId, session, found := randSession(r, ctx, keeper, func(session senttype.Session) bool {
+			return session.Status == senttype.StatusActive && ctx.BlockHeader().Time-session.Timestamp >= refundTimeout
+		})
+		if !found {
+			return "skipping a session refund, no session is active for the refund timeout", nil
+		}
+
+		action = fmt.Sprintf("%s is closing session %s with %s left", session.CAddress, sessionId, session.RemainingCoins())
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgRefund(session.CAddress, sessionId), privKeyOf(keys, session.CAddress), log)
+
+		closing, found := keeper.GetSession(ctx, sessionId)
+		require.True(t, !found || closing.Status == senttype.StatusClosing, log)
+		return action, nil
+	}
+}
+
+// TestAndRunRegisterMasterNode tests and runs the registration of a master
+// node by an account which is not registered yet, with a random bond.
+func TestAndRunRegisterMasterNode(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		key := keys[r.Intn(len(keys))]
+		addr := sdk.AccAddress(key.PubKey().Address())
+		if keeper.IsMasterNode(ctx, addr) {
+			return "skipping the registration of an already registered master node", nil
+		}
+		minBond := keeper.GetParams(ctx).MinMasterBond
+		initCoins := app.AccountMapper.GetAccount(ctx, addr).GetCoins()
+		bonds := randCoins(r, sdk.Coins{{minBond.Denom, initCoins.AmountOf(minBond.Denom)}})
+		if bonds.IsZero() || !bonds[0].IsGTE(minBond) {
+			return "skipping the registration of a master node without coins for the bond", nil
+		}
+		bond := bonds[0]
+
+		action = fmt.Sprintf("%s is registering as master node with a bond of %s", addr, bond)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgRegisterMasterNode(addr, bond), key, log)
+
+		require.True(t, initCoins.Minus(bonds).IsEqual(app.AccountMapper.GetAccount(ctx, addr).GetCoins()),
+			fmt.Sprintf("master node had an incorrect amount of coins\n%s", log))
+		resBond, found := keeper.GetMasterBond(ctx, addr)
+		require.True(t, found && resBond.IsEqual(bond), log)
+		return action, nil
+	}
+}
+
+// TestAndRunOpenDispute tests and runs the opening of a dispute on a random
+// active session, or closing session in its challenge period, by its client
+// or its dVPN node.
+func TestAndRunOpenDispute(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		sessionId, session, found := randSession(r, ctx, keeper, func(session senttype.Session) bool {
+			return session.Status == senttype.StatusActive ||
+				(session.Status == senttype.StatusClosing && !isChallengeOver(ctx, keeper, session))
+		})
+		if !found {
+			return "skipping a dispute, no session may be disputed", nil
+		}
+		opener := session.CAddress
+		if r.Intn(2) == 0 {
+			opener = session.VpnAddress()
+		}
+
+		action = fmt.Sprintf("%s is disputing session %s", opener, sessionId)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgOpenDispute(opener, sessionId), privKeyOf(keys, opener), log)
+
+		disputed, _ := keeper.GetSession(ctx, sessionId)
+		require.Equal(t, senttype.StatusDisputed, disputed.Status, log)
+		dispute, found := keeper.GetDispute(ctx, sessionId)
+		require.True(t, found, log)
+		require.Equal(t, ctx.BlockHeader().Time+keeper.GetParams(ctx).DisputePeriod, dispute.Deadline, log)
+		return action, nil
+	}
+}
+
+// TestAndRunDisputeVerdict tests and runs a random verdict on a random open
+// dispute by a random master node which is not a party of the session and has
+// not judged it yet.
+func TestAndRunDisputeVerdict(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		var disputes []Dispute
+		keeper.IterateDisputes(ctx, func(dispute Dispute) bool {
+			if ctx.BlockHeader().Time < dispute.Deadline {
+				disputes = append(disputes, dispute)
+			}
+			return false
+		})
+		if len(disputes) == 0 {
+			return "skipping a verdict, no dispute is open", nil
+		}
+		dispute := disputes[r.Intn(len(disputes))]
+		session, _ := keeper.GetSession(ctx, dispute.SessionId)
+		judge, found := randMasterNode(r, ctx, keeper, func(addr sdk.AccAddress) bool {
+			return !bytes.Equal(addr, session.CAddress) && !bytes.Equal(addr, session.VpnAddress()) && !dispute.HasVerdict(addr)
+		})
+		if !found {
+			return "skipping a verdict, no master node may judge the dispute", nil
+		}
+		verdict := VerdictForClient
+		if r.Intn(2) == 0 {
+			verdict = VerdictForNode
+		}
+
+		action = fmt.Sprintf("%s is judging the dispute of session %s in favor of the %s", judge, dispute.SessionId, verdictString(verdict))
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgDisputeVerdict(judge, dispute.SessionId, verdict), privKeyOf(keys, judge), log)
+
+		judged, _ := keeper.GetDispute(ctx, dispute.SessionId)
+		require.True(t, judged.HasVerdict(judge), log)
+		return action, nil
+	}
+}
+
+// TestAndRunSubmitSlashProposal tests and runs a proposal of a random master
+// node to slash a random fraction of the deposit of a random dVPN node.
+func TestAndRunSubmitSlashProposal(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		proposer, found := randMasterNode(r, ctx, keeper, func(sdk.AccAddress) bool { return true })
+		if !found {
+			return "skipping a slash proposal, no master node is registered", nil
+		}
+		var nodes []sdk.AccAddress
+		keeper.IterateNodeDeposits(ctx, func(addr sdk.AccAddress, deposit sdk.Coins) bool {
+			if !deposit.IsZero() {
+				nodes = append(nodes, addr)
+			}
+			return false
+		})
+		if len(nodes) == 0 {
+			return "skipping a slash proposal, no dVPN node has a deposit", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+		fraction := sdk.NewRat(r.Int63n(10)+1, 10)
+
+		action = fmt.Sprintf("%s is proposing to slash %s of the deposit of %s", proposer, fraction, node)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		count := keeper.GetProposalCount(ctx)
+		deliverMsg(t, app, ctx, NewMsgSubmitSlashProposal(proposer, node, fraction), privKeyOf(keys, proposer), log)
+
+		proposal, found := keeper.GetProposal(ctx, GetProposalId(count+1))
+		require.True(t, found, log)
+		require.Equal(t, ProposalKindSlashDeposit, proposal.Kind, log)
+		return action, nil
+	}
+}
+
+// TestAndRunVote tests and runs a random vote of a random master node on a
+// random proposal in its voting period.
+func TestAndRunVote(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		var proposals []Proposal
+		keeper.IterateProposals(ctx, func(proposal Proposal) bool {
+			if ctx.BlockHeader().Time < proposal.VotingEnd {
+				proposals = append(proposals, proposal)
+			}
+			return false
+		})
+		if len(proposals) == 0 {
+			return "skipping a vote, no proposal is in its voting period", nil
+		}
+		proposal := proposals[r.Intn(len(proposals))]
+		voter, found := randMasterNode(r, ctx, keeper, func(sdk.AccAddress) bool { return true })
+		if !found {
+			return "skipping a vote, no master node is registered", nil
+		}
+		option := VoteOptionYes
+		if r.Intn(3) == 0 {
+			option = VoteOptionNo
+		}
+
+		action = fmt.Sprintf("%s is voting %d on proposal %s", voter, option, proposal.Id)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		deliverMsg(t, app, ctx, NewMsgVote(voter, []byte(proposal.Id), option), privKeyOf(keys, voter), log)
+		return action, nil
+	}
+}
+
+// TestAndRunAddPlan tests and runs the publication of a subscription plan of a
+// random dVPN node, served by itself, with a random price, period and data cap.
+func TestAndRunAddPlan(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		nodes := keeper.GetVpnServices(ctx)
+		if len(nodes) == 0 {
+			return "skipping a plan, no dVPN node is registered", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+		price := randPrice(r, keeper.GetParams(ctx))
+		duration := r.Int63n(4*app.MaxBlockTime) + 1
+
+		action = fmt.Sprintf("%s is publishing a plan of %s for %d seconds", node.Address, price, duration)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		res := deliverMsg(t, app, ctx, NewMsgAddPlan(node.Address, nil, price, duration, r.Int63n(1000)), privKeyOf(keys, node.Address), log)
+
+		plan, found := keeper.GetPlan(ctx, res.Data)
+		require.True(t, found, log)
+		require.Equal(t, []sdk.AccAddress{node.Address}, plan.Nodes, log)
+		return action, nil
+	}
+}
+
+// TestAndRunSubscribe tests and runs the subscription of a random client to a
+// random plan it can afford.
+func TestAndRunSubscribe(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		var plans []Plan
+		keeper.IteratePlans(ctx, func(plan Plan) bool {
+			plans = append(plans, plan)
+			return false
+		})
+		if len(plans) == 0 {
+			return "skipping a subscription, no plan is published", nil
+		}
+		plan := plans[r.Intn(len(plans))]
+		key := keys[r.Intn(len(keys))]
+		addr := sdk.AccAddress(key.PubKey().Address())
+		initCoins := app.AccountMapper.GetAccount(ctx, addr).GetCoins()
+		if !initCoins.IsGTE(plan.Price) {
+			return "skipping a subscription of a client without coins for the price of the plan", nil
+		}
+
+		action = fmt.Sprintf("%s is subscribing to plan %s for %s", addr, plan.Id, plan.Price)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		res := deliverMsg(t, app, ctx, NewMsgSubscribe(addr, []byte(plan.Id), key.PubKey()), key, log)
+
+		require.True(t, initCoins.Minus(plan.Price).IsEqual(app.AccountMapper.GetAccount(ctx, addr).GetCoins()),
+			fmt.Sprintf("client had an incorrect amount of coins\n%s", log))
+		sub, found := keeper.GetSubscription(ctx, res.Data)
+		require.True(t, found, log)
+		require.Equal(t, ctx.BlockHeader().Time+plan.Duration, sub.End, log)
+		return action, nil
+	}
+}
+
+// TestAndRunNodeHeartbeat tests and runs a heartbeat of a random dVPN node,
+// reactivating it if it missed its heartbeats.
+func TestAndRunNodeHeartbeat(keeper Keeper) mock.TestAndRunTx {
+	return func(t *testing.T, r *rand.Rand, app *mock.App, ctx sdk.Context, keys []crypto.PrivKey, log string) (action string, err sdk.Error) {
+		nodes := keeper.GetVpnServices(ctx)
+		if len(nodes) == 0 {
+			return "skipping a heartbeat, no dVPN node is registered", nil
+		}
+		node := nodes[r.Intn(len(nodes))]
+		statuses := []string{NodeStatusOnline, NodeStatusBusy, NodeStatusMaintenance}
+		status := statuses[r.Intn(len(statuses))]
+
+		action = fmt.Sprintf("%s is sending a heartbeat with status %s", node.Address, status)
+		log = fmt.Sprintf("%s\n%s", log, action)
+
+		msg := NewMsgNodeHeartbeat(node.Address, status, r.Int63n(10), r.Int63n(MaxNodeLoad+1))
+		deliverMsg(t, app, ctx, msg, privKeyOf(keys, node.Address), log)
+
+		nodeStatus, found := keeper.GetNodeStatus(ctx, node.Address)
+		require.True(t, found, log)
+		require.True(t, nodeStatus.Active, log)
+		require.Equal(t, ctx.BlockHeader().Time, nodeStatus.LastHeartbeat, log)
+		return action, nil
+	}
+}
+
+// Delivers a msg signed by key, failing the test if the msg fails
+func deliverMsg(t *testing.T, app *mock.App, ctx sdk.Context, msg sdk.Msg, key crypto.PrivKey, log string) sdk.Result {
+	acc := app.AccountMapper.GetAccount(ctx, sdk.AccAddress(key.PubKey().Address()))
+	tx := mock.GenTx([]sdk.Msg{msg}, []int64{acc.GetAccountNumber()}, []int64{acc.GetSequence()}, key)
+	res := app.Deliver(tx)
+	require.True(t, res.IsOK(), fmt.Sprintf("%v\n%s", res, log))
+	return res
+}
+
+// get a random session of the sessions matching fn
+func randSession(r *rand.Rand, ctx sdk.Context, keeper Keeper, fn func(session senttype.Session) bool) (sessionId []byte, session senttype.Session, found bool) {
+	var ids [][]byte
+	var sessions []senttype.Session
+	keeper.IterateSessions(ctx, func(sessionId []byte, session senttype.Session) bool {
+		if fn(session) {
+			ids = append(ids, sessionId)
+			sessions = append(sessions, session)
+		}
+		return false
+	})
+	if len(ids) == 0 {
+		return nil, session, false
+	}
+	i := r.Intn(len(ids))
+	return ids[i], sessions[i], true
+}
+
+// get a random master node of the master nodes matching fn
+func randMasterNode(r *rand.Rand, ctx sdk.Context, keeper Keeper, fn func(addr sdk.AccAddress) bool) (addr sdk.AccAddress, found bool) {
+	var addrs []sdk.AccAddress
+	keeper.IterateMasterBonds(ctx, func(addr sdk.AccAddress, _ sdk.Coin) bool {
+		if fn(addr) {
+			addrs = append(addrs, addr)
+		}
+		return false
+	})
+	if len(addrs) == 0 {
+		return nil, false
+	}
+	return addrs[r.Intn(len(addrs))], true
+}
+
+// check if the challenge period of a closing session is over, so that it is
+// only waiting to be settled by the EndBlocker
+func isChallengeOver(ctx sdk.Context, keeper Keeper, session senttype.Session) bool {
+	return session.Status == senttype.StatusClosing &&
+		ctx.BlockHeader().Time-session.ClosingTime >= keeper.GetParams(ctx).ChallengePeriod
+}
+
+// a random positive amount, up to its amount, of a random coin of coins, none
+// if no coin is positive
+func randCoins(r *rand.Rand, coins sdk.Coins) sdk.Coins {
+	positive := sdk.Coins{}
+	for _, coin := range coins {
+		if coin.IsPositive() {
+			positive = append(positive, coin)
+		}
+	}
+	if len(positive) == 0 {
+		return nil
+	}
+	coin := positive[r.Intn(len(positive))]
+	amount := sdk.NewIntFromBigInt(new(big.Int).Rand(r, coin.Amount.BigInt())).Add(sdk.OneInt())
+	return sdk.Coins{{coin.Denom, amount}}
+}
+
+// a random price per GB in an allowed denom
+func randPrice(r *rand.Rand, params Params) sdk.Coins {
+	denom := params.AllowedDenoms[r.Intn(len(params.AllowedDenoms))]
+	return sdk.Coins{sdk.NewCoin(denom, r.Int63n(10)+1)}
+}
+
+// the key of an address
+func privKeyOf(keys []crypto.PrivKey, addr sdk.AccAddress) crypto.PrivKey {
+	for _, key := range keys {
+		if bytes.Equal(key.PubKey().Address(), addr) {
+			return key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/sentinel/tags"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// sentinel end block functionality, settles the sessions open for longer than
+// the session timeout, the closing sessions at the end of their challenge
+// period, the disputes at their deadline and the subscriptions at the end of
+// their period, tallies the proposals at the end of their voting period,
+// deactivates the dVPN nodes which missed their heartbeats for the heartbeat
+// window, and returns the deposits and bonds which finished unbonding
+func EndBlocker(ctx sdk.Context, keeper Keeper) (resTags sdk.Tags) {
+	resTags = sdk.NewTags()
+	params := keeper.GetParams(ctx)
+
+	settle := func(sessionId []byte, session senttype.Session) {
+		refund, err := keeper.SettleSession(ctx, sessionId, session)
+		if err != nil {
+			panic(err)
+		}
+		resTags = resTags.AppendTags(sdk.NewTags(
+			tags.Action, tags.ActionSettleSession,
+			tags.SessionId, sessionId,
+			tags.Client, []byte(session.CAddress.String()),
+			tags.Node, []byte(session.VpnAddress().String()),
+			tags.Released, []byte(session.ReleasedCoins.String()),
+			tags.Amount, []byte(refund.String()),
+		))
+	}
+
+	cutoff := ctx.BlockHeader().Time - params.SessionTimeout
+	for _, sessionId := range keeper.getExpiredSessionIds(ctx, cutoff) {
+		session, found := keeper.GetSession(ctx, sessionId)
+		if !found {
+			panic("session queue points to a missing session")
+		}
+		// closing sessions are settled once their challenge period is over,
+		// disputed sessions once their dispute is resolved
+		if session.Status == senttype.StatusClosing || session.Status == senttype.StatusDisputed {
+			continue
+		}
+		settle(sessionId, session)
+	}
+
+	cutoff = ctx.BlockHeader().Time - params.ChallengePeriod
+	for _, sessionId := range keeper.getChallengedSessionIds(ctx, cutoff) {
+		session, found := keeper.GetSession(ctx, sessionId)
+		if !found {
+			panic("session closing queue points to a missing session")
+		}
+		if session.Status == senttype.StatusDisputed {
+			continue
+		}
+		settle(sessionId, session)
+	}
+
+	for _, sessionId := range keeper.getExpiredDisputeIds(ctx, ctx.BlockHeader().Time) {
+		verdict, slashed, err := keeper.ResolveDispute(ctx, sessionId)
+		if err != nil {
+			panic(err)
+		}
+		resTags = resTags.AppendTags(sdk.NewTags(
+			tags.Action, tags.ActionResolveDispute,
+			tags.SessionId, sessionId,
+			tags.Verdict, []byte(verdictString(verdict)),
+			tags.Amount, []byte(slashed.String()),
+		))
+	}
+
+	for _, subscriptionId := range keeper.getEndedSubscriptionIds(ctx, ctx.BlockHeader().Time) {
+		sub, err := keeper.SettleSubscription(ctx, subscriptionId)
+		if err != nil {
+			panic(err)
+		}
+		resTags = resTags.AppendTags(sdk.NewTags(
+			tags.Action, tags.ActionSettleSubscription,
+			tags.SubscriptionId, subscriptionId,
+			tags.Provider, []byte(sub.Provider.String()),
+			tags.Amount, []byte(sub.Price.String()),
+			tags.Used, []byte(sub.Used.String()),
+		))
+	}
+
+	for _, proposalId := range keeper.getEndedProposalIds(ctx, ctx.BlockHeader().Time) {
+		proposal, result, slashed, passed, err := keeper.TallyProposal(ctx, proposalId)
+		if err != nil {
+			panic(err)
+		}
+		outcome := "rejected"
+		if passed {
+			outcome = "passed"
+		}
+		resTags = resTags.AppendTags(sdk.NewTags(
+			tags.Action, tags.ActionTallyProposal,
+			tags.ProposalId, proposalId,
+			tags.Kind, []byte(proposalKindString(proposal.Kind)),
+			tags.Result, []byte(outcome),
+			tags.Yes, []byte(result.Yes.String()),
+			tags.No, []byte(result.No.String()),
+			tags.Amount, []byte(slashed.String()),
+		))
+	}
+
+	// a zero heartbeat window never deactivates nodes
+	if params.HeartbeatWindow > 0 {
+		cutoff = ctx.BlockHeader().Time - params.HeartbeatWindow
+		for _, addr := range keeper.getMissedHeartbeatNodes(ctx, cutoff) {
+			err := keeper.DeactivateNode(ctx, addr)
+			if err != nil {
+				panic(err)
+			}
+			resTags = resTags.AppendTags(sdk.NewTags(
+				tags.Action, tags.ActionDeactivateNode,
+				tags.Node, []byte(addr.String()),
+			))
+		}
+	}
+
+	for _, addr := range keeper.getMatureUnbondingDeposits(ctx, ctx.BlockHeader().Time) {
+		returned, err := keeper.CompleteUnbondingDeposit(ctx, addr)
+		if err != nil {
+			panic(err)
+		}
+		resTags = resTags.AppendTags(sdk.NewTags(
+			tags.Action, tags.ActionReturnDeposit,
+			tags.Node, []byte(addr.String()),
+			tags.Amount, []byte(returned.String()),
+		))
+	}
+	return resTags
+}
+
+// get the ids of the sessions of the queue with a timestamp up to cutoff
+func (keeper Keeper) getExpiredSessionIds(ctx sdk.Context, cutoff int64) (ids [][]byte) {
+	if cutoff < 0 {
+		return nil
+	}
+	return keeper.getQueuedSessionIds(ctx, SessionQueueKey, GetSessionQueueTimeKey(cutoff+1))
+}
+
+// get the ids of the sessions of the closing queue with a closing time up to cutoff
+func (keeper Keeper) getChallengedSessionIds(ctx sdk.Context, cutoff int64) (ids [][]byte) {
+	if cutoff < 0 {
+		return nil
+	}
+	return keeper.getQueuedSessionIds(ctx, SessionClosingQueueKey, GetSessionClosingQueueTimeKey(cutoff+1))
+}
+
+// get the ids of the sessions of a queue between the start and end keys
+func (keeper Keeper) getQueuedSessionIds(ctx sdk.Context, start []byte, end []byte) (ids [][]byte) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := store.Iterator(start, end)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		_, sessionId := GetSessionFromQueueKey(iterator.Key())
+		ids = append(ids, sessionId)
+	}
+	return ids
+}
+
+// SettleSession closes a session, refunding the coins which were not released
+// to the client, and counts it in the reputation of its dVPN node. The node
+// has already been paid the released coins, the amount of the last claim.
+func (keeper Keeper) SettleSession(ctx sdk.Context, sessionId []byte, session senttype.Session) (refund sdk.Coins, err sdk.Error) {
+	refund = session.RemainingCoins()
+	if refund.IsPositive() {
+		_, _, err = keeper.coinKeeper.AddCoins(ctx, session.CAddress, refund)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keeper.recordSettledSession(ctx, sessionId, session)
+	keeper.DeleteSession(ctx, sessionId)
+	return refund, nil
+}
@@ -0,0 +1,48 @@
+package sentinel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestEndBlockerSettlesExpiredSessions(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	keeper.SetVpnService(ctx, addrs[0], newTestVpnNode())
+	timeout := keeper.GetParams(ctx).SessionTimeout
+
+	// open a session at time 1000 and another at time 2000
+	coins := sdk.Coins{{"sut", sdk.NewInt(10)}}
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	id1, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[1], pks[1]))
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 2000})
+	id2, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins, addrs[0], addrs[2], pks[2]))
+	require.Nil(t, err)
+	require.Equal(t, initCoins.Int64()-10, ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64())
+
+	// nothing has expired yet
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + timeout - 1})
+	tags := EndBlocker(ctx, keeper)
+	require.Empty(t, tags)
+
+	// only the first session has expired
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000 + timeout})
+	tags = EndBlocker(ctx, keeper)
+	require.NotEmpty(t, tags)
+	_, found := keeper.GetSession(ctx, []byte(id1))
+	require.False(t, found)
+	_, found = keeper.GetSession(ctx, []byte(id2))
+	require.True(t, found)
+	require.Equal(t, initCoins.Int64(), ck.GetCoins(ctx, addrs[1]).AmountOf("sut").Int64())
+	require.Empty(t, keeper.getExpiredSessionIds(ctx, ctx.BlockHeader().Time-timeout))
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 2000 + timeout})
+	EndBlocker(ctx, keeper)
+	_, found = keeper.GetSession(ctx, []byte(id2))
+	require.False(t, found)
+	require.Equal(t, initCoins.Int64(), ck.GetCoins(ctx, addrs[2]).AmountOf("sut").Int64())
+}
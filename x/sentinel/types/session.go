@@ -16,8 +16,28 @@ type Session struct {
 	CPubKey          crypto.PubKey
 	CAddress         sdk.AccAddress
 	Status           uint8
+	ClosingTime      int64     // time at which the session started closing, settled once the challenge period is over
+	PricePerGb       sdk.Coins // price of the dVPN node when the session was opened, paid for usage receipts
+	CloseReason      uint8     // how the session was closed, counted in the reputation of the dVPN node
 }
 
+// Status of the Session
+const (
+	StatusClosed   uint8 = 0
+	StatusActive   uint8 = 1
+	StatusClosing  uint8 = 2 // waiting for the challenge period to be settled
+	StatusDisputed uint8 = 3 // funds frozen until the dispute is resolved by the master nodes
+)
+
+// Reason a Session was closed
+const (
+	CloseReasonTimeout          uint8 = 0 // settled at the session timeout without being closed
+	CloseReasonFinal            uint8 = 1 // closed by a final client signature
+	CloseReasonRefund           uint8 = 2 // closed by a refund request of the client
+	CloseReasonDisputeForClient uint8 = 3 // settled by a verdict of the master nodes for the client on a dispute
+	CloseReasonDisputeForNode   uint8 = 4 // settled by a verdict of the master nodes for the dVPN node on a dispute
+)
+
 func GetNewSessionMap(coins sdk.Coins, vpnpub crypto.PubKey, cpub crypto.PubKey, caddr sdk.AccAddress, time int64) Session {
 	return Session{
 		TotalLockedCoins: coins,
@@ -26,14 +46,17 @@ func GetNewSessionMap(coins sdk.Coins, vpnpub crypto.PubKey, cpub crypto.PubKey,
 		CPubKey:          cpub,
 		Timestamp:        time,
 		CAddress:         caddr,
-		Status:           1,
+		Status:           StatusActive,
 	}
 
 }
 
-/*Status of the Session
-1 : for active session
-0: for closed session
-
+// address of the dVPN node serving the session
+func (s Session) VpnAddress() sdk.AccAddress {
+	return sdk.AccAddress(s.VpnPubKey.Address())
+}
 
-*/
+// coins locked in the session which were not released to the dVPN node
+func (s Session) RemainingCoins() sdk.Coins {
+	return s.TotalLockedCoins.Minus(s.ReleasedCoins)
+}
@@ -60,11 +60,11 @@ type Vpnsign struct {
 	From     sdk.AccAddress
 	Ip       string
 	Netspeed int64
-	Ppgb     int64
+	Ppgb     sdk.Coins
 	Location string
 }
 
-func GetVPNSignature(address sdk.AccAddress, ip string, ppgb int64, netspeed int64, location string) []byte {
+func GetVPNSignature(address sdk.AccAddress, ip string, ppgb sdk.Coins, netspeed int64, location string) []byte {
 	bz, err := json.Marshal(Vpnsign{
 		From:     address,
 		Ip:       ip,
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	// number of bytes in a GB of bandwidth, the unit dVPN nodes are priced in
+	BytesPerGb int64 = 1000000000
+
+	// maximum bytes uploaded or downloaded, and seconds, of a usage receipt,
+	// so that the total bytes of a receipt fit in an int64
+	MaxUsage int64 = math.MaxInt64 / 2
+)
+
+// UsageReceipt - the bandwidth used in a session up to a counter, signed by
+// both the client and the dVPN node. Receipts are cumulative, each one covers
+// the whole usage of the session.
+type UsageReceipt struct {
+	SessionId       []byte           `json:"session_id"`
+	Counter         int64            `json:"counter"`
+	Upload          int64            `json:"upload"`   // bytes uploaded by the client
+	Download        int64            `json:"download"` // bytes downloaded by the client
+	Duration        int64            `json:"duration"` // seconds
+	ClientSignature crypto.Signature `json:"client_signature"`
+	NodeSignature   crypto.Signature `json:"node_signature"`
+}
+
+func NewUsageReceipt(sessionId []byte, counter int64, upload int64, download int64, duration int64,
+	clientSig crypto.Signature, nodeSig crypto.Signature) UsageReceipt {
+
+	return UsageReceipt{
+		SessionId:       sessionId,
+		Counter:         counter,
+		Upload:          upload,
+		Download:        download,
+		Duration:        duration,
+		ClientSignature: clientSig,
+		NodeSignature:   nodeSig,
+	}
+}
+
+// bytes signed by both the client and the dVPN node
+func (r UsageReceipt) SignBytes() []byte {
+	return UsageStdSignBytes(r.SessionId, r.Counter, r.Upload, r.Download, r.Duration)
+}
+
+// check if the usage of the receipt is neither negative nor above MaxUsage
+func (r UsageReceipt) IsValidUsage() bool {
+	for _, usage := range []int64{r.Upload, r.Download, r.Duration} {
+		if usage < 0 || usage > MaxUsage {
+			return false
+		}
+	}
+	return true
+}
+
+// bytes transferred in both directions
+func (r UsageReceipt) TotalBytes() sdk.Int {
+	return sdk.NewInt(r.Upload).Add(sdk.NewInt(r.Download))
+}
+
+type StdUsage struct {
+	Sessionid []byte
+	Counter   int64
+	Upload    int64
+	Download  int64
+	Duration  int64
+}
+
+func UsageStdSignBytes(sessionid []byte, counter int64, upload int64, download int64, duration int64) []byte {
+	bz, err := json.Marshal(StdUsage{
+		Sessionid: sessionid,
+		Counter:   counter,
+		Upload:    upload,
+		Download:  download,
+		Duration:  duration,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+// UsagePayment returns the coins paying for bytes of bandwidth at pricePerGb.
+// The bandwidth is paid from the locked coins in order of denom, each denom
+// paying for the bandwidth the previous ones could not. The payment is
+// rounded down and capped at the locked coins.
+func UsagePayment(locked sdk.Coins, pricePerGb sdk.Coins, bytes sdk.Int) (payment sdk.Coins) {
+	bytesPerGb := sdk.NewInt(BytesPerGb)
+	for _, coin := range locked {
+		price := pricePerGb.AmountOf(coin.Denom)
+		if bytes.Sign() <= 0 {
+			break
+		}
+		if price.Sign() <= 0 {
+			continue
+		}
+		cost := bytes.Mul(price).Div(bytesPerGb)
+		if !cost.GT(coin.Amount) {
+			if cost.Sign() > 0 {
+				payment = append(payment, sdk.Coin{Denom: coin.Denom, Amount: cost})
+			}
+			break
+		}
+		payment = append(payment, coin)
+		bytes = bytes.Sub(coin.Amount.Mul(bytesPerGb).Div(price))
+	}
+	return payment
+}
@@ -1,16 +1,76 @@
 package types
 
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 type Registervpn struct {
 	Moniker    string
-	Ip         string
+	Endpoints  []Endpoint
 	NetSpeed   NetSpeed
-	PricePerGb int64
+	PricePerGb sdk.Coins
 	EncMethod  string
 	Location   Location
 	NodeType   string
 	Version    string
 }
 
+// Endpoint - an address at which a dVPN node accepts connections
+type Endpoint struct {
+	Host     string // IPv4 or IPv6 address, or DNS name
+	Port     int64
+	Protocol string // "tcp" or "udp"
+}
+
+func NewEndpoint(host string, port int64, protocol string) Endpoint {
+	return Endpoint{
+		Host:     host,
+		Port:     port,
+		Protocol: protocol,
+	}
+}
+
+// ParseEndpoint parses an endpoint written as host:port/protocol, with IPv6
+// hosts in brackets, e.g. [2001:db8::1]:51820/udp
+func ParseEndpoint(str string) (endpoint Endpoint, err error) {
+	slash := strings.LastIndex(str, "/")
+	if slash < 0 {
+		return endpoint, fmt.Errorf("endpoint %s has no protocol, expected host:port/protocol", str)
+	}
+	host, port, err := net.SplitHostPort(str[:slash])
+	if err != nil {
+		return endpoint, err
+	}
+	endpoint.Port, err = strconv.ParseInt(port, 10, 64)
+	if err != nil {
+		return endpoint, fmt.Errorf("endpoint %s has an invalid port", str)
+	}
+	endpoint.Host = host
+	endpoint.Protocol = strings.ToLower(str[slash+1:])
+	return endpoint, nil
+}
+
+// ParseEndpoints parses a list of endpoints, see ParseEndpoint
+func ParseEndpoints(strs []string) (endpoints []Endpoint, err error) {
+	for _, str := range strs {
+		endpoint, err := ParseEndpoint(str)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+func (endpoint Endpoint) String() string {
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(endpoint.Host, strconv.FormatInt(endpoint.Port, 10)), endpoint.Protocol)
+}
+
 type NetSpeed struct {
 	UploadSpeed   int64
 	DownloadSpeed int64
@@ -22,10 +82,10 @@ type Location struct {
 	Country   string
 }
 
-func NewVpnRegister(moniker, ip string, upload int64, download int64, ppgb int64, method string, latitude int64, long int64, city string, country string, nodetype string, version string) Registervpn {
+func NewVpnRegister(moniker string, endpoints []Endpoint, upload int64, download int64, ppgb sdk.Coins, method string, latitude int64, long int64, city string, country string, nodetype string, version string) Registervpn {
 	return Registervpn{
-		Moniker: moniker,
-		Ip:      ip,
+		Moniker:   moniker,
+		Endpoints: endpoints,
 		NetSpeed: NetSpeed{
 			UploadSpeed:   upload,
 			DownloadSpeed: download,
@@ -0,0 +1,77 @@
+package sentinel
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+// get the latest usage receipt of a session
+func (keeper Keeper) GetUsageReceipt(ctx sdk.Context, sessionId []byte) (receipt senttype.UsageReceipt, found bool) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := store.Get(GetUsageReceiptKey(sessionId))
+	if bz == nil {
+		return receipt, false
+	}
+	keeper.cdc.MustUnmarshalBinary(bz, &receipt)
+	return receipt, true
+}
+
+// set the latest usage receipt of a session
+func (keeper Keeper) SetUsageReceipt(ctx sdk.Context, receipt senttype.UsageReceipt) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	bz := keeper.cdc.MustMarshalBinary(receipt)
+	store.Set(GetUsageReceiptKey(receipt.SessionId), bz)
+}
+
+// iterate through the latest usage receipts of the sessions, execute func for each
+func (keeper Keeper) IterateUsageReceipts(ctx sdk.Context, fn func(receipt senttype.UsageReceipt) (stop bool)) {
+	store := ctx.KVStore(keeper.sentStoreKey)
+	iterator := sdk.KVStorePrefixIterator(store, UsageReceiptKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var receipt senttype.UsageReceipt
+		keeper.cdc.MustUnmarshalBinary(iterator.Value(), &receipt)
+		if fn(receipt) {
+			break
+		}
+	}
+}
+
+// SubmitUsageReceipt pays the dVPN node of a session for the bandwidth of a
+// usage receipt signed by both the client and the node, at the price per GB
+// the session was opened with, less the coins already released. The receipt
+// shares the counter of the client signatures and may not report less usage
+// than the previous receipt of the session, which it replaces.
+func (keeper Keeper) SubmitUsageReceipt(ctx sdk.Context, msg MsgSubmitUsageReceipt) (paid sdk.Coins, session senttype.Session, err sdk.Error) {
+	receipt := msg.Receipt
+	session, found := keeper.GetSession(ctx, receipt.SessionId)
+	if !found {
+		return nil, session, ErrInvalidSessionid("Invalid session Id")
+	}
+	signBytes := receipt.SignBytes()
+	if !session.CPubKey.VerifyBytes(signBytes, receipt.ClientSignature) {
+		return nil, session, sdk.ErrUnauthorized("client signature verification failed")
+	}
+	if !session.VpnPubKey.VerifyBytes(signBytes, receipt.NodeSignature) {
+		return nil, session, sdk.ErrUnauthorized("node signature verification failed")
+	}
+	if last, found := keeper.GetUsageReceipt(ctx, receipt.SessionId); found {
+		if receipt.Upload < last.Upload || receipt.Download < last.Download || receipt.Duration < last.Duration {
+			return nil, session, ErrInvalidUsage("Usage is less than the usage of the previous receipt")
+		}
+	}
+
+	pricePerGb := session.PricePerGb
+	if pricePerGb.IsZero() {
+		// sessions opened before their price was recorded pay the current price
+		vpn, _ := keeper.GetVpnService(ctx, session.VpnAddress())
+		pricePerGb = vpn.PricePerGb
+	}
+	amount := senttype.UsagePayment(session.TotalLockedCoins, pricePerGb, receipt.TotalBytes())
+	paid, session, err = keeper.releaseSessionCoins(ctx, receipt.SessionId, session, receipt.Counter, amount)
+	if err != nil {
+		return nil, session, err
+	}
+	keeper.SetUsageReceipt(ctx, receipt)
+	return paid, session, nil
+}
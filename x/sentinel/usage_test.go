@@ -0,0 +1,135 @@
+package sentinel
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	senttype "github.com/cosmos/cosmos-sdk/x/sentinel/types"
+)
+
+func TestSubmitUsageReceipt(t *testing.T) {
+	ctx, ck, keeper := createTestInput(t)
+	coins := func(amount int64) sdk.Coins { return sdk.Coins{{"sut", sdk.NewInt(amount)}} }
+	gb := senttype.BytesPerGb
+
+	// the dVPN node signs receipts with the key of its account
+	nodeKey := ed25519.GenPrivKey()
+	nodeAddr := sdk.AccAddress(nodeKey.PubKey().Address())
+	acc := keeper.account.NewAccountWithAddress(ctx, nodeAddr)
+	require.Nil(t, acc.SetPubKey(nodeKey.PubKey()))
+	keeper.account.SetAccount(ctx, acc)
+	keeper.SetVpnService(ctx, nodeAddr, newTestVpnNode())
+
+	clientKey := ed25519.GenPrivKey()
+	ctx = ctx.WithBlockHeader(abci.Header{Time: 1000})
+	id, _, err := keeper.PayVpnService(ctx, NewMsgPayVpnService(coins(100), nodeAddr, addrs[1], clientKey.PubKey()))
+	require.Nil(t, err)
+	sessionId := []byte(id)
+	submit := func(counter int64, upload int64, download int64, duration int64) (sdk.Coins, sdk.Error) {
+		bz := senttype.UsageStdSignBytes(sessionId, counter, upload, download, duration)
+		clientSig, signErr := clientKey.Sign(bz)
+		require.Nil(t, signErr)
+		nodeSig, signErr := nodeKey.Sign(bz)
+		require.Nil(t, signErr)
+		receipt := senttype.NewUsageReceipt(sessionId, counter, upload, download, duration, clientSig, nodeSig)
+		paid, _, sdkErr := keeper.SubmitUsageReceipt(ctx, NewMsgSubmitUsageReceipt(nodeAddr, receipt))
+		return paid, sdkErr
+	}
+
+	// 2 GB at 10sut per GB
+	paid, err := submit(1, gb/2, 3*gb/2, 600)
+	require.Nil(t, err)
+	require.Equal(t, coins(20), paid)
+	require.Equal(t, coins(20), ck.GetCoins(ctx, nodeAddr))
+
+	// the session keeps the price it was opened with
+	vpn := newTestVpnNode()
+	vpn.PricePerGb = coins(20)
+	keeper.SetVpnService(ctx, nodeAddr, vpn)
+	paid, err = submit(2, gb, 9*gb/2, 1200)
+	require.Nil(t, err)
+	require.Equal(t, coins(35), paid)
+	receipt, found := keeper.GetUsageReceipt(ctx, sessionId)
+	require.True(t, found)
+	require.Equal(t, int64(2), receipt.Counter)
+
+	// receipts are cumulative and share the counter of the client signatures
+	_, err = submit(3, gb, 4*gb, 1800)
+	require.Equal(t, CodeInvalidUsage, err.Code())
+	_, err = submit(2, gb, 5*gb, 1800)
+	require.Equal(t, CodeSignMsg, err.Code())
+	bz := senttype.UsageStdSignBytes(sessionId, 3, gb, 5*gb, 1800)
+	clientSig, _ := clientKey.Sign(bz)
+	_, _, err = keeper.SubmitUsageReceipt(ctx, NewMsgSubmitUsageReceipt(nodeAddr, senttype.NewUsageReceipt(sessionId, 3, gb, 5*gb, 1800, clientSig, clientSig)))
+	require.Equal(t, sdk.CodeUnauthorized, err.Code())
+
+	// the payment is capped at the locked coins
+	paid, err = submit(3, gb, 20*gb, 3600)
+	require.Nil(t, err)
+	require.Equal(t, coins(45), paid)
+	require.Equal(t, coins(100), ck.GetCoins(ctx, nodeAddr))
+
+	// the receipt is removed along with its session
+	keeper.DeleteSession(ctx, sessionId)
+	_, found = keeper.GetUsageReceipt(ctx, sessionId)
+	require.False(t, found)
+}
+
+func TestUsagePayment(t *testing.T) {
+	gb := senttype.BytesPerGb
+	locked := sdk.Coins{{"abc", sdk.NewInt(10)}, {"sut", sdk.NewInt(100)}}
+	price := sdk.Coins{{"abc", sdk.NewInt(1)}, {"sut", sdk.NewInt(10)}}
+	cases := []struct {
+		locked   sdk.Coins
+		price    sdk.Coins
+		bytes    int64
+		expected sdk.Coins
+	}{
+		{locked, price, 0, nil},
+		{locked, price, gb / 2, nil},
+		{locked, price, 5 * gb, sdk.Coins{{"abc", sdk.NewInt(5)}}},
+		{locked, price, 15 * gb, sdk.Coins{{"abc", sdk.NewInt(10)}, {"sut", sdk.NewInt(50)}}},
+		{locked, price, 100 * gb, locked},
+		{locked, sdk.Coins{{"sut", sdk.NewInt(10)}}, 3*gb + gb/2, sdk.Coins{{"sut", sdk.NewInt(35)}}},
+		{locked, nil, 100 * gb, nil},
+	}
+
+	for i, tc := range cases {
+		require.Equal(t, tc.expected, senttype.UsagePayment(tc.locked, tc.price, sdk.NewInt(tc.bytes)), "test: %v", i)
+	}
+}
+
+func TestMsgSubmitUsageReceiptValidateBasic(t *testing.T) {
+	key := ed25519.GenPrivKey()
+	sig, err := key.Sign([]byte("receipt"))
+	require.Nil(t, err)
+	cases := []struct {
+		msg        MsgSubmitUsageReceipt
+		expectPass bool
+	}{
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, 100, 1000, 60, sig, sig)), true},
+		{NewMsgSubmitUsageReceipt(nil, senttype.NewUsageReceipt([]byte("session"), 1, 100, 1000, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt(nil, 1, 100, 1000, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 0, 100, 1000, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, -1, 1000, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, senttype.MaxUsage, senttype.MaxUsage, senttype.MaxUsage, sig, sig)), true},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, senttype.MaxUsage+1, 1000, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, 100, math.MaxInt64, 60, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, 100, 1000, math.MaxInt64, sig, sig)), false},
+		{NewMsgSubmitUsageReceipt(addrs[0], senttype.NewUsageReceipt([]byte("session"), 1, 100, 1000, 60, sig, nil)), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.ValidateBasic()
+		if tc.expectPass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+		}
+	}
+}
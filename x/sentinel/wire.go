@@ -2,10 +2,12 @@ package sentinel
 
 import (
 	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/gov"
 )
 
 func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgRegisterVpnService{}, "sentinel/registervpn", nil)
+	cdc.RegisterConcrete(MsgUpdateVpnService{}, "sentinel/updatevpnservice", nil)
 	//cdc.RegisterConcrete(MsgQueryRegisteredVpnService{}, "sentinel/queryvpnservice", nil)
 	cdc.RegisterConcrete(MsgDeleteVpnUser{}, "sentienl/deletevpnservice", nil)
 	cdc.RegisterConcrete(MsgRegisterMasterNode{}, "sentinel/masternoderegistration", nil)
@@ -14,7 +16,20 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(MsgPayVpnService{}, "sentinel/payvpnservice", nil)
 	cdc.RegisterConcrete(MsgRefund{}, "sentinel/clientrefund", nil)
 	cdc.RegisterConcrete(MsgGetVpnPayment{}, "sentinel/getvpnpayment", nil)
+	cdc.RegisterConcrete(MsgSubmitUsageReceipt{}, "sentinel/submitusagereceipt", nil)
+	cdc.RegisterConcrete(MsgRateNode{}, "sentinel/ratenode", nil)
+	cdc.RegisterConcrete(MsgOpenDispute{}, "sentinel/opendispute", nil)
+	cdc.RegisterConcrete(MsgDisputeVerdict{}, "sentinel/disputeverdict", nil)
+	cdc.RegisterConcrete(MsgAddPlan{}, "sentinel/addplan", nil)
+	cdc.RegisterConcrete(MsgSubscribe{}, "sentinel/subscribe", nil)
+	cdc.RegisterConcrete(MsgSubmitSubscriptionUsage{}, "sentinel/submitsubscriptionusage", nil)
+	cdc.RegisterConcrete(MsgSubmitProposal{}, "sentinel/submitproposal", nil)
+	cdc.RegisterConcrete(MsgVote{}, "sentinel/vote", nil)
+	cdc.RegisterConcrete(MsgNodeHeartbeat{}, "sentinel/nodeheartbeat", nil)
 	cdc.RegisterConcrete(MsgSendTokens{}, "sentinel/sendtoken", nil)
+
+	cdc.RegisterConcrete(SlashNodeDepositProposal{}, "sentinel/SlashNodeDepositProposal", nil)
+	cdc.RegisterConcrete(RemoveMasterNodeProposal{}, "sentinel/RemoveMasterNodeProposal", nil)
 }
 
 var msgCdc = wire.NewCodec()
@@ -22,4 +37,7 @@ var msgCdc = wire.NewCodec()
 func init() {
 	RegisterWire(msgCdc)
 	wire.RegisterCrypto(msgCdc)
+
+	gov.RegisterProposalContent(SlashNodeDepositProposal{}, "sentinel/SlashNodeDepositProposal")
+	gov.RegisterProposalContent(RemoveMasterNodeProposal{}, "sentinel/RemoveMasterNodeProposal")
 }
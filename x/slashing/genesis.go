@@ -0,0 +1,40 @@
+package slashing
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState - all slashing state that must be provided at genesis
+type GenesisState struct {
+	Params Params `json:"params"`
+}
+
+func NewGenesisState(params Params) GenesisState {
+	return GenesisState{
+		Params: params,
+	}
+}
+
+// get raw genesis raw message for testing
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Params: DefaultParams(),
+	}
+}
+
+// ValidateGenesis checks the genesis parameters
+func ValidateGenesis(data GenesisState) error {
+	return validateParams(data.Params)
+}
+
+// InitGenesis - store genesis parameters
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	keeper.SetParams(ctx, data.Params)
+}
+
+// WriteGenesis - output genesis parameters
+func WriteGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	return GenesisState{
+		Params: keeper.GetParams(ctx),
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/tendermint/tendermint/crypto"
 )
 
@@ -13,17 +14,19 @@ type Keeper struct {
 	storeKey     sdk.StoreKey
 	cdc          *wire.Codec
 	validatorSet sdk.ValidatorSet
+	paramSpace   params.Subspace
 
 	// codespace
 	codespace sdk.CodespaceType
 }
 
 // NewKeeper creates a slashing keeper
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, vs sdk.ValidatorSet, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, vs sdk.ValidatorSet, paramSpace params.Subspace, codespace sdk.CodespaceType) Keeper {
 	keeper := Keeper{
 		storeKey:     key,
 		cdc:          cdc,
 		validatorSet: vs,
+		paramSpace:   paramSpace,
 		codespace:    codespace,
 	}
 	return keeper
@@ -35,18 +38,19 @@ func (k Keeper) handleDoubleSign(ctx sdk.Context, pubkey crypto.PubKey, infracti
 	time := ctx.BlockHeader().Time
 	age := time - timestamp
 	address := sdk.ValAddress(pubkey.Address())
+	params := k.GetParams(ctx)
 
 	// Double sign too old
-	if age > MaxEvidenceAge {
-		logger.Info(fmt.Sprintf("Ignored double sign from %s at height %d, age of %d past max age of %d", pubkey.Address(), infractionHeight, age, MaxEvidenceAge))
+	if age > params.MaxEvidenceAge {
+		logger.Info(fmt.Sprintf("Ignored double sign from %s at height %d, age of %d past max age of %d", pubkey.Address(), infractionHeight, age, params.MaxEvidenceAge))
 		return
 	}
 
 	// Double sign confirmed
-	logger.Info(fmt.Sprintf("Confirmed double sign from %s at height %d, age of %d less than max age of %d", pubkey.Address(), infractionHeight, age, MaxEvidenceAge))
+	logger.Info(fmt.Sprintf("Confirmed double sign from %s at height %d, age of %d less than max age of %d", pubkey.Address(), infractionHeight, age, params.MaxEvidenceAge))
 
 	// Slash validator
-	k.validatorSet.Slash(ctx, pubkey, infractionHeight, power, SlashFractionDoubleSign)
+	k.validatorSet.Slash(ctx, pubkey, infractionHeight, power, params.SlashFractionDoubleSign)
 
 	// Revoke validator
 	k.validatorSet.Revoke(ctx, pubkey)
@@ -56,7 +60,7 @@ func (k Keeper) handleDoubleSign(ctx sdk.Context, pubkey crypto.PubKey, infracti
 	if !found {
 		panic(fmt.Sprintf("Expected signing info for validator %s but not found", address))
 	}
-	signInfo.JailedUntil = time + DoubleSignUnbondDuration
+	signInfo.JailedUntil = time + params.DoubleSignUnbondDuration
 	k.setValidatorSigningInfo(ctx, address, signInfo)
 }
 
@@ -66,6 +70,7 @@ func (k Keeper) handleValidatorSignature(ctx sdk.Context, pubkey crypto.PubKey,
 	logger := ctx.Logger().With("module", "x/slashing")
 	height := ctx.BlockHeight()
 	address := sdk.ValAddress(pubkey.Address())
+	params := k.GetParams(ctx)
 
 	// Local index, so counts blocks validator *should* have signed
 	// Will use the 0-value default signing info if not present, except for start height
@@ -74,7 +79,7 @@ func (k Keeper) handleValidatorSignature(ctx sdk.Context, pubkey crypto.PubKey,
 		// If this validator has never been seen before, construct a new SigningInfo with the correct start height
 		signInfo = NewValidatorSigningInfo(height, 0, 0, 0)
 	}
-	index := signInfo.IndexOffset % SignedBlocksWindow
+	index := signInfo.IndexOffset % params.SignedBlocksWindow
 	signInfo.IndexOffset++
 
 	// Update signed block bit array & counter
@@ -94,18 +99,18 @@ func (k Keeper) handleValidatorSignature(ctx sdk.Context, pubkey crypto.PubKey,
 	}
 
 	if !signed {
-		logger.Info(fmt.Sprintf("Absent validator %s at height %d, %d signed, threshold %d", pubkey.Address(), height, signInfo.SignedBlocksCounter, MinSignedPerWindow))
+		logger.Info(fmt.Sprintf("Absent validator %s at height %d, %d signed, threshold %d", pubkey.Address(), height, signInfo.SignedBlocksCounter, params.MinSignedPerWindow))
 	}
-	minHeight := signInfo.StartHeight + SignedBlocksWindow
-	if height > minHeight && signInfo.SignedBlocksCounter < MinSignedPerWindow {
+	minHeight := signInfo.StartHeight + params.SignedBlocksWindow
+	if height > minHeight && signInfo.SignedBlocksCounter < params.MinSignedPerWindow {
 		validator := k.validatorSet.ValidatorByPubKey(ctx, pubkey)
 		if validator != nil && !validator.GetRevoked() {
 			// Downtime confirmed, slash, revoke, and jail the validator
 			logger.Info(fmt.Sprintf("Validator %s past min height of %d and below signed blocks threshold of %d",
-				pubkey.Address(), minHeight, MinSignedPerWindow))
-			k.validatorSet.Slash(ctx, pubkey, height, power, SlashFractionDowntime)
+				pubkey.Address(), minHeight, params.MinSignedPerWindow))
+			k.validatorSet.Slash(ctx, pubkey, height, power, params.SlashFractionDowntime)
 			k.validatorSet.Revoke(ctx, pubkey)
-			signInfo.JailedUntil = ctx.BlockHeader().Time + DowntimeUnbondDuration
+			signInfo.JailedUntil = ctx.BlockHeader().Time + params.DowntimeUnbondDuration
 		} else {
 			// Validator was (a) not found or (b) already revoked, don't slash
 			logger.Info(fmt.Sprintf("Validator %s would have been slashed for downtime, but was either not found in store or already revoked",
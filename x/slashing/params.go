@@ -1,42 +1,93 @@
 package slashing
 
 import (
+	"errors"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
-var (
-	// MaxEvidenceAge - Max age for evidence - 21 days (3 weeks)
-	// TODO Should this be a governance parameter or just modifiable with SoftwareUpgradeProposals?
-	// MaxEvidenceAge = 60 * 60 * 24 * 7 * 3
-	// TODO Temporarily set to 2 minutes for testnets.
-	MaxEvidenceAge int64 = 60 * 2
-
-	// SignedBlocksWindow - sliding window for downtime slashing
-	// TODO Governance parameter?
-	// TODO Temporarily set to 40000 blocks for testnets
-	SignedBlocksWindow int64 = 10000
-
-	// Downtime slashing threshold - 50%
-	// TODO Governance parameter?
-	MinSignedPerWindow = SignedBlocksWindow / 2
-
-	// Downtime unbond duration
-	// TODO Governance parameter?
-	// TODO Temporarily set to five minutes for testnets
-	DowntimeUnbondDuration int64 = 60 * 5
-
-	// Double-sign unbond duration
-	// TODO Governance parameter?
-	// TODO Temporarily set to five minutes for testnets
-	DoubleSignUnbondDuration int64 = 60 * 5
+// name of the slashing subspace of the params store, and the key of the
+// slashing params within it
+const (
+	DefaultParamspace   = "slashing"
+	ParamStoreKeyParams = "params"
 )
 
-var (
-	// SlashFractionDoubleSign - currently 5%
-	// TODO Governance parameter?
-	SlashFractionDoubleSign = sdk.NewRat(1).Quo(sdk.NewRat(20))
+// ParamTypeTable - the parameters of the slashing subspace
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterType(ParamStoreKeyParams, Params{}, validateParams)
+}
 
-	// SlashFractionDowntime - currently 1%
-	// TODO Governance parameter?
-	SlashFractionDowntime = sdk.NewRat(10).Quo(sdk.NewRat(100))
-)
+// Params - the governable parameters of slashing
+type Params struct {
+	MaxEvidenceAge           int64   `json:"max_evidence_age"`            // max age for evidence
+	SignedBlocksWindow       int64   `json:"signed_blocks_window"`        // sliding window for downtime slashing
+	MinSignedPerWindow       int64   `json:"min_signed_per_window"`       // downtime slashing threshold
+	DowntimeUnbondDuration   int64   `json:"downtime_unbond_duration"`    // downtime unbond duration
+	DoubleSignUnbondDuration int64   `json:"double_sign_unbond_duration"` // double-sign unbond duration
+	SlashFractionDoubleSign  sdk.Rat `json:"slash_fraction_double_sign"`  // fraction slashed for double signing
+	SlashFractionDowntime    sdk.Rat `json:"slash_fraction_downtime"`     // fraction slashed for downtime
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		// TODO Temporarily set to 2 minutes for testnets, should be 21 days (3 weeks)
+		MaxEvidenceAge: 60 * 2,
+
+		// TODO Temporarily set to 10000 blocks for testnets
+		SignedBlocksWindow: 10000,
+
+		// 50% of the window
+		MinSignedPerWindow: 10000 / 2,
+
+		// TODO Temporarily set to five minutes for testnets
+		DowntimeUnbondDuration:   60 * 5,
+		DoubleSignUnbondDuration: 60 * 5,
+
+		// 5% for double signing, 10% for downtime
+		SlashFractionDoubleSign: sdk.NewRat(1).Quo(sdk.NewRat(20)),
+		SlashFractionDowntime:   sdk.NewRat(10).Quo(sdk.NewRat(100)),
+	}
+}
+
+// validate the slashing params set by a params change
+func validateParams(value interface{}) error {
+	params := value.(Params)
+	if params.SignedBlocksWindow <= 0 {
+		return errors.New("signed blocks window must be positive")
+	}
+	if params.MinSignedPerWindow < 0 || params.MinSignedPerWindow > params.SignedBlocksWindow {
+		return errors.New("min signed per window must be between 0 and the signed blocks window")
+	}
+	if params.MaxEvidenceAge < 0 || params.DowntimeUnbondDuration < 0 || params.DoubleSignUnbondDuration < 0 {
+		return errors.New("max evidence age and unbond durations must not be negative")
+	}
+	for _, fraction := range []sdk.Rat{params.SlashFractionDoubleSign, params.SlashFractionDowntime} {
+		if fraction.Rat == nil || fraction.LT(sdk.ZeroRat()) || fraction.GT(sdk.OneRat()) {
+			return errors.New("slash fractions must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+// MigrateParams sets the default slashing params, which the slashing module
+// used before they became governable, unless they have been set. It is run
+// by an upgrade handler registered by the app.
+func (k Keeper) MigrateParams(ctx sdk.Context) {
+	if !k.paramSpace.Has(ctx, ParamStoreKeyParams) {
+		k.SetParams(ctx, DefaultParams())
+	}
+}
+
+// load/save the slashing params, kept in the slashing subspace of the params store
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	k.paramSpace.Get(ctx, ParamStoreKeyParams, &params)
+	return
+}
+
+// set the params
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	k.paramSpace.Set(ctx, ParamStoreKeyParams, params)
+}
@@ -14,6 +14,7 @@ import (
 
 func TestBeginBlocker(t *testing.T) {
 	ctx, ck, sk, keeper := createTestInput(t)
+	params := keeper.GetParams(ctx)
 	addr, pk, amt := addrs[2], pks[2], sdk.NewInt(100)
 
 	// bond the validator
@@ -47,7 +48,7 @@ func TestBeginBlocker(t *testing.T) {
 	height := int64(0)
 
 	// for 1000 blocks, mark the validator as having signed
-	for ; height < SignedBlocksWindow; height++ {
+	for ; height < params.SignedBlocksWindow; height++ {
 		ctx = ctx.WithBlockHeight(height)
 		req = abci.RequestBeginBlock{
 			Validators: []abci.SigningValidator{{
@@ -59,7 +60,7 @@ func TestBeginBlocker(t *testing.T) {
 	}
 
 	// for 500 blocks, mark the validator as having not signed
-	for ; height < ((SignedBlocksWindow * 2) - MinSignedPerWindow + 1); height++ {
+	for ; height < ((params.SignedBlocksWindow * 2) - params.MinSignedPerWindow + 1); height++ {
 		ctx = ctx.WithBlockHeight(height)
 		req = abci.RequestBeginBlock{
 			Validators: []abci.SigningValidator{{
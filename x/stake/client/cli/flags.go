@@ -14,6 +14,7 @@ const (
 	FlagAmount              = "amount"
 	FlagSharesAmount        = "shares-amount"
 	FlagSharesPercent       = "shares-percent"
+	FlagCommission          = "commission"
 
 	FlagMoniker  = "moniker"
 	FlagIdentity = "keybase-sig"
@@ -25,6 +26,7 @@ const (
 var (
 	fsPk           = flag.NewFlagSet("", flag.ContinueOnError)
 	fsAmount       = flag.NewFlagSet("", flag.ContinueOnError)
+	fsCommission   = flag.NewFlagSet("", flag.ContinueOnError)
 	fsShares       = flag.NewFlagSet("", flag.ContinueOnError)
 	fsDescription  = flag.NewFlagSet("", flag.ContinueOnError)
 	fsValidator    = flag.NewFlagSet("", flag.ContinueOnError)
@@ -35,6 +37,7 @@ var (
 func init() {
 	fsPk.String(FlagPubKey, "", "Go-Amino encoded hex PubKey of the validator. For Ed25519 the go-amino prepend hex is 1624de6220")
 	fsAmount.String(FlagAmount, "1steak", "Amount of coins to bond")
+	fsCommission.String(FlagCommission, "0", "Fraction of the earned fees kept by the validator as a decimal >=0 and <=1")
 	fsShares.String(FlagSharesAmount, "", "Amount of source-shares to either unbond or redelegate as a positive integer or decimal")
 	fsShares.String(FlagSharesPercent, "", "Percent of source-shares to either unbond or redelegate as a positive integer or decimal >0 and <=1")
 	fsDescription.String(FlagMoniker, "[do-not-modify]", "validator name")
@@ -51,7 +51,12 @@ func GetCmdCreateValidator(cdc *wire.Codec) *cobra.Command {
 				Details:  viper.GetString(FlagDetails),
 			}
 
-			var msg sdk.Msg
+			commission, err := sdk.NewRatFromDecimal(viper.GetString(FlagCommission), types.MaxBondDenominatorPrecision)
+			if err != nil {
+				return err
+			}
+
+			var msg stake.MsgCreateValidator
 			if viper.GetString(FlagAddressDelegator) != "" {
 				delegatorAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddressDelegator))
 				if err != nil {
@@ -61,6 +66,7 @@ func GetCmdCreateValidator(cdc *wire.Codec) *cobra.Command {
 			} else {
 				msg = stake.NewMsgCreateValidator(validatorAddr, pk, amount, description)
 			}
+			msg.Commission = commission
 
 			// build and sign the transaction, then broadcast to Tendermint
 			err = ctx.EnsureSignBuildBroadcast(ctx.FromAddressName, []sdk.Msg{msg}, cdc)
@@ -73,6 +79,7 @@ func GetCmdCreateValidator(cdc *wire.Codec) *cobra.Command {
 
 	cmd.Flags().AddFlagSet(fsPk)
 	cmd.Flags().AddFlagSet(fsAmount)
+	cmd.Flags().AddFlagSet(fsCommission)
 	cmd.Flags().AddFlagSet(fsDescription)
 	cmd.Flags().AddFlagSet(fsValidator)
 	cmd.Flags().AddFlagSet(fsDelegator)
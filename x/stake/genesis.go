@@ -46,6 +46,23 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) error
 	return nil
 }
 
+// ValidateGenesis checks the params of data and that no validator is given
+// twice, before the genesis state is loaded
+func ValidateGenesis(data types.GenesisState) error {
+	err := data.Params.Validate()
+	if err != nil {
+		return err
+	}
+	owners := make(map[string]bool, len(data.Validators))
+	for _, validator := range data.Validators {
+		if owners[validator.Owner.String()] {
+			return errors.Errorf("duplicate genesis validator, owner: %s", validator.Owner)
+		}
+		owners[validator.Owner.String()] = true
+	}
+	return nil
+}
+
 // WriteGenesis returns a GenesisState for a given context and keeper. The
 // GenesisState will contain the pool, params, validators, and bonds found in
 // the keeper.
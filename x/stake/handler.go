@@ -50,6 +50,13 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) (ValidatorUpdates []abci.Valid
 	// reset the intra-transaction counter
 	k.SetIntraTxCounter(ctx, 0)
 
+	// recalculate the bonded validators if the max validator count was
+	// changed through the params store, e.g. by a governance proposal
+	if k.GetLastMaxValidators(ctx) != params.MaxValidators {
+		k.UpdateBondedValidatorsFull(ctx)
+		k.SetLastMaxValidators(ctx, params.MaxValidators)
+	}
+
 	// calculate validator set changes
 	ValidatorUpdates = k.GetTendermintUpdates(ctx)
 	k.ClearTendermintUpdates(ctx)
@@ -77,6 +84,7 @@ func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k k
 	}
 
 	validator := NewValidator(msg.ValidatorAddr, msg.PubKey, msg.Description)
+	validator.Commission = msg.Commission
 	k.SetValidator(ctx, validator)
 	k.SetValidatorByPubKeyIndex(ctx, validator)
 
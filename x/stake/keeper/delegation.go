@@ -204,6 +204,10 @@ func (k Keeper) RemoveRedelegation(ctx sdk.Context, red types.Redelegation) {
 func (k Keeper) Delegate(ctx sdk.Context, delegatorAddr sdk.AccAddress, bondAmt sdk.Coin,
 	validator types.Validator, subtractAccount bool) (newShares sdk.Rat, err sdk.Error) {
 
+	if k.hooks != nil {
+		k.hooks.BeforeDelegationSharesModified(ctx, delegatorAddr, validator.Owner)
+	}
+
 	// Get or create the delegator delegation
 	delegation, found := k.GetDelegation(ctx, delegatorAddr, validator.Owner)
 	if !found {
@@ -260,6 +264,10 @@ func (k Keeper) unbond(ctx sdk.Context, delegatorAddr, validatorAddr sdk.AccAddr
 		return
 	}
 
+	if k.hooks != nil {
+		k.hooks.BeforeDelegationSharesModified(ctx, delegatorAddr, validatorAddr)
+	}
+
 	// subtract shares from delegator
 	delegation.Shares = delegation.Shares.Sub(shares)
 
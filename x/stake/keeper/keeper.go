@@ -5,29 +5,59 @@ import (
 	"github.com/cosmos/cosmos-sdk/wire"
 
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/stake/types"
 )
 
+// name of the staking subspace of the params store, and the key of the
+// staking params within it
+const (
+	DefaultParamspace   = "stake"
+	ParamStoreKeyParams = "params"
+)
+
+// ParamTypeTable - the parameters of the staking subspace
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterType(ParamStoreKeyParams, types.Params{}, validateParams)
+}
+
+// validate the staking params set by a params change
+func validateParams(value interface{}) error {
+	return value.(types.Params).Validate()
+}
+
 // keeper of the stake store
 type Keeper struct {
 	storeKey   sdk.StoreKey
 	cdc        *wire.Codec
 	coinKeeper bank.Keeper
+	paramSpace params.Subspace
+	hooks      sdk.StakingHooks
 
 	// codespace
 	codespace sdk.CodespaceType
 }
 
-func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, paramSpace params.Subspace, codespace sdk.CodespaceType) Keeper {
 	keeper := Keeper{
 		storeKey:   key,
 		cdc:        cdc,
 		coinKeeper: ck,
+		paramSpace: paramSpace,
 		codespace:  codespace,
 	}
 	return keeper
 }
 
+// Set the staking hooks, called on changes of the staking state
+func (k Keeper) SetHooks(sh sdk.StakingHooks) Keeper {
+	if k.hooks != nil {
+		panic("cannot set staking hooks twice")
+	}
+	k.hooks = sh
+	return k
+}
+
 //_________________________________________________________________________
 
 // return the codespace
@@ -38,16 +68,10 @@ func (k Keeper) Codespace() sdk.CodespaceType {
 //_________________________________________________________________________
 // some generic reads/writes that don't need their own files
 
-// load/save the global staking params
+// load/save the global staking params, kept in the staking subspace of the
+// params store
 func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	store := ctx.KVStore(k.storeKey)
-
-	b := store.Get(ParamKey)
-	if b == nil {
-		panic("Stored params should not have been nil")
-	}
-
-	k.cdc.MustUnmarshalBinary(b, &params)
+	k.paramSpace.Get(ctx, ParamStoreKeyParams, &params)
 	return
 }
 
@@ -56,22 +80,57 @@ func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
 // panic on retrieval if it doesn't exist - hence if we use setParams for the very
 // first params set it will panic.
 func (k Keeper) SetNewParams(ctx sdk.Context, params types.Params) {
-	store := ctx.KVStore(k.storeKey)
-	b := k.cdc.MustMarshalBinary(params)
-	store.Set(ParamKey, b)
+	k.paramSpace.Set(ctx, ParamStoreKeyParams, params)
+	k.SetLastMaxValidators(ctx, params.MaxValidators)
 }
 
 // set the params
 func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
-	store := ctx.KVStore(k.storeKey)
 	exParams := k.GetParams(ctx)
 
 	// if max validator count changes, must recalculate validator set
 	if exParams.MaxValidators != params.MaxValidators {
 		k.UpdateBondedValidatorsFull(ctx)
 	}
-	b := k.cdc.MustMarshalBinary(params)
-	store.Set(ParamKey, b)
+	k.paramSpace.Set(ctx, ParamStoreKeyParams, params)
+	k.SetLastMaxValidators(ctx, params.MaxValidators)
+}
+
+// MigrateParams moves the staking params of the stake store, kept there
+// before they moved to the params store, into the staking subspace, or sets
+// the default params if neither store holds them. It is run by an upgrade
+// handler registered by the app.
+func (k Keeper) MigrateParams(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	if b := store.Get(LegacyParamsKey); b != nil {
+		var params types.Params
+		k.cdc.MustUnmarshalBinary(b, &params)
+		store.Delete(LegacyParamsKey)
+		k.SetNewParams(ctx, params)
+		return
+	}
+	if !k.paramSpace.Has(ctx, ParamStoreKeyParams) {
+		k.SetNewParams(ctx, types.DefaultParams())
+	}
+}
+
+// load/save the maximum number of validators the bonded validator set was
+// last updated with, used to detect changes made directly to the params store
+func (k Keeper) GetLastMaxValidators(ctx sdk.Context) (maxValidators uint16) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(LastMaxValidatorsKey)
+	if b == nil {
+		panic("Stored last max validators should not have been nil")
+	}
+	k.cdc.MustUnmarshalBinary(b, &maxValidators)
+	return
+}
+
+// set the last max validators
+func (k Keeper) SetLastMaxValidators(ctx sdk.Context, maxValidators uint16) {
+	store := ctx.KVStore(k.storeKey)
+	b := k.cdc.MustMarshalBinary(maxValidators)
+	store.Set(LastMaxValidatorsKey, b)
 }
 
 //_______________________________________________________________________
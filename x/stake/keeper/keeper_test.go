@@ -24,6 +24,23 @@ func TestParams(t *testing.T) {
 	require.True(t, expParams.Equal(resParams))
 }
 
+func TestMigrateParams(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	expParams := types.DefaultParams()
+	expParams.MaxValidators = 777
+
+	// the params of the stake store replace the params store ones
+	ctx.KVStore(keeper.storeKey).Set(LegacyParamsKey, keeper.cdc.MustMarshalBinary(expParams))
+	keeper.MigrateParams(ctx)
+	require.True(t, expParams.Equal(keeper.GetParams(ctx)))
+	require.Equal(t, uint16(777), keeper.GetLastMaxValidators(ctx))
+	require.Nil(t, ctx.KVStore(keeper.storeKey).Get(LegacyParamsKey))
+
+	// migrated params are kept
+	keeper.MigrateParams(ctx)
+	require.True(t, expParams.Equal(keeper.GetParams(ctx)))
+}
+
 func TestPool(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 0)
 	expPool := types.InitialPool()
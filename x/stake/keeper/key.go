@@ -14,7 +14,7 @@ import (
 //nolint
 var (
 	// Keys for store prefixes
-	ParamKey                         = []byte{0x00} // key for parameters relating to staking
+	LegacyParamsKey                  = []byte{0x00} // key for the staking params before they moved to the params store
 	PoolKey                          = []byte{0x01} // key for the staking pools
 	ValidatorsKey                    = []byte{0x02} // prefix for each key to a validator
 	ValidatorsByPubKeyIndexKey       = []byte{0x03} // prefix for each key to a validator index, by pubkey
@@ -30,6 +30,7 @@ var (
 	RedelegationKey                  = []byte{0x0D} // key for a redelegation
 	RedelegationByValSrcIndexKey     = []byte{0x0E} // prefix for each key for an redelegation, by source validator owner
 	RedelegationByValDstIndexKey     = []byte{0x0F} // prefix for each key for an redelegation, by destination validator owner
+	LastMaxValidatorsKey             = []byte{0x10} // key for the max validators the bonded set was last updated with
 )
 
 const maxDigitsForAccount = 12 // ~220,000,000 atoms created at launch
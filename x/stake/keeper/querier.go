@@ -0,0 +1,90 @@
+package keeper
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/stake/types"
+)
+
+// query endpoints supported by the staking Querier
+const (
+	QueryValidators = "validators"
+	QueryValidator  = "validator"
+	QueryDelegation = "delegation"
+	QueryPool       = "pool"
+	QueryParameters = "parameters"
+)
+
+// NewQuerier returns the sdk.Querier answering "custom/stake/<endpoint>" queries
+func NewQuerier(k Keeper, cdc *wire.Codec) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("no stake query endpoint specified")
+		}
+		switch path[0] {
+		case QueryValidators:
+			return marshalQueryResult(cdc, k.GetAllValidators(ctx))
+		case QueryValidator:
+			return queryValidator(ctx, cdc, req, k)
+		case QueryDelegation:
+			return queryDelegation(ctx, cdc, req, k)
+		case QueryPool:
+			return marshalQueryResult(cdc, k.GetPool(ctx))
+		case QueryParameters:
+			return marshalQueryResult(cdc, k.GetParams(ctx))
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown stake query endpoint %s", path[0]))
+		}
+	}
+}
+
+// Params for query 'custom/stake/validator'
+type QueryValidatorParams struct {
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+// Params for query 'custom/stake/delegation'
+type QueryDelegationParams struct {
+	DelegatorAddr sdk.AccAddress `json:"delegator_addr"`
+	ValidatorAddr sdk.AccAddress `json:"validator_addr"`
+}
+
+func queryValidator(ctx sdk.Context, cdc *wire.Codec, req abci.RequestQuery, k Keeper) (res []byte, err sdk.Error) {
+	var params QueryValidatorParams
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	validator, found := k.GetValidator(ctx, params.ValidatorAddr)
+	if !found {
+		return nil, types.ErrNoValidatorFound(k.codespace)
+	}
+	return marshalQueryResult(cdc, validator)
+}
+
+func queryDelegation(ctx sdk.Context, cdc *wire.Codec, req abci.RequestQuery, k Keeper) (res []byte, err sdk.Error) {
+	var params QueryDelegationParams
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data - %s", errRes.Error()))
+	}
+
+	delegation, found := k.GetDelegation(ctx, params.DelegatorAddr, params.ValidatorAddr)
+	if !found {
+		return nil, types.ErrNoDelegation(k.codespace)
+	}
+	return marshalQueryResult(cdc, delegation)
+}
+
+func marshalQueryResult(cdc *wire.Codec, result interface{}) (res []byte, err sdk.Error) {
+	bz, errRes := wire.MarshalJSONIndent(cdc, result)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("could not marshal result to JSON - %s", errRes.Error()))
+	}
+	return bz, nil
+}
@@ -25,10 +25,15 @@ type (
 	MsgBeginRedelegate    = types.MsgBeginRedelegate
 	MsgCompleteRedelegate = types.MsgCompleteRedelegate
 	GenesisState          = types.GenesisState
+
+	QueryValidatorParams  = keeper.QueryValidatorParams
+	QueryDelegationParams = keeper.QueryDelegationParams
 )
 
 var (
-	NewKeeper = keeper.NewKeeper
+	NewKeeper      = keeper.NewKeeper
+	NewQuerier     = keeper.NewQuerier
+	ParamTypeTable = keeper.ParamTypeTable
 
 	GetValidatorKey              = keeper.GetValidatorKey
 	GetValidatorByPubKeyIndexKey = keeper.GetValidatorByPubKeyIndexKey
@@ -37,7 +42,8 @@ var (
 	GetTendermintUpdatesKey      = keeper.GetTendermintUpdatesKey
 	GetDelegationKey             = keeper.GetDelegationKey
 	GetDelegationsKey            = keeper.GetDelegationsKey
-	ParamKey                     = keeper.ParamKey
+	LastMaxValidatorsKey         = keeper.LastMaxValidatorsKey
+	LegacyParamsKey              = keeper.LegacyParamsKey
 	PoolKey                      = keeper.PoolKey
 	ValidatorsKey                = keeper.ValidatorsKey
 	ValidatorsByPubKeyIndexKey   = keeper.ValidatorsByPubKeyIndexKey
@@ -78,6 +84,11 @@ var (
 	NewMsgCompleteRedelegate        = types.NewMsgCompleteRedelegate
 )
 
+const (
+	DefaultParamspace   = keeper.DefaultParamspace
+	ParamStoreKeyParams = keeper.ParamStoreKeyParams
+)
+
 const (
 	DefaultCodespace      = types.DefaultCodespace
 	CodeInvalidValidator  = types.CodeInvalidValidator
@@ -97,6 +108,7 @@ var (
 	ErrValidatorRevoked      = types.ErrValidatorRevoked
 	ErrBadRemoveValidator    = types.ErrBadRemoveValidator
 	ErrDescriptionLength     = types.ErrDescriptionLength
+	ErrNilCommission         = types.ErrNilCommission
 	ErrCommissionNegative    = types.ErrCommissionNegative
 	ErrCommissionHuge        = types.ErrCommissionHuge
 
@@ -51,6 +51,10 @@ func ErrDescriptionLength(codespace sdk.CodespaceType, descriptor string, got, m
 	return sdk.NewError(codespace, CodeInvalidValidator, msg)
 }
 
+func ErrNilCommission(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, "commission is nil")
+}
+
 func ErrCommissionNegative(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "commission must be positive")
 }
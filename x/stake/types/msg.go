@@ -32,6 +32,7 @@ type MsgCreateValidator struct {
 	ValidatorAddr sdk.AccAddress `json:"validator_address"`
 	PubKey        crypto.PubKey  `json:"pubkey"`
 	Delegation    sdk.Coin       `json:"delegation"`
+	Commission    sdk.Rat        `json:"commission"`
 }
 
 // Default way to create validator. Delegator address and validator address are the same
@@ -43,6 +44,7 @@ func NewMsgCreateValidator(validatorAddr sdk.AccAddress, pubkey crypto.PubKey,
 		ValidatorAddr: validatorAddr,
 		PubKey:        pubkey,
 		Delegation:    selfDelegation,
+		Commission:    sdk.ZeroRat(),
 	}
 }
 
@@ -55,6 +57,7 @@ func NewMsgCreateValidatorOnBehalfOf(delegatorAddr, validatorAddr sdk.AccAddress
 		ValidatorAddr: validatorAddr,
 		PubKey:        pubkey,
 		Delegation:    delegation,
+		Commission:    sdk.ZeroRat(),
 	}
 }
 
@@ -80,11 +83,13 @@ func (msg MsgCreateValidator) GetSignBytes() []byte {
 		ValidatorAddr sdk.AccAddress `json:"validator_address"`
 		PubKey        string         `json:"pubkey"`
 		Delegation    sdk.Coin       `json:"delegation"`
+		Commission    sdk.Rat        `json:"commission"`
 	}{
 		Description:   msg.Description,
 		ValidatorAddr: msg.ValidatorAddr,
 		PubKey:        sdk.MustBech32ifyValPub(msg.PubKey),
 		Delegation:    msg.Delegation,
+		Commission:    msg.Commission,
 	})
 	if err != nil {
 		panic(err)
@@ -103,6 +108,15 @@ func (msg MsgCreateValidator) ValidateBasic() sdk.Error {
 	if !(msg.Delegation.Amount.GT(sdk.ZeroInt())) {
 		return ErrBadDelegationAmount(DefaultCodespace)
 	}
+	if msg.Commission.Rat == nil {
+		return ErrNilCommission(DefaultCodespace)
+	}
+	if msg.Commission.LT(sdk.ZeroRat()) {
+		return ErrCommissionNegative(DefaultCodespace)
+	}
+	if msg.Commission.GT(sdk.OneRat()) {
+		return ErrCommissionHuge(DefaultCodespace)
+	}
 	empty := Description{}
 	if msg.Description == empty {
 		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "description must be included")
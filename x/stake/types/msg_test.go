@@ -43,6 +43,19 @@ func TestMsgCreateValidator(t *testing.T) {
 			require.NotNil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		}
 	}
+
+	// the commission is a fraction between 0 and 1
+	msg := NewMsgCreateValidator(addr1, pk1, coinPos, NewDescription("a", "b", "c", "d"))
+	msg.Commission = sdk.NewRat(1, 10)
+	require.Nil(t, msg.ValidateBasic())
+	msg.Commission = sdk.OneRat()
+	require.Nil(t, msg.ValidateBasic())
+	msg.Commission = sdk.NewRat(-1, 10)
+	require.NotNil(t, msg.ValidateBasic())
+	msg.Commission = sdk.NewRat(11, 10)
+	require.NotNil(t, msg.ValidateBasic())
+	msg.Commission = sdk.Rat{}
+	require.NotNil(t, msg.ValidateBasic())
 }
 
 // test ValidateBasic for MsgEditValidator
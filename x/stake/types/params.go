@@ -2,6 +2,7 @@ package types
 
 import (
 	"bytes"
+	"errors"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -42,3 +43,19 @@ func DefaultParams() Params {
 		BondDenom:           "steak",
 	}
 }
+
+// Validate checks the params set at genesis or by a params change
+func (p Params) Validate() error {
+	for _, fraction := range []sdk.Rat{p.InflationRateChange, p.InflationMax, p.InflationMin, p.GoalBonded} {
+		if fraction.Rat == nil || fraction.LT(sdk.ZeroRat()) || fraction.GT(sdk.OneRat()) {
+			return errors.New("inflation rates and goal bonded must be between 0 and 1")
+		}
+	}
+	if p.InflationMin.GT(p.InflationMax) {
+		return errors.New("min inflation must not exceed max inflation")
+	}
+	if p.UnbondingTime < 0 || p.MaxValidators == 0 || p.BondDenom == "" {
+		return errors.New("params must have a non-negative unbonding time, validators and a bond denom")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+//nolint
+package upgrade
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	DefaultCodespace sdk.CodespaceType = 11
+
+	CodeInvalidPlan sdk.CodeType = 1
+	CodeUpgradeDone sdk.CodeType = 2
+)
+
+//----------------------------------------
+// Error constructors
+
+func ErrInvalidPlan(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidPlan, "Invalid upgrade plan: "+msg)
+}
+
+func ErrUpgradeDone(codespace sdk.CodespaceType, name string) sdk.Error {
+	return sdk.NewError(codespace, CodeUpgradeDone, "Upgrade "+name+" has already been applied")
+}
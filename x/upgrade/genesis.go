@@ -0,0 +1,49 @@
+package upgrade
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState - the upgrade plan scheduled at genesis, if any
+type GenesisState struct {
+	Plan Plan `json:"plan"` // empty if no upgrade is scheduled
+}
+
+func NewGenesisState(plan Plan) GenesisState {
+	return GenesisState{
+		Plan: plan,
+	}
+}
+
+// get raw genesis raw message for testing
+func DefaultGenesisState() GenesisState {
+	return GenesisState{}
+}
+
+// ValidateGenesis checks the scheduled plan
+func ValidateGenesis(data GenesisState) error {
+	if data.Plan.IsEmpty() {
+		return nil
+	}
+	if err := data.Plan.ValidateBasic(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// InitGenesis - schedule the genesis upgrade plan
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) error {
+	if data.Plan.IsEmpty() {
+		return nil
+	}
+	if err := keeper.ScheduleUpgrade(ctx, data.Plan); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteGenesis - output the scheduled upgrade plan
+func WriteGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	plan, _ := keeper.GetUpgradePlan(ctx)
+	return NewGenesisState(plan)
+}
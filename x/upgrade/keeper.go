@@ -0,0 +1,96 @@
+package upgrade
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// UpgradeHandler is run by the upgraded binary in the BeginBlock of the
+// upgrade height, before any other module, and is the place for the store
+// migrations of the upgrade
+type UpgradeHandler func(ctx sdk.Context, plan Plan)
+
+// Keeper of the upgrade store
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+
+	// upgrade handlers known to the running binary, by upgrade name
+	upgradeHandlers map[string]UpgradeHandler
+
+	// codespace
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper creates an upgrade keeper
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:        key,
+		cdc:             cdc,
+		upgradeHandlers: make(map[string]UpgradeHandler),
+		codespace:       codespace,
+	}
+}
+
+// SetUpgradeHandler registers the handler of the upgrade with the given
+// name, letting the binary process blocks past the upgrade height
+func (k Keeper) SetUpgradeHandler(name string, handler UpgradeHandler) {
+	if _, ok := k.upgradeHandlers[name]; ok {
+		panic(fmt.Sprintf("upgrade handler for %s already set", name))
+	}
+	k.upgradeHandlers[name] = handler
+}
+
+// ScheduleUpgrade stores the plan, replacing any plan scheduled before
+func (k Keeper) ScheduleUpgrade(ctx sdk.Context, plan Plan) sdk.Error {
+	err := plan.ValidateBasic()
+	if err != nil {
+		return err
+	}
+	if plan.Height <= ctx.BlockHeight() {
+		return ErrInvalidPlan(k.codespace, fmt.Sprintf("upgrade height %d is not after the current height %d", plan.Height, ctx.BlockHeight()))
+	}
+	if k.GetDoneHeight(ctx, plan.Name) != 0 {
+		return ErrUpgradeDone(k.codespace, plan.Name)
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(PlanKey, k.cdc.MustMarshalBinary(plan))
+	return nil
+}
+
+// GetUpgradePlan returns the scheduled upgrade plan, if any
+func (k Keeper) GetUpgradePlan(ctx sdk.Context) (plan Plan, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(PlanKey)
+	if bz == nil {
+		return plan, false
+	}
+	k.cdc.MustUnmarshalBinary(bz, &plan)
+	return plan, true
+}
+
+// ClearUpgradePlan removes the scheduled upgrade plan
+func (k Keeper) ClearUpgradePlan(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(PlanKey)
+}
+
+// GetDoneHeight returns the height at which the upgrade was applied, or 0 if
+// it has not been applied
+func (k Keeper) GetDoneHeight(ctx sdk.Context, name string) (height int64) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetDoneKey(name))
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinary(bz, &height)
+	return
+}
+
+func (k Keeper) setDone(ctx sdk.Context, name string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetDoneKey(name), k.cdc.MustMarshalBinary(ctx.BlockHeight()))
+}
@@ -0,0 +1,117 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper) {
+	key := sdk.NewKVStoreKey("upgrade")
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	err := ms.LoadLatestVersion()
+	require.Nil(t, err)
+	ctx := sdk.NewContext(ms, abci.Header{Height: 10}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), key, DefaultCodespace)
+}
+
+func TestScheduleUpgrade(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+
+	_, found := keeper.GetUpgradePlan(ctx)
+	require.False(t, found)
+
+	require.NotNil(t, keeper.ScheduleUpgrade(ctx, NewPlan("", 20, "")))
+	require.NotNil(t, keeper.ScheduleUpgrade(ctx, NewPlan("v2", 10, "")))
+
+	plan := NewPlan("v2", 20, "https://example.com/v2")
+	require.Nil(t, keeper.ScheduleUpgrade(ctx, plan))
+	stored, found := keeper.GetUpgradePlan(ctx)
+	require.True(t, found)
+	require.Equal(t, plan, stored)
+
+	// a later plan replaces the scheduled one
+	plan = NewPlan("v2", 30, "")
+	require.Nil(t, keeper.ScheduleUpgrade(ctx, plan))
+	stored, _ = keeper.GetUpgradePlan(ctx)
+	require.Equal(t, plan, stored)
+
+	keeper.ClearUpgradePlan(ctx)
+	_, found = keeper.GetUpgradePlan(ctx)
+	require.False(t, found)
+}
+
+func TestHaltHookWithoutHandler(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+	hook := NewHaltHook(keeper)
+	require.Equal(t, "", hook(ctx, 20))
+	require.Nil(t, keeper.ScheduleUpgrade(ctx, NewPlan("v2", 20, "")))
+
+	// blocks before the upgrade height are processed as usual
+	require.Equal(t, "", hook(ctx, 19))
+	require.NotEqual(t, "", hook(ctx, 20))
+
+	// the block of the upgrade height is never processed without a handler
+	ctx = ctx.WithBlockHeight(20)
+	require.Empty(t, BeginBlocker(ctx, keeper))
+	_, found := keeper.GetUpgradePlan(ctx)
+	require.True(t, found)
+}
+
+func TestBeginBlockerAppliesUpgrade(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+	require.Nil(t, keeper.ScheduleUpgrade(ctx, NewPlan("v2", 20, "")))
+
+	applied := int64(0)
+	keeper.SetUpgradeHandler("v2", func(ctx sdk.Context, plan Plan) {
+		applied = ctx.BlockHeight()
+	})
+	require.Panics(t, func() { keeper.SetUpgradeHandler("v2", func(sdk.Context, Plan) {}) })
+
+	// an upgraded binary keeps processing the blocks before the upgrade height
+	hook := NewHaltHook(keeper)
+	require.Equal(t, "", hook(ctx, 19))
+	require.Equal(t, "", hook(ctx, 20))
+	require.Empty(t, BeginBlocker(ctx.WithBlockHeight(19), keeper))
+	require.Equal(t, int64(0), applied)
+
+	ctx = ctx.WithBlockHeight(20)
+	tags := BeginBlocker(ctx, keeper)
+	require.Equal(t, int64(20), applied)
+	require.Equal(t, sdk.NewTags("action", []byte("upgradeApplied"), "upgrade", []byte("v2")), tags)
+	_, found := keeper.GetUpgradePlan(ctx)
+	require.False(t, found)
+	require.Equal(t, int64(20), keeper.GetDoneHeight(ctx, "v2"))
+	require.Equal(t, "", hook(ctx, 21))
+
+	// applied upgrades cannot be scheduled again
+	require.NotNil(t, keeper.ScheduleUpgrade(ctx, NewPlan("v2", 30, "")))
+}
+
+func TestProposalHandler(t *testing.T) {
+	ctx, keeper := createTestInput(t)
+	handler := NewProposalHandler(keeper)
+
+	// plans for reached heights are rejected on submission
+	require.NotNil(t, handler.CheckProposal(ctx, NewSoftwareUpgradeProposal(NewPlan("v2", 10, ""))))
+	content := NewSoftwareUpgradeProposal(NewPlan("v2", 20, "https://example.com/v2"))
+	require.Nil(t, handler.CheckProposal(ctx, content))
+
+	// the plan is only scheduled once the proposal passes
+	_, found := keeper.GetUpgradePlan(ctx)
+	require.False(t, found)
+	require.Nil(t, handler.ExecuteProposal(ctx, content))
+	scheduled, found := keeper.GetUpgradePlan(ctx)
+	require.True(t, found)
+	require.Equal(t, content.Plan, scheduled)
+}
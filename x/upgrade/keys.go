@@ -0,0 +1,13 @@
+package upgrade
+
+//nolint
+var (
+	PlanKey       = []byte{0x00} // key for the scheduled upgrade plan
+	DoneKeyPrefix = []byte{0x01} // prefix for the heights at which upgrades were applied
+)
+
+// get the key for the height at which the upgrade was applied
+// VALUE: int64
+func GetDoneKey(name string) []byte {
+	return append(DoneKeyPrefix, []byte(name)...)
+}
@@ -0,0 +1,44 @@
+package upgrade
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Plan - a software upgrade which the chain halts for at Height, unless the
+// running binary has an upgrade handler registered under Name
+type Plan struct {
+	Name   string `json:"name"`   // name of the upgrade, matched against the registered upgrade handlers
+	Height int64  `json:"height"` // height of the first block to be processed by the upgraded binary
+	Info   string `json:"info"`   // any information for the node operators, e.g. where to get the new binary
+}
+
+// NewPlan creates an upgrade plan
+func NewPlan(name string, height int64, info string) Plan {
+	return Plan{
+		Name:   name,
+		Height: height,
+		Info:   info,
+	}
+}
+
+// ValidateBasic performs the stateless checks of the plan
+func (p Plan) ValidateBasic() sdk.Error {
+	if len(p.Name) == 0 {
+		return ErrInvalidPlan(DefaultCodespace, "upgrade name cannot be empty")
+	}
+	if p.Height <= 0 {
+		return ErrInvalidPlan(DefaultCodespace, "upgrade height must be positive")
+	}
+	return nil
+}
+
+// IsEmpty checks if no field of the plan is set
+func (p Plan) IsEmpty() bool {
+	return p == Plan{}
+}
+
+func (p Plan) String() string {
+	return fmt.Sprintf("Upgrade Plan\n  Name: %s\n  Height: %d\n  Info: %s", p.Name, p.Height, p.Info)
+}
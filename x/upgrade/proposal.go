@@ -0,0 +1,67 @@
+package upgrade
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// GovRoute - the route of the governance proposal contents of the module,
+// making SoftwareUpgrade proposals
+const GovRoute = gov.SoftwareUpgradeRoute
+
+// SoftwareUpgradeProposal - the content of a governance proposal scheduling
+// an upgrade plan once it passes
+type SoftwareUpgradeProposal struct {
+	Plan Plan `json:"plan"`
+}
+
+// NewSoftwareUpgradeProposal creates the content of a software upgrade proposal
+func NewSoftwareUpgradeProposal(plan Plan) SoftwareUpgradeProposal {
+	return SoftwareUpgradeProposal{
+		Plan: plan,
+	}
+}
+
+var _ gov.ProposalContent = SoftwareUpgradeProposal{}
+
+// nolint
+func (p SoftwareUpgradeProposal) ProposalRoute() string    { return GovRoute }
+func (p SoftwareUpgradeProposal) ValidateBasic() sdk.Error { return p.Plan.ValidateBasic() }
+func (p SoftwareUpgradeProposal) String() string           { return p.Plan.String() }
+
+// NewProposalHandler returns the handler of the software upgrade proposals,
+// registered by the app on the gov proposal router under GovRoute
+func NewProposalHandler(k Keeper) gov.ProposalHandler {
+	return proposalHandler{k}
+}
+
+type proposalHandler struct {
+	k Keeper
+}
+
+// the plan must still be schedulable once the proposal passes
+func (h proposalHandler) CheckProposal(ctx sdk.Context, content gov.ProposalContent) sdk.Error {
+	switch content := content.(type) {
+	case SoftwareUpgradeProposal:
+		if content.Plan.Height <= ctx.BlockHeight() {
+			return ErrInvalidPlan(h.k.codespace, fmt.Sprintf("upgrade height %d has already been reached", content.Plan.Height))
+		}
+		if h.k.GetDoneHeight(ctx, content.Plan.Name) != 0 {
+			return ErrUpgradeDone(h.k.codespace, content.Plan.Name)
+		}
+		return nil
+	default:
+		return sdk.ErrUnknownRequest("Unrecognized upgrade proposal content")
+	}
+}
+
+func (h proposalHandler) ExecuteProposal(ctx sdk.Context, content gov.ProposalContent) sdk.Error {
+	switch content := content.(type) {
+	case SoftwareUpgradeProposal:
+		return h.k.ScheduleUpgrade(ctx, content.Plan)
+	default:
+		return sdk.ErrUnknownRequest("Unrecognized upgrade proposal content")
+	}
+}
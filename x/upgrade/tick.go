@@ -0,0 +1,59 @@
+package upgrade
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHaltHook returns the halt hook of the app, stopping the node before the
+// height of the scheduled upgrade unless the binary has an upgrade handler
+// for it, so that node operators can swap in the upgraded binary which then
+// processes that block. A binary shipping the handler keeps processing the
+// earlier blocks, which follow the same rules, and only logs the upcoming
+// upgrade
+func NewHaltHook(k Keeper) sdk.HaltHook {
+	return func(ctx sdk.Context, height int64) (reason string) {
+		plan, found := k.GetUpgradePlan(ctx)
+		if !found {
+			return ""
+		}
+		_, ok := k.upgradeHandlers[plan.Name]
+
+		if height < plan.Height {
+			if ok {
+				ctx.Logger().With("module", "x/upgrade").Info(
+					fmt.Sprintf("upgrade \"%s\" is scheduled at height %d", plan.Name, plan.Height))
+			}
+			return ""
+		}
+		if !ok {
+			return fmt.Sprintf("UPGRADE \"%s\" NEEDED at height %d: %s", plan.Name, plan.Height, plan.Info)
+		}
+		return ""
+	}
+}
+
+// BeginBlocker applies the scheduled upgrade once its height is reached. The
+// halt hook has stopped the node before if the binary has no handler for the
+// upgrade. It must be the first BeginBlocker of the app.
+func BeginBlocker(ctx sdk.Context, k Keeper) (tags sdk.Tags) {
+	plan, found := k.GetUpgradePlan(ctx)
+	if !found || ctx.BlockHeight() < plan.Height {
+		return
+	}
+	handler, ok := k.upgradeHandlers[plan.Name]
+	if !ok {
+		return
+	}
+
+	ctx.Logger().With("module", "x/upgrade").Info(fmt.Sprintf("applying upgrade \"%s\" at height %d", plan.Name, ctx.BlockHeight()))
+	handler(ctx, plan)
+	k.ClearUpgradePlan(ctx)
+	k.setDone(ctx, plan.Name)
+
+	return sdk.NewTags(
+		"action", []byte("upgradeApplied"),
+		"upgrade", []byte(plan.Name),
+	)
+}
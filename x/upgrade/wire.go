@@ -0,0 +1,15 @@
+package upgrade
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// Register concrete types on wire codec
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(SoftwareUpgradeProposal{}, "upgrade/SoftwareUpgradeProposal", nil)
+}
+
+func init() {
+	gov.RegisterProposalContent(SoftwareUpgradeProposal{}, "upgrade/SoftwareUpgradeProposal")
+}